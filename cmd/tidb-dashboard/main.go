@@ -15,13 +15,17 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	_ "net/http/pprof" // #nosec
+	"net/url"
 	"os"
 	"os/signal"
 	"path"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
@@ -67,12 +71,44 @@ func NewCLIConfig() *DashboardCLIConfig {
 	flag.BoolVar(&cfg.CoreConfig.EnableExperimental, "experimental", cfg.CoreConfig.EnableExperimental, "allow experimental features")
 	flag.StringVar(&cfg.CoreConfig.FeatureVersion, "feature-version", cfg.CoreConfig.FeatureVersion, "target TiDB version for standalone mode")
 
+	profilingEncryptionKeyFiles := flag.StringToString("profiling-encryption-key-files", nil, "paths of files that contain hex encoded AES keys to encrypt profiling results, indexed by key ID, e.g. k1=/path/to/k1.key")
+	flag.StringVar(&cfg.CoreConfig.ProfilingEncryptionKeyID, "profiling-encryption-key-id", "", "ID of the key to encrypt new profiling results, which are not encrypted if it is empty")
+	flag.DurationVar(&cfg.CoreConfig.ProfilingRetention, "profiling-retention", cfg.CoreConfig.ProfilingRetention, "profiling results older than the retention are deleted automatically, which are kept forever if it is 0")
+	flag.Int64Var(&cfg.CoreConfig.ProfilingBandwidthLimit, "profiling-bandwidth-limit", 0, "maximum bytes per second of all profile downloads in total, which are not limited if it is 0")
+	flag.DurationVar(&cfg.CoreConfig.ProfilingRequestTimeoutSlack, "profiling-request-timeout-slack", 0, "time allowed for fetching a profile beyond the profile duration, 30s if it is 0")
+	flag.UintVar(&cfg.CoreConfig.ProfilingMaxDurationSecs, "profiling-max-duration-secs", 0, "maximum duration of a profiling request in seconds, 120 if it is 0")
+	flag.IntVar(&cfg.CoreConfig.ProfilingFetchMaxRetries, "profiling-fetch-max-retries", 0, "maximum times of retrying a profile fetch failed due to a connection error, 2 if it is 0, and not retried if it is negative")
+	flag.StringVar(&cfg.CoreConfig.ProfilingResultDir, "profiling-result-dir", "", "path to the directory to store profiling results, the temporary directory of the OS if it is empty")
+	profilingS3 := &config.ProfilingS3Config{}
+	flag.StringVar(&profilingS3.Endpoint, "profiling-s3-endpoint", "", "endpoint of the S3 compatible object storage to store profiling results, e.g. https://s3.us-east-1.amazonaws.com")
+	flag.StringVar(&profilingS3.Region, "profiling-s3-region", "", "region of the S3 compatible object storage, us-east-1 if it is empty")
+	flag.StringVar(&profilingS3.Bucket, "profiling-s3-bucket", "", "bucket to store profiling results, which are stored locally if it is empty. The credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	flag.StringVar(&profilingS3.Prefix, "profiling-s3-prefix", "", "prefix of the object keys of profiling results, e.g. dashboard/profiling/")
+	flag.StringToStringVar(&cfg.CoreConfig.ProfilingPprofPathPrefixes, "profiling-pprof-path-prefixes", nil, "paths under which pprof handlers are served by kinds of components deployed with a non-default path, e.g. tidb=/custom/pprof")
+	flag.DurationVar(&cfg.CoreConfig.ProfilingTargetsCacheTTL, "profiling-targets-cache-ttl", 0, "time for which the listed profiling targets are cached, 5s if it is 0, and not cached if it is negative")
+	flag.StringVar(&cfg.CoreConfig.ProfilingCompressionCodec, "profiling-compression-codec", "", "codec to compress new profiling results, which is gzip, zstd or none, gzip if it is empty")
+	profilingAuthorizationFiles := flag.StringToString("profiling-authorization-files", nil, "paths of files that contain the Authorization headers sent with profiling requests to kinds of components, e.g. tikv=/path/to/tikv.auth")
+	flag.Int64Var(&cfg.CoreConfig.ProfilingStorageBudget, "profiling-storage-budget", 0, "total bytes of stored profiling results, above which the oldest task groups are deleted, which is not limited if it is 0")
+	flag.IntVar(&cfg.CoreConfig.ProfilingMaxRunningGroups, "profiling-max-running-groups", 0, "maximum number of profiling task groups running at the same time, 32 if it is 0, and not limited if it is negative")
+	profilingProxy := flag.String("profiling-proxy", "", "URL of the HTTP proxy through which profiles are fetched, e.g. http://proxy:3128, which are fetched directly if it is empty")
+	profilingProxies := flag.StringToString("profiling-proxies", nil, "URLs of the HTTP proxies through which profiles of kinds of components are fetched, overriding --profiling-proxy, e.g. tikv=http://proxy:3128")
+
 	showVersion := flag.BoolP("version", "v", false, "print version information and exit")
 
 	clusterCaPath := flag.String("cluster-ca", "", "path of file that contains list of trusted SSL CAs")
 	clusterCertPath := flag.String("cluster-cert", "", "path of file that contains X509 certificate in PEM format")
 	clusterKeyPath := flag.String("cluster-key", "", "path of file that contains X509 key in PEM format")
 
+	// TLS configs for kinds of components signed by different CAs, which override the cluster TLS config
+	componentTLSPaths := make(map[string]tlsPaths)
+	for _, kind := range []string{"tidb", "tikv", "pd", "tiflash"} {
+		componentTLSPaths[kind] = tlsPaths{
+			caPath:   flag.String("cluster-"+kind+"-ca", "", "path of file that contains list of trusted SSL CAs of "+kind+", overriding --cluster-ca"),
+			certPath: flag.String("cluster-"+kind+"-cert", "", "path of file that contains X509 certificate of "+kind+" in PEM format, overriding --cluster-cert"),
+			keyPath:  flag.String("cluster-"+kind+"-key", "", "path of file that contains X509 key of "+kind+" in PEM format, overriding --cluster-key"),
+		}
+	}
+
 	tidbCaPath := flag.String("tidb-ca", "", "path of file that contains list of trusted SSL CAs")
 	tidbCertPath := flag.String("tidb-cert", "", "path of file that contains X509 certificate in PEM format")
 	tidbKeyPath := flag.String("tidb-key", "", "path of file that contains X509 key in PEM format")
@@ -96,6 +132,15 @@ func NewCLIConfig() *DashboardCLIConfig {
 	if len(*clusterCaPath) != 0 && len(*clusterCertPath) != 0 && len(*clusterKeyPath) != 0 {
 		cfg.CoreConfig.ClusterTLSConfig = buildTLSConfig(clusterCaPath, clusterKeyPath, clusterCertPath)
 	}
+	for kind, paths := range componentTLSPaths {
+		if len(*paths.caPath) == 0 || len(*paths.certPath) == 0 || len(*paths.keyPath) == 0 {
+			continue
+		}
+		if cfg.CoreConfig.ComponentTLSConfigs == nil {
+			cfg.CoreConfig.ComponentTLSConfigs = make(map[string]*tls.Config)
+		}
+		cfg.CoreConfig.ComponentTLSConfigs[kind] = buildTLSConfig(paths.caPath, paths.keyPath, paths.certPath)
+	}
 
 	// setup TLS config for MySQL client
 	// See https://github.com/pingcap/docs/blob/7a62321b3ce9318cbda8697503c920b2a01aeb3d/how-to/secure/enable-tls-clients.md#enable-authentication
@@ -103,6 +148,28 @@ func NewCLIConfig() *DashboardCLIConfig {
 		cfg.CoreConfig.TiDBTLSConfig = buildTLSConfig(tidbCaPath, tidbKeyPath, tidbCertPath)
 	}
 
+	if len(*profilingEncryptionKeyFiles) > 0 {
+		cfg.CoreConfig.ProfilingEncryptionKeys = loadEncryptionKeys(*profilingEncryptionKeyFiles)
+	}
+
+	if len(*profilingAuthorizationFiles) > 0 {
+		cfg.CoreConfig.ProfilingAuthorizations = loadAuthorizations(*profilingAuthorizationFiles)
+	}
+	if *profilingProxy != "" {
+		cfg.CoreConfig.ProfilingProxyURL = parseProxyURL(*profilingProxy)
+	}
+	if len(*profilingProxies) > 0 {
+		cfg.CoreConfig.ProfilingProxyURLs = make(map[string]*url.URL, len(*profilingProxies))
+		for kind, proxy := range *profilingProxies {
+			cfg.CoreConfig.ProfilingProxyURLs[kind] = parseProxyURL(proxy)
+		}
+	}
+	if profilingS3.Bucket != "" {
+		profilingS3.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		profilingS3.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.CoreConfig.ProfilingS3 = profilingS3
+	}
+
 	if err := cfg.CoreConfig.NormalizePDEndPoint(); err != nil {
 		log.Fatal("Invalid PD Endpoint", zap.Error(err))
 	}
@@ -135,6 +202,12 @@ func getContext() context.Context {
 	return ctx
 }
 
+type tlsPaths struct {
+	caPath   *string
+	certPath *string
+	keyPath  *string
+}
+
 func buildTLSConfig(caPath, keyPath, certPath *string) *tls.Config {
 	tlsInfo := transport.TLSInfo{
 		TrustedCAFile: *caPath,
@@ -148,6 +221,44 @@ func buildTLSConfig(caPath, keyPath, certPath *string) *tls.Config {
 	return tlsConfig
 }
 
+// loadEncryptionKeys reads hex encoded keys from files, indexed by key ID.
+func loadEncryptionKeys(paths map[string]string) map[string][]byte {
+	keys := make(map[string][]byte, len(paths))
+	for keyID, keyPath := range paths {
+		content, err := ioutil.ReadFile(filepath.Clean(keyPath))
+		if err != nil {
+			log.Fatal("Failed to load encryption key", zap.String("key_id", keyID), zap.Error(err))
+		}
+		key, err := hex.DecodeString(strings.TrimSpace(string(content)))
+		if err != nil {
+			log.Fatal("Invalid encryption key, expect a hex encoded AES key", zap.String("key_id", keyID), zap.Error(err))
+		}
+		keys[keyID] = key
+	}
+	return keys
+}
+
+// loadAuthorizations reads Authorization headers from files, indexed by kinds of components.
+func loadAuthorizations(paths map[string]string) map[string]string {
+	authorizations := make(map[string]string, len(paths))
+	for kind, authPath := range paths {
+		content, err := ioutil.ReadFile(filepath.Clean(authPath))
+		if err != nil {
+			log.Fatal("Failed to load authorization header", zap.String("kind", kind), zap.Error(err))
+		}
+		authorizations[kind] = strings.TrimSpace(string(content))
+	}
+	return authorizations
+}
+
+func parseProxyURL(proxy string) *url.URL {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+		log.Fatal("Invalid proxy URL", zap.String("proxy", proxy), zap.Error(err))
+	}
+	return proxyURL
+}
+
 const (
 	distroResFolderName      string = "distro-res"
 	distroStringsResFileName string = "strings.json"
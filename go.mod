@@ -30,6 +30,7 @@ require (
 	github.com/joho/godotenv v1.4.0
 	github.com/joomcode/errorx v1.0.1
 	github.com/json-iterator/go v1.1.12
+	github.com/klauspost/compress v1.11.13
 	github.com/minio/sio v0.3.0
 	github.com/oleiade/reflections v1.0.1
 	github.com/ozonru/etcd/v3 v3.3.0-rc.0-grpc1.30.0
@@ -38,6 +39,7 @@ require (
 	github.com/pingcap/kvproto v0.0.0-20200411081810-b85805c9476c
 	github.com/pingcap/log v0.0.0-20210906054005-afc726e70354
 	github.com/pingcap/tipb v0.0.0-20220718022156-3e2483c20a9e
+	github.com/prometheus/client_golang v1.0.0
 	github.com/rs/cors v1.7.0
 	github.com/shhdgit/testfixtures/v3 v3.6.2-0.20211219171712-c4f264d673d3
 	github.com/shurcooL/httpgzip v0.0.0-20190720172056-320755c1c1b0
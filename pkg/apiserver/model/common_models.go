@@ -14,10 +14,15 @@ const (
 	NodeKindTiKV    NodeKind = "tikv"
 	NodeKindPD      NodeKind = "pd"
 	NodeKindTiFlash NodeKind = "tiflash"
+	NodeKindTiProxy NodeKind = "tiproxy"
+	NodeKindTiCDC   NodeKind = "ticdc"
+	// PD micro-services, which are deployed separately from PD.
+	NodeKindPDTSO        NodeKind = "tso"
+	NodeKindPDScheduling NodeKind = "scheduling"
 )
 
 type RequestTargetNode struct {
-	Kind        NodeKind `json:"kind" gorm:"size:8" example:"tidb"`
+	Kind        NodeKind `json:"kind" gorm:"size:16" example:"tidb"`
 	DisplayName string   `json:"display_name" gorm:"size:32" example:"127.0.0.1:4000"`
 	IP          string   `json:"ip" gorm:"size:32" example:"127.0.0.1"`
 	Port        int      `json:"port" example:"4000"`
@@ -37,6 +42,11 @@ type RequestTargetStatistics struct {
 	NumTiDBNodes    int `json:"num_tidb_nodes"`
 	NumPDNodes      int `json:"num_pd_nodes"`
 	NumTiFlashNodes int `json:"num_tiflash_nodes"`
+	NumTiProxyNodes int `json:"num_tiproxy_nodes"`
+	NumTiCDCNodes   int `json:"num_ticdc_nodes"`
+
+	NumPDTSONodes        int `json:"num_pd_tso_nodes"`
+	NumPDSchedulingNodes int `json:"num_pd_scheduling_nodes"`
 }
 
 func NewRequestTargetStatisticsFromArray(arr *[]RequestTargetNode) RequestTargetStatistics {
@@ -51,6 +61,14 @@ func NewRequestTargetStatisticsFromArray(arr *[]RequestTargetNode) RequestTarget
 			stats.NumPDNodes++
 		case NodeKindTiFlash:
 			stats.NumTiFlashNodes++
+		case NodeKindTiProxy:
+			stats.NumTiProxyNodes++
+		case NodeKindTiCDC:
+			stats.NumTiCDCNodes++
+		case NodeKindPDTSO:
+			stats.NumPDTSONodes++
+		case NodeKindPDScheduling:
+			stats.NumPDSchedulingNodes++
 		}
 	}
 	return stats
@@ -0,0 +1,96 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/google/pprof/profile"
+)
+
+// analyzeConcurrency is the maximum number of profiles being parsed or analyzed at the same time.
+var analyzeConcurrency = runtime.NumCPU()
+
+func parseProtobufProfile(content []byte) (*profile.Profile, error) {
+	p, err := profile.ParseData(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile: %v", err)
+	}
+	return p, nil
+}
+
+// defaultSampleIndex returns the sample value index which pprof displays by default,
+// i.e. the one named by DefaultSampleType or the last one.
+func defaultSampleIndex(p *profile.Profile) int {
+	if p.DefaultSampleType != "" {
+		if idx, err := p.SampleIndexByName(p.DefaultSampleType); err == nil {
+			return idx
+		}
+	}
+	return len(p.SampleType) - 1
+}
+
+// flatByFunction sums the flat value of each function, which is the value of samples whose
+// leaf frame is the function. The total value of all samples is returned as well.
+func flatByFunction(p *profile.Profile, sampleIndex int) (map[string]int64, int64) {
+	flat := make(map[string]int64)
+	var total int64
+	for _, s := range p.Sample {
+		if sampleIndex < 0 || sampleIndex >= len(s.Value) {
+			continue
+		}
+		v := s.Value[sampleIndex]
+		total += v
+		flat[leafFunctionName(s)] += v
+	}
+	return flat, total
+}
+
+func leafFunctionName(s *profile.Sample) string {
+	if len(s.Location) == 0 {
+		return "<unknown>"
+	}
+	loc := s.Location[0]
+	if len(loc.Line) == 0 || loc.Line[0].Function == nil {
+		return fmt.Sprintf("0x%x", loc.Address)
+	}
+	// The first line is the innermost inlined function.
+	return loc.Line[0].Function.Name
+}
+
+// forEachParallel calls fn for each index in [0, n) using at most `workers` goroutines.
+// To keep results deterministic, fn should write its result into the slot of its index.
+// The first error is returned after all calls finish.
+func forEachParallel(n int, workers int, fn func(i int) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	indexCh := make(chan int)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexCh <- i
+	}
+	close(indexCh)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
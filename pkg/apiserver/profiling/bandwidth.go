@@ -0,0 +1,128 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-dashboard/pkg/httpc"
+)
+
+// bandwidthLimiter is a token bucket of bytes shared by all profile downloads. Tokens are refilled at `rate`
+// bytes per second and at most `burst` tokens are accumulated.
+type bandwidthLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBandwidthLimiter(bytesPerSec int64) *bandwidthLimiter {
+	return &bandwidthLimiter{
+		rate:   float64(bytesPerSec),
+		burst:  float64(bytesPerSec),
+		tokens: float64(bytesPerSec),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes n tokens from the bucket and returns how long the caller should wait before the tokens are
+// available. The bucket may go into debt so that a request larger than the burst still completes.
+func (l *bandwidthLimiter) reserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// wait blocks until n bytes are allowed by the limiter or the context is done.
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	delay := l.reserve(n)
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// limitedReadSize is the maximum bytes read from a response body at once, so that a single read does not take
+// much more than the limit allows.
+const limitedReadSize = 32 << 10
+
+// limitedFetcher throttles the downloads of profiles by a shared bandwidthLimiter. The bytes are charged as they
+// are read from the response bodies, so the downloads are slowed down instead of failed when the limit is
+// exceeded, and the aggregate throughput of concurrent downloads is kept under the limit.
+type limitedFetcher struct {
+	profileFetcher
+	limiter *bandwidthLimiter
+}
+
+func (f *limitedFetcher) unwrap() profileFetcher {
+	return f.profileFetcher
+}
+
+func (f *limitedFetcher) fetch(op *fetchOptions) ([]byte, error) {
+	limitedOp := *op
+	limitedOp.limiter = f.limiter
+	return f.profileFetcher.fetch(&limitedOp)
+}
+
+// limitedBody is a response body whose reads wait for the bandwidthLimiter.
+type limitedBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	limiter *bandwidthLimiter
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if len(p) > limitedReadSize {
+		p = p[:limitedReadSize]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := b.limiter.wait(b.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// limitBody throttles reading the body of a response by the bandwidth limiter of the fetch, if it is set.
+func (op *fetchOptions) limitBody(res *httpc.Response) *httpc.Response {
+	if op.limiter == nil {
+		return res
+	}
+	ctx := op.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	res.Response.Body = &limitedBody{ReadCloser: res.Response.Body, ctx: ctx, limiter: op.limiter}
+	return res
+}
+
+// limited returns fetchers whose downloads share a bandwidth limit of bytesPerSec in total.
+func (fts *fetchers) limited(bytesPerSec int64) *fetchers {
+	limiter := newBandwidthLimiter(bytesPerSec)
+	return fts.wrap(func(f profileFetcher) profileFetcher {
+		return &limitedFetcher{profileFetcher: f, limiter: limiter}
+	})
+}
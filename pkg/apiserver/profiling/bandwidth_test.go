@@ -0,0 +1,100 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+	"github.com/pingcap/tidb-dashboard/pkg/httpc"
+	"github.com/pingcap/tidb-dashboard/pkg/pd"
+)
+
+// newTestLimitedPDFetcher returns a PD fetcher limited to bytesPerSec, which fetches profiles of profileSize bytes
+// from a real HTTP server, and the port of the server.
+func newTestLimitedPDFetcher(t *testing.T, bytesPerSec int64, profileSize int) (profileFetcher, int) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(profileSize))
+		_, _ = w.Write(make([]byte, profileSize))
+	}))
+	t.Cleanup(server.Close)
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	cfg := &config.Config{ProfilingBandwidthLimit: bytesPerSec, ProfilingFetchMaxRetries: -1}
+	lc := fxtest.NewLifecycle(t)
+	fts := buildFetchers(lc, nil, nil, pd.NewPDClient(lc, httpc.NewHTTPClient(lc, cfg), cfg), nil, cfg)
+	require.NoError(t, lc.Start(context.Background()))
+	t.Cleanup(lc.RequireStop)
+	return fts.pd, portNum
+}
+
+func TestLimitedFetchersThroughput(t *testing.T) {
+	const (
+		limit       = 1 << 20 // 1 MiB/s
+		profileSize = 512 << 10
+		numProfiles = 4
+	)
+	f, port := newTestLimitedPDFetcher(t, limit, profileSize)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make([]error, numProfiles)
+	partials := make([]*partialBuffer, numProfiles)
+	for i := 0; i < numProfiles; i++ {
+		partials[i] = &partialBuffer{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data, err := f.fetch(&fetchOptions{ip: "127.0.0.1", port: port, path: "/debug/pprof/heap", partial: partials[i]})
+			if err == nil && len(data) != profileSize {
+				err = fmt.Errorf("unexpected profile size %d", len(data))
+			}
+			errs[i] = err
+		}(i)
+	}
+
+	// The bytes are throttled while they are received, rather than charged after the bodies are fully read.
+	time.Sleep(300 * time.Millisecond)
+	received := 0
+	for _, partial := range partials {
+		received += len(partial.bytes())
+	}
+	require.Less(t, received, numProfiles*profileSize)
+	require.LessOrEqual(t, float64(received), limit*1.5)
+
+	wg.Wait()
+	elapsed := time.Since(start)
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	// 2 MiB are downloaded in total. The first 1 MiB is allowed by the burst, so it takes at least 1s.
+	totalBytes := float64(profileSize * numProfiles)
+	require.GreaterOrEqual(t, elapsed.Seconds(), 0.9)
+	require.LessOrEqual(t, (totalBytes-limit)/elapsed.Seconds(), float64(limit)*1.05)
+}
+
+func TestLimitedFetcherCancel(t *testing.T) {
+	f, port := newTestLimitedPDFetcher(t, 1<<10, 64<<10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := f.fetch(&fetchOptions{ctx: ctx, ip: "127.0.0.1", port: port, path: "/debug/pprof/profile"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+}
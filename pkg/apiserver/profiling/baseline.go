@@ -0,0 +1,200 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+const (
+	// DefaultRegressionThreshold is the minimum growth of a function's share of the total
+	// samples to be reported as a regression, e.g. 0.05 means 5 percentage points.
+	DefaultRegressionThreshold = 0.05
+)
+
+var (
+	ErrBaselineNotFound = ErrNS.NewType("baseline_not_found")
+	ErrGroupPinned      = ErrNS.NewType("group_pinned")
+)
+
+// BaselineModel is a "known good" profile which later profiles can be compared with.
+// The profile content is not copied. It is the result of the task the baseline is registered from, which is read
+// like other results, e.g. decrypted if it is encrypted at rest.
+type BaselineModel struct {
+	Name          string                  `json:"name" gorm:"primary_key;size:128"`
+	TaskID        uint                    `json:"task_id" gorm:"index"`
+	Target        model.RequestTargetNode `json:"target" gorm:"embedded;embedded_prefix:target_"`
+	ProfilingType TaskProfilingType       `json:"profiling_type"`
+	CreatedAt     int64                   `json:"created_at"`
+}
+
+func (BaselineModel) TableName() string {
+	return "profiling_baselines"
+}
+
+// pinnedGroupIDs is a subquery of the task groups with results registered as baselines. These results are pinned,
+// i.e. they are neither deleted by the janitor or to make room for new results, nor replaced by refreshing the task.
+func (s *Service) pinnedGroupIDs() *gorm.DB {
+	return s.params.LocalStore.Model(&TaskModel{}).
+		Select("task_group_id").
+		Where("id IN (?)", s.params.LocalStore.Model(&BaselineModel{}).Select("task_id"))
+}
+
+// baselinesOfTask returns the names of the baselines registered from the result of a task.
+func (s *Service) baselinesOfTask(taskID uint) ([]string, error) {
+	var names []string
+	err := s.params.LocalStore.Model(&BaselineModel{}).Where("task_id = ?", taskID).Order("name ASC").Pluck("name", &names).Error
+	return names, err
+}
+
+type BaselineDiffEntry struct {
+	Function string `json:"function"`
+	// Share of the total samples in the baseline / current profile, in range [0, 1].
+	BaselineShare float64 `json:"baseline_share"`
+	CurrentShare  float64 `json:"current_share"`
+	Delta         float64 `json:"delta"`
+}
+
+type BaselineCompareResult struct {
+	Baseline    string              `json:"baseline"`
+	TaskID      uint                `json:"task_id"`
+	Threshold   float64             `json:"threshold"`
+	Diff        []BaselineDiffEntry `json:"diff"`
+	Regressions []BaselineDiffEntry `json:"regressions"`
+}
+
+func (s *Service) registerBaseline(name string, taskID uint) (*BaselineModel, error) {
+	var task TaskModel
+	if err := s.params.LocalStore.Where("id = ? AND state = ?", taskID, TaskStateFinish).First(&task).Error; err != nil {
+		return nil, err
+	}
+	if task.RawDataType != RawDataTypeProtobuf {
+		return nil, ErrUnsupportedProfilingType.New("only protobuf profiles can be used as a baseline")
+	}
+	content, err := s.results.readResult(&task)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseProtobufProfile(content); err != nil {
+		return nil, err
+	}
+
+	baseline := &BaselineModel{
+		Name:          name,
+		TaskID:        task.ID,
+		Target:        task.Target,
+		ProfilingType: task.ProfilingType,
+		CreatedAt:     time.Now().Unix(),
+	}
+	if err := s.params.LocalStore.Save(baseline).Error; err != nil {
+		return nil, err
+	}
+	return baseline, nil
+}
+
+func (s *Service) compareToBaseline(taskID uint, baselineName string, threshold float64) (*BaselineCompareResult, error) {
+	var baseline BaselineModel
+	if err := s.params.LocalStore.Where("name = ?", baselineName).Find(&baseline).Error; err != nil {
+		return nil, err
+	}
+	if baseline.Name == "" {
+		return nil, ErrBaselineNotFound.New("baseline %s does not exist", baselineName)
+	}
+
+	var task TaskModel
+	if err := s.params.LocalStore.Where("id = ? AND state = ?", taskID, TaskStateFinish).First(&task).Error; err != nil {
+		return nil, err
+	}
+	if task.RawDataType != RawDataTypeProtobuf {
+		return nil, ErrUnsupportedProfilingType.New("only protobuf profiles can be compared with a baseline")
+	}
+	if task.ProfilingType != baseline.ProfilingType {
+		return nil, ErrUnsupportedProfilingType.New("cannot compare a %s profile with a %s baseline", task.ProfilingType, baseline.ProfilingType)
+	}
+	content, err := s.results.readResult(&task)
+	if err != nil {
+		return nil, err
+	}
+	var baselineTask TaskModel
+	if err := s.params.LocalStore.Where("id = ? AND state = ?", baseline.TaskID, TaskStateFinish).Find(&baselineTask).Error; err != nil {
+		return nil, err
+	}
+	if baselineTask.ID == 0 {
+		return nil, ErrBaselineNotFound.New("the result of baseline %s does not exist", baselineName)
+	}
+	baselineContent, err := s.results.readResult(&baselineTask)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := diffProfiles(baselineContent, content)
+	if err != nil {
+		return nil, err
+	}
+	regressions := make([]BaselineDiffEntry, 0)
+	for _, entry := range diff {
+		if entry.Delta > threshold {
+			regressions = append(regressions, entry)
+		}
+	}
+	return &BaselineCompareResult{
+		Baseline:    baselineName,
+		TaskID:      taskID,
+		Threshold:   threshold,
+		Diff:        diff,
+		Regressions: regressions,
+	}, nil
+}
+
+// diffProfiles compares the share of each function's flat value in two profiles.
+// Shares are used instead of the absolute values so that profiles of different durations are comparable.
+// The result is ordered by the delta descending.
+func diffProfiles(baselineContent, currentContent []byte) ([]BaselineDiffEntry, error) {
+	baselineProfile, err := parseProtobufProfile(baselineContent)
+	if err != nil {
+		return nil, err
+	}
+	currentProfile, err := parseProtobufProfile(currentContent)
+	if err != nil {
+		return nil, err
+	}
+	baselineFlat, baselineTotal := flatByFunction(baselineProfile, defaultSampleIndex(baselineProfile))
+	currentFlat, currentTotal := flatByFunction(currentProfile, defaultSampleIndex(currentProfile))
+
+	share := func(v, total int64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(v) / float64(total)
+	}
+
+	functions := make(map[string]struct{}, len(baselineFlat)+len(currentFlat))
+	for fn := range baselineFlat {
+		functions[fn] = struct{}{}
+	}
+	for fn := range currentFlat {
+		functions[fn] = struct{}{}
+	}
+	diff := make([]BaselineDiffEntry, 0, len(functions))
+	for fn := range functions {
+		entry := BaselineDiffEntry{
+			Function:      fn,
+			BaselineShare: share(baselineFlat[fn], baselineTotal),
+			CurrentShare:  share(currentFlat[fn], currentTotal),
+		}
+		entry.Delta = entry.CurrentShare - entry.BaselineShare
+		diff = append(diff, entry)
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		if diff[i].Delta != diff[j].Delta {
+			return diff[i].Delta > diff[j].Delta
+		}
+		return diff[i].Function < diff[j].Function
+	})
+	return diff, nil
+}
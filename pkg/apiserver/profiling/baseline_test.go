@@ -0,0 +1,134 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+)
+
+func TestCompareToBaseline(t *testing.T) {
+	s := newTestService(t)
+
+	baselineTask := newTestFinishedTask(t, s, ProfilingTypeCPU, RawDataTypeProtobuf, newTestCPUProfile(t, map[string]int64{
+		"main.handle": 500e6,
+		"main.encode": 300e6,
+		"main.decode": 200e6,
+	}))
+	_, err := s.registerBaseline("steady", baselineTask.ID)
+	require.NoError(t, err)
+
+	// main.encode grows from 30% to 60% of the total samples.
+	currentTask := newTestFinishedTask(t, s, ProfilingTypeCPU, RawDataTypeProtobuf, newTestCPUProfile(t, map[string]int64{
+		"main.handle": 250e6,
+		"main.encode": 600e6,
+		"main.decode": 150e6,
+	}))
+	result, err := s.compareToBaseline(currentTask.ID, "steady", DefaultRegressionThreshold)
+	require.NoError(t, err)
+	require.Len(t, result.Diff, 3)
+	require.Len(t, result.Regressions, 1)
+	require.Equal(t, "main.encode", result.Regressions[0].Function)
+	require.InDelta(t, 0.3, result.Regressions[0].BaselineShare, 1e-9)
+	require.InDelta(t, 0.6, result.Regressions[0].CurrentShare, 1e-9)
+
+	// The baseline itself never regresses.
+	result, err = s.compareToBaseline(baselineTask.ID, "steady", DefaultRegressionThreshold)
+	require.NoError(t, err)
+	require.Empty(t, result.Regressions)
+
+	_, err = s.compareToBaseline(currentTask.ID, "missing", DefaultRegressionThreshold)
+	require.True(t, errorx.IsOfType(err, ErrBaselineNotFound))
+}
+
+func TestRegisterBaselineRejectsText(t *testing.T) {
+	s := newTestService(t)
+	task := newTestFinishedTask(t, s, ProfilingTypeGoroutine, RawDataTypeText, []byte("goroutine profile: total 1"))
+	_, err := s.registerBaseline("goroutine", task.ID)
+	require.Error(t, err)
+}
+
+func TestCompareToEncryptedBaseline(t *testing.T) {
+	s := newTestService(t)
+	s.results = newTestEncryptedResults(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	content := newTestCPUProfile(t, map[string]int64{"main.handle": 500e6, "main.encode": 500e6})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	req := &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	}
+	baselineTasks, _ := runTestGroup(t, s, req)
+	require.Equal(t, "k1", baselineTasks[0].EncryptionKeyID)
+	_, err := s.registerBaseline("steady", baselineTasks[0].ID)
+	require.NoError(t, err)
+
+	content = newTestCPUProfile(t, map[string]int64{"main.handle": 200e6, "main.encode": 800e6})
+	currentTasks, _ := runTestGroup(t, s, req)
+	result, err := s.compareToBaseline(currentTasks[0].ID, "steady", DefaultRegressionThreshold)
+	require.NoError(t, err)
+	require.Len(t, result.Regressions, 1)
+	require.Equal(t, "main.encode", result.Regressions[0].Function)
+
+	// The baseline refers to the encrypted result instead of keeping a plaintext copy of the profile.
+	var baseline BaselineModel
+	require.NoError(t, s.params.LocalStore.Where("name = ?", "steady").First(&baseline).Error)
+	require.Equal(t, baselineTasks[0].ID, baseline.TaskID)
+	require.False(t, s.params.LocalStore.Migrator().HasColumn(&BaselineModel{}, "data"))
+}
+
+func TestBaselinePinsResult(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	req := &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	}
+	tasks, group := runTestGroup(t, s, req)
+	_, err := s.registerBaseline("steady", tasks[0].ID)
+	require.NoError(t, err)
+	groupExists := func() bool {
+		var count int64
+		require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Where("id = ?", group.ID).Count(&count).Error)
+		return count > 0
+	}
+
+	// The task group is not deleted by the janitor after the retention.
+	s.params.Config = &config.Config{ProfilingRetention: time.Hour, ProfilingStorageBudget: int64(len(content)) * 3 / 2}
+	deleted, err := s.deleteExpiredGroups(time.Now().Add(2 * time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+	require.True(t, groupExists())
+
+	// The task group is not deleted to make room for new results.
+	_, err = s.startGroup(context.Background(), req)
+	require.True(t, errorx.IsOfType(err, ErrStorageBudgetExceeded))
+	require.True(t, groupExists())
+	require.True(t, errorx.IsOfType(s.evictGroup(group.ID), ErrGroupPinned))
+
+	// The result is not replaced by refreshing the task.
+	_, err = s.refreshTask(tasks[0].ID)
+	require.True(t, errorx.IsOfType(err, ErrGroupPinned))
+
+	// The baseline is deleted with the task group when it is deleted by the user.
+	require.NoError(t, s.deleteGroup(group.ID))
+	var count int64
+	require.NoError(t, s.params.LocalStore.Model(&BaselineModel{}).Count(&count).Error)
+	require.Zero(t, count)
+	_, err = os.Stat(tasks[0].FilePath)
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+// buildIDTimeout is the time allowed for fetching the build ID, which is not a profile and should be returned quickly.
+const buildIDTimeout = 10 * time.Second
+
+// buildIDPaths are the status APIs reporting the git hash of a component binary, which identifies the build
+// to match symbols with. TiKV and TiFlash do not expose such an API on their status ports.
+var buildIDPaths = map[model.NodeKind]string{
+	model.NodeKindTiDB: "/status",
+	model.NodeKindPD:   "/pd/api/v1/status",
+}
+
+type buildIDStatus struct {
+	GitHash string `json:"git_hash"`
+}
+
+// fetchBuildID returns the build ID of the target, or an empty string if it is not available.
+func fetchBuildID(ctx context.Context, fts *fetchers, target *model.RequestTargetNode) string {
+	path, ok := buildIDPaths[target.Kind]
+	if !ok {
+		return ""
+	}
+	var fetcher profileFetcher
+	switch target.Kind {
+	case model.NodeKindTiDB:
+		fetcher = fts.tidb
+	case model.NodeKindPD:
+		fetcher = fts.pd
+	}
+	if fetcher == nil {
+		return ""
+	}
+	resp, err := fetcher.fetch(&fetchOptions{ctx: ctx, ip: target.IP, port: target.Port, path: path, timeout: buildIDTimeout})
+	if err != nil {
+		log.Warn("failed to fetch build ID", zap.String("target", target.String()), zap.Error(err))
+		return ""
+	}
+	var status buildIDStatus
+	if err := json.Unmarshal(resp, &status); err != nil {
+		log.Warn("failed to parse build ID", zap.String("target", target.String()), zap.Error(err))
+		return ""
+	}
+	return status.GitHash
+}
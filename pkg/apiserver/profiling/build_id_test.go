@@ -0,0 +1,51 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+func TestCaptureBuildID(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if op.path == "/status" {
+			return []byte(`{"connections":0,"version":"5.7.25-TiDB-v6.1.0","git_hash":"1a89decdb192cbdce6a7b0020d71128bc964d30f"}`), nil
+		}
+		return content, nil
+	}}
+	s.fetchers.pd = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if op.path == "/pd/api/v1/status" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return content, nil
+	}}
+	targets := []model.RequestTargetNode{
+		{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+		{Kind: model.NodeKindPD, DisplayName: "127.0.0.1:2379", IP: "127.0.0.1", Port: 2379},
+	}
+
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                targets,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+		CaptureBuildID:         true,
+	})
+	require.Len(t, tasks, 2)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, "1a89decdb192cbdce6a7b0020d71128bc964d30f", tasks[0].BuildID)
+	// Build ID is captured on a best-effort basis.
+	require.Equal(t, TaskStateFinish, tasks[1].State)
+	require.Empty(t, tasks[1].BuildID)
+
+	tasks, _ = runTestGroup(t, s, &StartRequest{
+		Targets:                targets[:1],
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.Empty(t, tasks[0].BuildID)
+}
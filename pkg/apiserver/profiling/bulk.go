@@ -0,0 +1,68 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+// maxTasksDataIDs is the maximum number of tasks whose results are read in one request, which bounds the size of
+// the response.
+const maxTasksDataIDs = 100
+
+type TasksDataRequest struct {
+	TaskIDs []uint `json:"task_ids"`
+}
+
+type TaskWithData struct {
+	Task TaskModel `json:"task"`
+	// The profiling result, which is empty unless the task is finished.
+	Data []byte `json:"data"`
+}
+
+// tasksData returns the tasks with their profiling results in the requested order, which are loaded in a single
+// query. Tasks which are not finished, e.g. failed or skipped, are returned without data along with their states,
+// so that they do not fail the others.
+func (s *Service) tasksData(req TasksDataRequest) ([]TaskWithData, error) {
+	if len(req.TaskIDs) == 0 {
+		return nil, rest.ErrBadRequest.New("Expect at least 1 task")
+	}
+	if len(req.TaskIDs) > maxTasksDataIDs {
+		return nil, rest.ErrBadRequest.New("at most %d tasks can be read at once", maxTasksDataIDs)
+	}
+	var tasks []TaskModel
+	if err := s.params.LocalStore.Where("id IN ?", req.TaskIDs).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	taskByID := make(map[uint]*TaskModel, len(tasks))
+	for i := range tasks {
+		taskByID[tasks[i].ID] = &tasks[i]
+	}
+	missingIDs := make([]string, 0)
+	for _, id := range req.TaskIDs {
+		if _, ok := taskByID[id]; !ok {
+			missingIDs = append(missingIDs, strconv.FormatUint(uint64(id), 10))
+		}
+	}
+	if len(missingIDs) > 0 {
+		return nil, rest.ErrNotFound.New("tasks do not exist: %s", strings.Join(missingIDs, ", "))
+	}
+
+	result := make([]TaskWithData, 0, len(req.TaskIDs))
+	for _, id := range req.TaskIDs {
+		task := taskByID[id]
+		item := TaskWithData{Task: *task}
+		if task.State == TaskStateFinish {
+			data, err := s.results.readResult(task)
+			if err != nil {
+				return nil, err
+			}
+			item.Data = data
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
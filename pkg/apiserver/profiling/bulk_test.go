@@ -0,0 +1,52 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+func TestTasksData(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if op.ip == "127.0.0.2" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return content, nil
+	}}
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.Len(t, tasks, 2)
+
+	// Tasks are returned in the requested order, and the failed task does not fail the finished one.
+	result, err := s.tasksData(TasksDataRequest{TaskIDs: []uint{tasks[1].ID, tasks[0].ID}})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, tasks[1].ID, result[0].Task.ID)
+	require.Equal(t, TaskStateError, result[0].Task.State)
+	require.Contains(t, result[0].Task.Error, "connection refused")
+	require.Empty(t, result[0].Data)
+	require.Equal(t, tasks[0].ID, result[1].Task.ID)
+	require.Equal(t, TaskStateFinish, result[1].Task.State)
+	require.Equal(t, content, result[1].Data)
+
+	_, err = s.tasksData(TasksDataRequest{TaskIDs: []uint{tasks[0].ID, tasks[1].ID + 1}})
+	require.True(t, errorx.IsOfType(err, rest.ErrNotFound))
+	_, err = s.tasksData(TasksDataRequest{})
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+	_, err = s.tasksData(TasksDataRequest{TaskIDs: make([]uint, maxTasksDataIDs+1)})
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+}
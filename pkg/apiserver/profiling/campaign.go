@@ -0,0 +1,160 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/pprof/profile"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+// CampaignModel repeats the same profiling request several times with a gap, so that the behavior
+// over a longer span can be captured. Each iteration is a task group linked by its CampaignID.
+type CampaignModel struct {
+	ID         uint      `json:"id" gorm:"primary_key"`
+	State      TaskState `json:"state" gorm:"index"`
+	Iterations uint      `json:"iterations"`
+	GapSecs    uint      `json:"gap_secs"`
+	StartedAt  int64     `json:"started_at"`
+	Error      string    `json:"error" gorm:"type:text"`
+}
+
+func (CampaignModel) TableName() string {
+	return "profiling_campaigns"
+}
+
+type StartCampaignRequest struct {
+	StartRequest
+	Iterations uint `json:"iterations"`
+	GapSecs    uint `json:"gap_secs"` // The gap between the end of an iteration and the start of the next one
+}
+
+// CampaignMergedProfile is the merged profile of a target across all iterations of a campaign.
+type CampaignMergedProfile struct {
+	Target        model.RequestTargetNode
+	ProfilingType TaskProfilingType
+	NumProfiles   int
+	Data          []byte // Gzipped protobuf
+}
+
+// startCampaign starts running the iterations of a campaign in the background.
+func (s *Service) startCampaign(ctx context.Context, req *StartCampaignRequest) (*CampaignModel, error) {
+	campaign := &CampaignModel{
+		State:      TaskStateRunning,
+		Iterations: req.Iterations,
+		GapSecs:    req.GapSecs,
+		StartedAt:  time.Now().Unix(),
+	}
+	if err := s.params.LocalStore.Create(campaign).Error; err != nil {
+		return nil, err
+	}
+
+	// Return a copy, since the campaign is updated in the background.
+	resp := *campaign
+	groupReq := req.StartRequest
+	groupReq.campaignID = campaign.ID
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		campaign.State = s.runCampaign(ctx, campaign, &groupReq)
+		s.params.LocalStore.Save(campaign)
+	}()
+	return &resp, nil
+}
+
+func (s *Service) runCampaign(ctx context.Context, campaign *CampaignModel, req *StartRequest) TaskState {
+	for i := uint(0); i < campaign.Iterations; i++ {
+		if i > 0 {
+			timer := time.NewTimer(time.Duration(campaign.GapSecs) * time.Second)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return TaskStateCancelled
+			}
+		}
+		taskGroup, err := s.exclusiveExecute(ctx, req)
+		if err != nil {
+			log.Warn("failed to start campaign iteration", zap.Uint("campaign", campaign.ID), zap.Uint("iteration", i), zap.Error(err))
+			campaign.Error = err.Error()
+			return TaskStateError
+		}
+		select {
+		case <-taskGroup.done:
+		case <-ctx.Done():
+			return TaskStateCancelled
+		}
+	}
+	return TaskStateFinish
+}
+
+// mergeCampaign merges the finished protobuf profiles of each target across all iterations of a campaign.
+func (s *Service) mergeCampaign(campaignID uint, profilingType TaskProfilingType) ([]CampaignMergedProfile, error) {
+	var groupIDs []uint
+	if err := s.params.LocalStore.Model(&TaskGroupModel{}).Where("campaign_id = ?", campaignID).Pluck("id", &groupIDs).Error; err != nil {
+		return nil, err
+	}
+	var tasks []TaskModel
+	err := s.params.LocalStore.
+		Where("task_group_id IN ? AND state = ? AND profiling_type = ? AND raw_data_type = ?", groupIDs, TaskStateFinish, profilingType, RawDataTypeProtobuf).
+		Order("id ASC").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make([]*profile.Profile, len(tasks))
+	err = forEachParallel(len(tasks), analyzeConcurrency, func(i int) error {
+		content, err := s.results.readResult(&tasks[i])
+		if err != nil {
+			return err
+		}
+		parsed[i], err = parseProtobufProfile(content)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byTarget := make(map[string][]int)
+	for i, task := range tasks {
+		key := task.Target.FileName()
+		byTarget[key] = append(byTarget[key], i)
+	}
+	keys := make([]string, 0, len(byTarget))
+	for key := range byTarget {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	merged := make([]CampaignMergedProfile, 0, len(keys))
+	for _, key := range keys {
+		indexes := byTarget[key]
+		profiles := make([]*profile.Profile, 0, len(indexes))
+		for _, i := range indexes {
+			profiles = append(profiles, parsed[i])
+		}
+		p, err := profile.Merge(profiles)
+		if err != nil {
+			return nil, ErrUnsupportedProfilingType.Wrap(err, "failed to merge profiles of %s", key)
+		}
+		buf := bytes.Buffer{}
+		if err := p.Write(&buf); err != nil {
+			return nil, err
+		}
+		merged = append(merged, CampaignMergedProfile{
+			Target:        tasks[indexes[0]].Target,
+			ProfilingType: profilingType,
+			NumProfiles:   len(indexes),
+			Data:          buf.Bytes(),
+		})
+	}
+	return merged, nil
+}
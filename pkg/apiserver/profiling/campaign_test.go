@@ -0,0 +1,68 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+func TestCampaign(t *testing.T) {
+	s := newTestService(t)
+	iterationProfiles := [][]byte{
+		newTestCPUProfile(t, map[string]int64{"main.a": 10000000, "main.b": 20000000}),
+		newTestCPUProfile(t, map[string]int64{"main.a": 30000000}),
+	}
+	var fetched int32
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return iterationProfiles[(atomic.AddInt32(&fetched, 1)-1)%2], nil
+	}}
+	target := model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}
+
+	campaign, err := s.startCampaign(context.Background(), &StartCampaignRequest{
+		StartRequest: StartRequest{
+			Targets:                []model.RequestTargetNode{target},
+			RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+		},
+		Iterations: 2,
+		GapSecs:    0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TaskStateRunning, campaign.State)
+	s.wg.Wait()
+
+	require.NoError(t, s.params.LocalStore.Where("id = ?", campaign.ID).First(campaign).Error)
+	require.Equal(t, TaskStateFinish, campaign.State)
+	var groups []TaskGroupModel
+	require.NoError(t, s.params.LocalStore.Where("campaign_id = ?", campaign.ID).Order("id ASC").Find(&groups).Error)
+	require.Len(t, groups, 2)
+	for _, group := range groups {
+		require.Equal(t, TaskStateFinish, group.State)
+	}
+
+	merged, err := s.mergeCampaign(campaign.ID, ProfilingTypeCPU)
+	require.NoError(t, err)
+	require.Len(t, merged, 1)
+	require.Equal(t, target, merged[0].Target)
+	require.Equal(t, 2, merged[0].NumProfiles)
+	p, err := parseProtobufProfile(merged[0].Data)
+	require.NoError(t, err)
+	flat, total := flatByFunction(p, defaultSampleIndex(p))
+	require.Equal(t, int64(40000000), flat["main.a"])
+	require.Equal(t, int64(20000000), flat["main.b"])
+	require.Equal(t, int64(60000000), total)
+
+	// Task groups started alone are not merged into the campaign.
+	runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{target},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	merged, err = s.mergeCampaign(campaign.ID, ProfilingTypeCPU)
+	require.NoError(t, err)
+	require.Equal(t, 2, merged[0].NumProfiles)
+}
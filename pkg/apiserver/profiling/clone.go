@@ -0,0 +1,105 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"sort"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+type CloneGroupRequest struct {
+	// The duration of the cloned task group. The duration of the original task group is used when it is 0.
+	DurationSecs uint `json:"duration_secs"`
+}
+
+// cloneGroupRequest builds a request which profiles the same targets with the same profiling types as a stopped
+// task group, so that a follow-up measurement can be captured with an identical scope. Targets are checked against
+// the current cluster topology, so that targets which no longer exist are reported instead of being profiled.
+func (s *Service) cloneGroupRequest(taskGroupID uint, req CloneGroupRequest) (*StartRequest, error) {
+	var groupModel TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", taskGroupID).Find(&groupModel).Error; err != nil {
+		return nil, err
+	}
+	if groupModel.ID == 0 {
+		return nil, rest.ErrNotFound.New("task group %d does not exist", taskGroupID)
+	}
+	if groupModel.State == TaskStateRunning {
+		return nil, ErrGroupRunning.New("task group %d is still running", taskGroupID)
+	}
+	var tasks []TaskModel
+	if err := s.params.LocalStore.Where("task_group_id = ?", taskGroupID).Order("id ASC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, ErrIgnoredRequest.New("task group %d has no tasks", taskGroupID)
+	}
+	groups := []TaskGroupModel{groupModel}
+	if err := s.fillGroupLabels(groups); err != nil {
+		return nil, err
+	}
+
+	startReq := &StartRequest{
+		DurationSecs:           req.DurationSecs,
+		RequstedProfilingTypes: groupModel.RequstedProfilingTypes,
+		ProfilingTypesByKind:   make(map[model.NodeKind]TaskProfilingTypeList),
+		CheckTopology:          true,
+		Labels:                 groups[0].Labels,
+	}
+	if startReq.DurationSecs == 0 {
+		startReq.DurationSecs = groupModel.ProfileDurationSecs
+	}
+	durationsByType := make(map[TaskProfilingType][]uint)
+	for _, task := range tasks {
+		// Profiling types of the original tasks are kept for each kind, since they may be overridden by kind.
+		types := startReq.ProfilingTypesByKind[task.Target.Kind]
+		seen := false
+		for _, t := range types {
+			if t == task.ProfilingType {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			startReq.ProfilingTypesByKind[task.Target.Kind] = append(types, task.ProfilingType)
+		}
+		if req.DurationSecs == 0 && task.DurationSecs > 0 && !containsDuration(durationsByType[task.ProfilingType], task.DurationSecs) {
+			durationsByType[task.ProfilingType] = append(durationsByType[task.ProfilingType], task.DurationSecs)
+		}
+		if task.ProfilingType == ProfilingTypeCustom {
+			// The stored path already contains the seconds query.
+			startReq.CustomPprofPath = task.CustomPath
+		}
+		startReq.Targets = append(startReq.Targets, task.Target)
+	}
+	// Durations overridden by profiling types are kept unless a new duration is given, and profiling types captured
+	// with multiple durations are captured with the same durations again.
+	for profilingType, durations := range durationsByType {
+		switch {
+		case len(durations) > 1:
+			for _, durationSecs := range durations {
+				if !containsDuration(startReq.DurationSecsList, durationSecs) {
+					startReq.DurationSecsList = append(startReq.DurationSecsList, durationSecs)
+				}
+			}
+		case durations[0] != groupModel.ProfileDurationSecs:
+			if startReq.DurationSecsByType == nil {
+				startReq.DurationSecsByType = make(map[TaskProfilingType]uint)
+			}
+			startReq.DurationSecsByType[profilingType] = durations[0]
+		}
+	}
+	sort.Slice(startReq.DurationSecsList, func(i, j int) bool { return startReq.DurationSecsList[i] < startReq.DurationSecsList[j] })
+	startReq.Targets = uniqueTargets(startReq.Targets)
+	return startReq, nil
+}
+
+func containsDuration(durations []uint, durationSecs uint) bool {
+	for _, d := range durations {
+		if d == durationSecs {
+			return true
+		}
+	}
+	return false
+}
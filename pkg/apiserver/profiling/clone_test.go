@@ -0,0 +1,87 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/topo"
+)
+
+func TestCloneGroup(t *testing.T) {
+	s := newTestService(t)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return newTestCPUProfile(t, map[string]int64{"main.work": 10000000}), nil
+	}}
+	s.fetchers.tikv = s.fetchers.tidb
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetTiDB", mock.Anything).Return([]topo.TiDBInfo{
+		{IP: "10.0.0.1", Port: 4000, StatusPort: 10080},
+	}, nil)
+	provider.On("GetTiKV", mock.Anything).Return([]topo.TiKVStoreInfo{
+		{IP: "10.0.0.2", Port: 20160, StatusPort: 20180},
+	}, nil)
+	s.topoProvider = provider
+
+	targets := []model.RequestTargetNode{
+		{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.1:4000", IP: "10.0.0.1", Port: 10080},
+		{Kind: model.NodeKindTiKV, DisplayName: "10.0.0.2:20160", IP: "10.0.0.2", Port: 20180},
+	}
+	originalTasks, original := runTestGroup(t, s, &StartRequest{
+		Targets:                targets,
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+		ProfilingTypesByKind:   map[model.NodeKind]TaskProfilingTypeList{model.NodeKindTiDB: {ProfilingTypeCPU, ProfilingTypeGoroutine}},
+		Labels:                 map[string]string{"reason": "incident"},
+	})
+	require.Equal(t, TaskStateFinish, original.State)
+	require.Len(t, originalTasks, 3)
+
+	req, err := s.cloneGroupRequest(original.ID, CloneGroupRequest{DurationSecs: 2})
+	require.NoError(t, err)
+	require.Equal(t, targets, req.Targets)
+	require.Equal(t, uint(2), req.DurationSecs)
+	require.Equal(t, map[string]string{"reason": "incident"}, req.Labels)
+	taskGroup, err := s.exclusiveExecute(context.Background(), req)
+	require.NoError(t, err)
+	s.wg.Wait()
+	require.NotEqual(t, original.ID, taskGroup.ID)
+
+	var cloned []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Order("id ASC").Find(&cloned).Error)
+	require.Len(t, cloned, len(originalTasks))
+	for i := range cloned {
+		require.Equal(t, originalTasks[i].Target, cloned[i].Target)
+		require.Equal(t, originalTasks[i].ProfilingType, cloned[i].ProfilingType)
+		require.Equal(t, TaskStateFinish, cloned[i].State)
+	}
+
+	// The original task group is untouched.
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", original.ID).Order("id ASC").Find(&tasks).Error)
+	require.Equal(t, originalTasks, tasks)
+
+	// The duration of the original task group is used by default.
+	req, err = s.cloneGroupRequest(original.ID, CloneGroupRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint(1), req.DurationSecs)
+
+	// Targets which no longer exist are reported.
+	provider.ExpectedCalls = nil
+	provider.On("GetTiDB", mock.Anything).Return([]topo.TiDBInfo{}, nil)
+	provider.On("GetTiKV", mock.Anything).Return([]topo.TiKVStoreInfo{
+		{IP: "10.0.0.2", Port: 20160, StatusPort: 20180},
+	}, nil)
+	_, err = s.exclusiveExecute(context.Background(), req)
+	require.True(t, errorx.IsOfType(err, ErrTargetNotInTopology))
+	require.Contains(t, err.Error(), "tidb(10.0.0.1:4000)")
+
+	_, err = s.cloneGroupRequest(original.ID+100, CloneGroupRequest{})
+	require.Error(t, err)
+}
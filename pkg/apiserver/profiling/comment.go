@@ -0,0 +1,50 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"time"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+// TaskGroupCommentModel is an append-only comment left on a task group, e.g. during an incident analysis.
+type TaskGroupCommentModel struct {
+	ID          uint   `json:"id" gorm:"primary_key"`
+	TaskGroupID uint   `json:"task_group_id" gorm:"index"`
+	Author      string `json:"author"`
+	Content     string `json:"content" gorm:"type:text"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+func (TaskGroupCommentModel) TableName() string {
+	return "profiling_task_group_comments"
+}
+
+func (s *Service) addGroupComment(taskGroupID uint, author string, content string) (*TaskGroupCommentModel, error) {
+	var count int64
+	if err := s.params.LocalStore.Model(&TaskGroupModel{}).Where("id = ?", taskGroupID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, rest.ErrNotFound.New("task group %d does not exist", taskGroupID)
+	}
+
+	comment := &TaskGroupCommentModel{
+		TaskGroupID: taskGroupID,
+		Author:      author,
+		Content:     content,
+		CreatedAt:   time.Now().Unix(),
+	}
+	if err := s.params.LocalStore.Create(comment).Error; err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// listGroupComments returns comments of a task group in the order they are added.
+func (s *Service) listGroupComments(taskGroupID uint) ([]TaskGroupCommentModel, error) {
+	comments := make([]TaskGroupCommentModel, 0)
+	err := s.params.LocalStore.Where("task_group_id = ?", taskGroupID).Order("id ASC").Find(&comments).Error
+	return comments, err
+}
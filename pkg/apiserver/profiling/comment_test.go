@@ -0,0 +1,36 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+func TestGroupComments(t *testing.T) {
+	s := newTestService(t)
+	group := &TaskGroupModel{State: TaskStateFinish}
+	require.NoError(t, s.params.LocalStore.Create(group).Error)
+
+	_, err := s.addGroupComment(group.ID, "alice", "CPU is saturated by the coprocessor")
+	require.NoError(t, err)
+	_, err = s.addGroupComment(group.ID, "bob", "Looks like a hot region")
+	require.NoError(t, err)
+
+	comments, err := s.listGroupComments(group.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	require.Equal(t, "alice", comments[0].Author)
+	require.Equal(t, "CPU is saturated by the coprocessor", comments[0].Content)
+	require.Equal(t, "bob", comments[1].Author)
+	require.Equal(t, "Looks like a hot region", comments[1].Content)
+	require.NotZero(t, comments[0].CreatedAt)
+	require.LessOrEqual(t, comments[0].CreatedAt, comments[1].CreatedAt)
+
+	_, err = s.addGroupComment(group.ID+1, "alice", "comment on a missing group")
+	require.True(t, errorx.IsOfType(err, rest.ErrNotFound))
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var ErrUnsupportedCompressionCodec = ErrNS.NewType("unsupported_compression_codec")
+
+const (
+	compressionCodecGzip = "gzip"
+	compressionCodecZstd = "zstd"
+	compressionCodecNone = "none"
+)
+
+// compressFile compresses a new result file in place with the configured codec, and returns the codec which the
+// file is compressed with, or an empty string if it is not compressed.
+func (r *resultFiles) compressFile(path string) (string, error) {
+	codec := compressionCodecGzip
+	if r != nil && r.codec != "" {
+		codec = r.codec
+	}
+	if codec == compressionCodecNone {
+		return "", nil
+	}
+	return compressFile(path, codec)
+}
+
+// compressFile compresses a result file in place with the codec, unless it is already compressed, e.g. protobuf
+// profiles of Go programs. It returns the codec which the file is compressed with, or an empty string if it is not
+// compressed.
+func compressFile(path string, codec string) (string, error) {
+	content, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	if bytes.HasPrefix(content, gzipMagic) {
+		return "", nil
+	}
+	compressed, err := compress(content, codec)
+	if err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(path, compressed, 0o600); err != nil {
+		return "", err
+	}
+	return codec, nil
+}
+
+func compress(content []byte, codec string) ([]byte, error) {
+	switch codec {
+	case compressionCodecGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(content); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case compressionCodecZstd:
+		zw, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer zw.Close()
+		return zw.EncodeAll(content, nil), nil
+	default:
+		return nil, ErrUnsupportedCompressionCodec.New("unsupported profiling compression codec %q", codec)
+	}
+}
+
+// decompress returns a reader of the content of r, which is compressed with the codec.
+func decompress(r io.Reader, codec string) (io.ReadCloser, error) {
+	switch codec {
+	case compressionCodecGzip:
+		return gzip.NewReader(r)
+	case compressionCodecZstd:
+		zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, ErrUnsupportedCompressionCodec.New("unsupported profiling compression codec %q", codec)
+	}
+}
+
+// readResult reads the result of a task, which is decrypted and decompressed if necessary.
+func (r *resultFiles) readResult(task *TaskModel) ([]byte, error) {
+	content, err := r.readFile(task.FilePath, task.StorageKey, task.EncryptionKeyID)
+	codec := task.compressionCodec()
+	if err != nil || codec == "" {
+		return content, err
+	}
+	zr, err := decompress(bytes.NewReader(content), codec)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return ioutil.ReadAll(zr)
+}
+
+// openResult opens an exported result for streaming, which is decrypted and decompressed if necessary.
+func (r *resultFiles) openResult(file exportFile) (io.ReadCloser, error) {
+	if file.content != nil {
+		return ioutil.NopCloser(bytes.NewReader(file.content)), nil
+	}
+	f, err := r.openFile(file.path, file.storageKey, file.keyID)
+	if err != nil || file.compressionCodec == "" {
+		return f, err
+	}
+	zr, err := decompress(f, file.compressionCodec)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &decompressReadCloser{ReadCloser: zr, file: f}, nil
+}
+
+type decompressReadCloser struct {
+	io.ReadCloser
+	file io.Closer
+}
+
+func (r *decompressReadCloser) Close() error {
+	_ = r.ReadCloser.Close()
+	return r.file.Close()
+}
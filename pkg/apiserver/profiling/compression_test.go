@@ -0,0 +1,135 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+)
+
+func TestCompressTextResult(t *testing.T) {
+	s := newTestService(t)
+	var sb strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&sb, "goroutine %d [select]:\nmain.worker()\n\t/src/main.go:42 +0x25\n\n", i)
+	}
+	dump := []byte(sb.String())
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return dump, nil
+	}}
+
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeGoroutine},
+	})
+	require.Len(t, tasks, 1)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.True(t, tasks[0].Compressed)
+	stat, err := os.Stat(tasks[0].FilePath)
+	require.NoError(t, err)
+	require.Less(t, stat.Size(), int64(len(dump))/10)
+
+	data, err := s.results.readResult(&tasks[0])
+	require.NoError(t, err)
+	require.Equal(t, dump, data)
+
+	// Exported results are decompressed.
+	buf := bytes.Buffer{}
+	zw := zip.NewWriter(&buf)
+	require.NoError(t, writeZipFromFiles(zw, s.results, []exportFile{newExportFile(0, tasks[0])}, true))
+	require.NoError(t, zw.Close())
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	f, err := zr.File[0].Open()
+	require.NoError(t, err)
+	exported, err := ioutil.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, dump, exported)
+}
+
+func TestCompressSkipsGzippedResult(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	task := newTestFinishedTask(t, s, ProfilingTypeCPU, RawDataTypeProtobuf, content)
+
+	codec, err := compressFile(task.FilePath, compressionCodecZstd)
+	require.NoError(t, err)
+	require.Empty(t, codec)
+	// Results saved before compression is introduced are read as is.
+	data, err := s.results.readResult(task)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+func TestCompressionCodec(t *testing.T) {
+	s := newTestService(t)
+	dump := bytes.Repeat([]byte("goroutine 1 [select]:\nmain.worker()\n\n"), 1000)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return dump, nil
+	}}
+	runTask := func() TaskModel {
+		tasks, _ := runTestGroup(t, s, &StartRequest{
+			Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+			RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeGoroutine},
+		})
+		require.Len(t, tasks, 1)
+		require.Equal(t, TaskStateFinish, tasks[0].State)
+		return tasks[0]
+	}
+	newResults := func(codec string) *resultFiles {
+		results, err := newResultFiles(&config.Config{ProfilingCompressionCodec: codec})
+		require.NoError(t, err)
+		return results
+	}
+
+	s.results = newResults(compressionCodecNone)
+	plain := runTask()
+	require.False(t, plain.Compressed)
+	s.results = newResults(compressionCodecGzip)
+	gzipped := runTask()
+	require.True(t, gzipped.Compressed)
+	require.Equal(t, compressionCodecGzip, gzipped.CompressionCodec)
+	s.results = newResults(compressionCodecZstd)
+	zstded := runTask()
+	require.True(t, zstded.Compressed)
+	require.Equal(t, compressionCodecZstd, zstded.CompressionCodec)
+	stored, err := ioutil.ReadFile(zstded.FilePath)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(stored, []byte{0x28, 0xb5, 0x2f, 0xfd}))
+	require.Less(t, len(stored), len(dump)/10)
+	// Results compressed by earlier versions do not record the codec, which is gzip.
+	legacy := gzipped
+	legacy.CompressionCodec = ""
+
+	// Results are read as they are saved regardless of the current codec, e.g. results stored with zstd are still
+	// read after the codec is switched to gzip.
+	for _, codec := range []string{compressionCodecNone, compressionCodecGzip, compressionCodecZstd} {
+		s.results = newResults(codec)
+		for _, task := range []TaskModel{plain, gzipped, zstded, legacy} {
+			data, err := s.results.readResult(&task)
+			require.NoError(t, err)
+			require.Equal(t, dump, data)
+
+			f, err := s.results.openResult(newExportFile(0, task))
+			require.NoError(t, err)
+			exported, err := ioutil.ReadAll(f)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+			require.Equal(t, dump, exported)
+		}
+	}
+
+	_, err = newResultFiles(&config.Config{ProfilingCompressionCodec: "brotli"})
+	require.True(t, errorx.IsOfType(err, ErrUnsupportedCompressionCodec))
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+)
+
+var (
+	ErrInvalidEncryptionKey = ErrNS.NewType("invalid_encryption_key")
+	ErrDecryptionFailed     = ErrNS.NewType("decryption_failed")
+)
+
+// resultCipher encrypts profiling result files at rest with AES-GCM. Each encrypted file is stored as
+// `nonce || ciphertext`, and the ID of the key is recorded in the task so that keys can be rotated.
+type resultCipher struct {
+	keyID string // The key to encrypt new results. New results are stored in plaintext when it is empty.
+	aeads map[string]cipher.AEAD
+}
+
+func newResultCipher(cfg *config.Config) (*resultCipher, error) {
+	c := &resultCipher{aeads: make(map[string]cipher.AEAD)}
+	if cfg == nil {
+		return c, nil
+	}
+	for keyID, key := range cfg.ProfilingEncryptionKeys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, ErrInvalidEncryptionKey.Wrap(err, "invalid profiling encryption key %s", keyID)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, ErrInvalidEncryptionKey.Wrap(err, "invalid profiling encryption key %s", keyID)
+		}
+		c.aeads[keyID] = aead
+	}
+	if cfg.ProfilingEncryptionKeyID != "" {
+		if _, ok := c.aeads[cfg.ProfilingEncryptionKeyID]; !ok {
+			return nil, ErrInvalidEncryptionKey.New("profiling encryption key %s is not configured", cfg.ProfilingEncryptionKeyID)
+		}
+		c.keyID = cfg.ProfilingEncryptionKeyID
+	}
+	return c, nil
+}
+
+// encryptFile encrypts the file in place with the current key, and returns the ID of the key used.
+// The file is kept in plaintext and an empty key ID is returned if encryption is not enabled.
+func (c *resultCipher) encryptFile(path string) (string, error) {
+	if c == nil || c.keyID == "" {
+		return "", nil
+	}
+	plaintext, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	aead := c.aeads[c.keyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ciphertext := aead.Seal(nonce, nonce, plaintext, []byte(c.keyID))
+	if err := ioutil.WriteFile(path, ciphertext, 0o600); err != nil {
+		return "", err
+	}
+	return c.keyID, nil
+}
+
+// decrypt decrypts the content of a result file with the given key. The content is returned as is if the key ID
+// is empty, which means the result is stored in plaintext.
+func (c *resultCipher) decrypt(content []byte, keyID string) ([]byte, error) {
+	if keyID == "" {
+		return content, nil
+	}
+	var aead cipher.AEAD
+	if c != nil {
+		aead = c.aeads[keyID]
+	}
+	if aead == nil {
+		return nil, ErrDecryptionFailed.New("profiling encryption key %s is not configured", keyID)
+	}
+	if len(content) < aead.NonceSize() {
+		return nil, ErrDecryptionFailed.New("encrypted profiling result is truncated")
+	}
+	nonce, ciphertext := content[:aead.NonceSize()], content[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, ErrDecryptionFailed.Wrap(err, "failed to decrypt profiling result with key %s", keyID)
+	}
+	return plaintext, nil
+}
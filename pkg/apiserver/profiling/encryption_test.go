@@ -0,0 +1,88 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+)
+
+func newTestEncryptedResults(t *testing.T, keyID string, keys map[string][]byte) *resultFiles {
+	results, err := newResultFiles(&config.Config{ProfilingEncryptionKeys: keys, ProfilingEncryptionKeyID: keyID})
+	require.NoError(t, err)
+	return results
+}
+
+func TestEncryptedResultRoundTrip(t *testing.T) {
+	s := newTestService(t)
+	s.results = newTestEncryptedResults(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, "k1", tasks[0].EncryptionKeyID)
+
+	stored, err := ioutil.ReadFile(tasks[0].FilePath)
+	require.NoError(t, err)
+	require.NotEqual(t, content, stored)
+	require.False(t, bytes.Contains(stored, []byte("main.alloc")))
+
+	data, err := s.results.readFile(tasks[0].FilePath, tasks[0].StorageKey, tasks[0].EncryptionKeyID)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	// The key is rotated, while the previous key is kept to decrypt existing results.
+	rotated := newTestEncryptedResults(t, "k2", map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	})
+	data, err = rotated.readFile(tasks[0].FilePath, tasks[0].StorageKey, tasks[0].EncryptionKeyID)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+func TestEncryptedResultWrongKey(t *testing.T) {
+	results := newTestEncryptedResults(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	path := t.TempDir() + "/profile.proto"
+	require.NoError(t, ioutil.WriteFile(path, []byte("secret"), 0o600))
+	keyID, err := results.encryptFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "k1", keyID)
+
+	wrongKey := newTestEncryptedResults(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{9}, 32)})
+	_, err = wrongKey.readFile(path, "", keyID)
+	require.True(t, errorx.IsOfType(err, ErrDecryptionFailed))
+
+	missingKey := newTestEncryptedResults(t, "", nil)
+	_, err = missingKey.readFile(path, "", keyID)
+	require.True(t, errorx.IsOfType(err, ErrDecryptionFailed))
+}
+
+func TestPlaintextResultIsReadable(t *testing.T) {
+	results := newTestEncryptedResults(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	path := t.TempDir() + "/profile.proto"
+	require.NoError(t, ioutil.WriteFile(path, []byte("legacy"), 0o600))
+	data, err := results.readFile(path, "", "")
+	require.NoError(t, err)
+	require.Equal(t, []byte("legacy"), data)
+}
+
+func TestInvalidEncryptionKey(t *testing.T) {
+	_, err := newResultCipher(&config.Config{ProfilingEncryptionKeys: map[string][]byte{"k1": []byte("short")}})
+	require.True(t, errorx.IsOfType(err, ErrInvalidEncryptionKey))
+	_, err = newResultCipher(&config.Config{ProfilingEncryptionKeyID: "k1"})
+	require.True(t, errorx.IsOfType(err, ErrInvalidEncryptionKey))
+}
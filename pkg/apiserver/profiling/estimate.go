@@ -0,0 +1,164 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+// estimateSampleSize is the number of the latest finished tasks of a kind of component and a profiling type whose
+// sizes are averaged, so that the estimation follows recent changes of the cluster.
+const estimateSampleSize = 20
+
+type EstimateGroupResponse struct {
+	// The approximate total size in bytes of the profiles, which only covers the estimated tasks.
+	ApproxBytes int64 `json:"approx_bytes"`
+	// The number of tasks which are not skipped.
+	NumTasks int `json:"num_tasks"`
+	// The number of tasks which cannot be estimated, since no task of the same kind of component and profiling
+	// type is finished recently.
+	NumUnknownTasks int `json:"num_unknown_tasks"`
+}
+
+type profileSizeKey struct {
+	kind          model.NodeKind
+	profilingType TaskProfilingType
+}
+
+// profileSize is the average size of the latest finished tasks of a kind of component and a profiling type.
+type profileSize struct {
+	bytes       float64
+	bytesPerSec float64 // Only valid for profiling types captured over a duration
+}
+
+// profileSizes returns the average sizes of the latest finished tasks, by kinds of components and profiling types.
+func (s *Service) profileSizes() (map[profileSizeKey]profileSize, error) {
+	var tasks []TaskModel
+	err := s.params.LocalStore.
+		Select("task_group_id", "kind", "profiling_type", "size_bytes", "duration_secs").
+		Where("state = ? AND size_bytes > 0", TaskStateFinish).
+		Order("id DESC").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	groupIDs := make([]uint, 0)
+	seenGroups := make(map[uint]struct{})
+	for _, task := range tasks {
+		if _, ok := seenGroups[task.TaskGroupID]; !ok {
+			seenGroups[task.TaskGroupID] = struct{}{}
+			groupIDs = append(groupIDs, task.TaskGroupID)
+		}
+	}
+	groupDurations := make(map[uint]uint, len(groupIDs))
+	if len(groupIDs) > 0 {
+		var groups []TaskGroupModel
+		if err := s.params.LocalStore.Select("id", "profile_duration_secs").Where("id IN ?", groupIDs).Find(&groups).Error; err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			groupDurations[group.ID] = group.ProfileDurationSecs
+		}
+	}
+
+	type sum struct {
+		n, timed            int
+		bytes, bytesPerSecs float64
+	}
+	sums := make(map[profileSizeKey]*sum)
+	for _, task := range tasks {
+		key := profileSizeKey{kind: task.Target.Kind, profilingType: task.ProfilingType}
+		acc, ok := sums[key]
+		if !ok {
+			acc = &sum{}
+			sums[key] = acc
+		}
+		if acc.n >= estimateSampleSize {
+			continue
+		}
+		acc.n++
+		acc.bytes += float64(task.SizeBytes)
+		if durationSecs := task.profileDurationSecs(groupDurations[task.TaskGroupID]); durationSecs > 0 {
+			acc.timed++
+			acc.bytesPerSecs += float64(task.SizeBytes) / float64(durationSecs)
+		}
+	}
+	sizes := make(map[profileSizeKey]profileSize, len(sums))
+	for key, acc := range sums {
+		size := profileSize{bytes: acc.bytes / float64(acc.n)}
+		if acc.timed > 0 {
+			size.bytesPerSec = acc.bytesPerSecs / float64(acc.timed)
+		}
+		sizes[key] = size
+	}
+	return sizes, nil
+}
+
+// estimateGroup estimates the total size of the profiles of a profiling request from the sizes of recently
+// finished tasks, so that a huge capture can be noticed before it is started. The sizes of profiles captured over
+// a duration are scaled by the requested duration.
+func (s *Service) estimateGroup(req *StartRequest) (*EstimateGroupResponse, error) {
+	sizes, err := s.profileSizes()
+	if err != nil {
+		return nil, err
+	}
+	targets := uniqueTargets(req.Targets)
+	// TiKV stores are not resolved, since only the kind of targets matters.
+	for range req.TiKVStoreIDs {
+		targets = append(targets, model.RequestTargetNode{Kind: model.NodeKindTiKV})
+	}
+
+	resp := &EstimateGroupResponse{}
+	var approxBytes float64
+	for _, target := range targets {
+		profileTypeList := req.RequstedProfilingTypes
+		if types, ok := req.ProfilingTypesByKind[target.Kind]; ok {
+			profileTypeList = types
+		}
+		for _, profilingType := range profileTypeList {
+			if !supportsProfilingType(target.Kind, profilingType) {
+				continue
+			}
+			for _, durationSecs := range req.durationSecsListOf(profilingType) {
+				resp.NumTasks++
+				bytes, ok := estimateProfile(sizes, target.Kind, profilingType, durationSecs)
+				if !ok {
+					resp.NumUnknownTasks++
+					continue
+				}
+				approxBytes += bytes
+			}
+		}
+	}
+	resp.ApproxBytes = int64(approxBytes)
+	return resp, nil
+}
+
+// estimateTasks estimates the total size of the profiles of tasks to be run again, e.g. to be refreshed or retried.
+// Tasks which cannot be estimated are not counted.
+func (s *Service) estimateTasks(tasks []TaskModel, groupDurationSecs uint) (int64, error) {
+	sizes, err := s.profileSizes()
+	if err != nil {
+		return 0, err
+	}
+	var approxBytes float64
+	for _, task := range tasks {
+		if bytes, ok := estimateProfile(sizes, task.Target.Kind, task.ProfilingType, task.profileDurationSecs(groupDurationSecs)); ok {
+			approxBytes += bytes
+		}
+	}
+	return int64(approxBytes), nil
+}
+
+// estimateProfile returns the approximate size of a profile, or false if no task of the same kind of component and
+// profiling type is finished recently.
+func estimateProfile(sizes map[profileSizeKey]profileSize, kind model.NodeKind, profilingType TaskProfilingType, durationSecs uint) (float64, bool) {
+	size, ok := sizes[profileSizeKey{kind: kind, profilingType: profilingType}]
+	if !ok {
+		return 0, false
+	}
+	if !profilingType.isSnapshot() && size.bytesPerSec > 0 {
+		return size.bytesPerSec * float64(durationSecs), true
+	}
+	return size.bytes, true
+}
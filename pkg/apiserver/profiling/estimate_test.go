@@ -0,0 +1,58 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+func TestEstimateGroup(t *testing.T) {
+	s := newTestService(t)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if op.path == "/debug/pprof/trace?seconds=2" {
+			return bytes.Repeat([]byte("t"), 2000), nil
+		}
+		return bytes.Repeat([]byte("g"), 300), nil
+	}}
+	tidb := model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}
+	tikv := model.RequestTargetNode{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180}
+	req := &StartRequest{
+		Targets:                []model.RequestTargetNode{tidb, tikv},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeGoroutine, ProfilingTypeTrace},
+	}
+
+	// Nothing can be estimated without history. Profiling types not supported by TiKV are not counted.
+	resp, err := s.estimateGroup(req)
+	require.NoError(t, err)
+	require.Equal(t, &EstimateGroupResponse{NumTasks: 2, NumUnknownTasks: 2}, resp)
+
+	for i := 0; i < 3; i++ {
+		_, group := runTestGroup(t, s, &StartRequest{
+			Targets:                []model.RequestTargetNode{tidb},
+			DurationSecs:           2,
+			RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeGoroutine, ProfilingTypeTrace},
+		})
+		require.Equal(t, TaskStateFinish, group.State)
+	}
+
+	// A trace is about 1000 bytes per second, which is scaled by the duration, while the size of goroutines is not.
+	req.Targets = append(req.Targets, model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080})
+	req.DurationSecs = 10
+	resp, err = s.estimateGroup(req)
+	require.NoError(t, err)
+	require.Equal(t, 4, resp.NumTasks)
+	require.Zero(t, resp.NumUnknownTasks)
+	require.Equal(t, int64(2*(300+1000*10)), resp.ApproxBytes)
+
+	// TiKV CPU profiles have never been captured.
+	req.RequstedProfilingTypes = TaskProfilingTypeList{ProfilingTypeCPU}
+	resp, err = s.estimateGroup(req)
+	require.NoError(t, err)
+	require.Equal(t, &EstimateGroupResponse{NumTasks: 3, NumUnknownTasks: 3}, resp)
+}
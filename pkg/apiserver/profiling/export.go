@@ -0,0 +1,148 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+// ExportFilter scopes the results of a task group to export, e.g. only CPU profiles of TiKV. Empty fields
+// do not filter.
+type ExportFilter struct {
+	Kinds          []model.NodeKind    `json:"kinds" form:"kinds"`
+	Targets        []string            `json:"targets" form:"targets"` // Addresses of targets in the form of "ip:port"
+	ProfilingTypes []TaskProfilingType `json:"profiling_types" form:"profiling_types"`
+}
+
+func (f *ExportFilter) isEmpty() bool {
+	return len(f.Kinds) == 0 && len(f.Targets) == 0 && len(f.ProfilingTypes) == 0
+}
+
+// apply adds the filter to a query of tasks, so that results filtered out are never loaded.
+func (f *ExportFilter) apply(db *gorm.DB) (*gorm.DB, error) {
+	if len(f.Kinds) > 0 {
+		db = db.Where("kind IN ?", f.Kinds)
+	}
+	if len(f.ProfilingTypes) > 0 {
+		db = db.Where("profiling_type IN ?", f.ProfilingTypes)
+	}
+	if len(f.Targets) > 0 {
+		targets := db.Session(&gorm.Session{NewDB: true})
+		for i, addr := range f.Targets {
+			host, portStr, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, rest.ErrBadRequest.New("invalid target address %q, expect ip:port", addr)
+			}
+			port, err := strconv.Atoi(portStr)
+			if err != nil {
+				return nil, rest.ErrBadRequest.New("invalid target address %q, expect ip:port", addr)
+			}
+			if i == 0 {
+				targets = targets.Where("ip = ? AND port = ?", host, port)
+			} else {
+				targets = targets.Or("ip = ? AND port = ?", host, port)
+			}
+		}
+		db = db.Where(targets)
+	}
+	return db, nil
+}
+
+// groupExportFiles returns finished profiling results of a task group matching the filter to be exported.
+func (s *Service) groupExportFiles(taskGroupID uint, filter ExportFilter) ([]exportFile, error) {
+	var taskGroup TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", taskGroupID).First(&taskGroup).Error; err != nil {
+		return nil, err
+	}
+	query, err := filter.apply(s.params.LocalStore.Where("task_group_id = ? AND state = ?", taskGroupID, TaskStateFinish))
+	if err != nil {
+		return nil, err
+	}
+	var tasks []TaskModel
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	files := make([]exportFile, len(tasks))
+	names := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		files[i] = newExportFile(taskGroup.StartedAt, task)
+		names[files[i].name]++
+	}
+	// Tasks of a target captured with multiple durations are told apart by their durations in the file names.
+	for i, task := range tasks {
+		if names[files[i].name] > 1 {
+			ext := filepath.Ext(files[i].name)
+			durationSecs := task.profileDurationSecs(taskGroup.ProfileDurationSecs)
+			files[i].name = fmt.Sprintf("%s_%ds%s", strings.TrimSuffix(files[i].name, ext), durationSecs, ext)
+		}
+	}
+	return files, nil
+}
+
+// groupMergedCPUFile returns the finished CPU profiles of a task group matching the filter merged into a single
+// profile, which is computed on read and never persisted. nil is returned if there is no such profile.
+func (s *Service) groupMergedCPUFile(taskGroupID uint, filter ExportFilter) (*exportFile, error) {
+	var taskGroup TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", taskGroupID).First(&taskGroup).Error; err != nil {
+		return nil, err
+	}
+	query, err := filter.apply(s.params.LocalStore.
+		Where("task_group_id = ? AND state = ? AND profiling_type = ? AND raw_data_type = ?", taskGroupID, TaskStateFinish, ProfilingTypeCPU, RawDataTypeProtobuf).
+		Order("id ASC"))
+	if err != nil {
+		return nil, err
+	}
+	var tasks []TaskModel
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+	content, err := s.mergeTasks(tasks)
+	if err != nil {
+		return nil, err
+	}
+	capturedAt := time.Unix(taskGroup.StartedAt, 0).UTC().Format(exportFileTimeLayout)
+	return &exportFile{name: fmt.Sprintf("%s_%s_merged.proto", capturedAt, ProfilingTypeCPU), content: content}, nil
+}
+
+// exportGroup writes the files into a zip archive. When autoDelete is set, the task group is deleted
+// after the archive is completely written, and is kept when any error occurs.
+func (s *Service) exportGroup(w io.Writer, taskGroupID uint, files []exportFile, autoDelete bool) error {
+	zw := zip.NewWriter(w)
+	if err := writeZipFromFiles(zw, s.results, files, true); err != nil {
+		_ = zw.Close()
+		return err
+	}
+	if err := zipREADME(zw); err != nil {
+		_ = zw.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	if autoDelete {
+		if err := s.deleteGroup(taskGroupID); err != nil {
+			log.Warn("failed to delete task group after export", zap.Uint("id", taskGroupID), zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
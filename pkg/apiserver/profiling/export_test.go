@@ -0,0 +1,241 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/pprof/profile"
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/utils"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+func TestExportFileName(t *testing.T) {
+	capturedAt := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	task := TaskModel{
+		Target:        model.RequestTargetNode{Kind: model.NodeKindTiKV, DisplayName: "10.0.0.1:20160", IP: "10.0.0.1", Port: 20180},
+		FilePath:      "/tmp/cpu_tikv_10.0.0.1_20160_123456.proto",
+		ProfilingType: ProfilingTypeCPU,
+	}
+	name := exportFileName(capturedAt.Unix(), task)
+	require.Equal(t, "2022-03-04_05-06-07_cpu_tikv_10.0.0.1_20160.proto", name)
+
+	parsed, err := time.Parse(exportFileTimeLayout, name[:len(exportFileTimeLayout)])
+	require.NoError(t, err)
+	require.True(t, capturedAt.Equal(parsed))
+
+	// The same target captured in another task group has a different name.
+	require.NotEqual(t, name, exportFileName(capturedAt.Add(time.Minute).Unix(), task))
+	require.True(t, strings.HasSuffix(exportFileName(capturedAt.Unix(), TaskModel{FilePath: "/tmp/goroutine.txt"}), ".txt"))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+func TestExportGroupAutoDelete(t *testing.T) {
+	s := newTestService(t)
+	newGroup := func() (*TaskGroupModel, *TaskModel) {
+		group := &TaskGroupModel{State: TaskStateFinish, StartedAt: time.Now().Unix()}
+		require.NoError(t, s.params.LocalStore.Create(group).Error)
+		task := newTestFinishedTask(t, s, ProfilingTypeCPU, RawDataTypeProtobuf, newTestCPUProfile(t, map[string]int64{"main.main": 1e9}))
+		require.NoError(t, s.params.LocalStore.Model(task).Update("task_group_id", group.ID).Error)
+		return group, task
+	}
+	groupExists := func(id uint) bool {
+		var count int64
+		require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Where("id = ?", id).Count(&count).Error)
+		return count > 0
+	}
+
+	// A failed export keeps the task group.
+	group, task := newGroup()
+	files, err := s.groupExportFiles(group.ID, ExportFilter{})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Error(t, s.exportGroup(failingWriter{}, group.ID, files, true))
+	require.True(t, groupExists(group.ID))
+	require.FileExists(t, task.FilePath)
+
+	// A successful export deletes the task group and its results.
+	buf := bytes.Buffer{}
+	require.NoError(t, s.exportGroup(&buf, group.ID, files, true))
+	require.False(t, groupExists(group.ID))
+	require.NoFileExists(t, task.FilePath)
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 2) // The profile and README.md
+
+	// Without auto delete the task group is kept.
+	group, _ = newGroup()
+	files, err = s.groupExportFiles(group.ID, ExportFilter{})
+	require.NoError(t, err)
+	require.NoError(t, s.exportGroup(&bytes.Buffer{}, group.ID, files, false))
+	require.True(t, groupExists(group.ID))
+}
+
+func TestDownloadGroupAutoDeleteRequiresWritePriv(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newTestService(t)
+	group := &TaskGroupModel{State: TaskStateFinish, StartedAt: time.Now().Unix()}
+	require.NoError(t, s.params.LocalStore.Create(group).Error)
+	task := newTestFinishedTask(t, s, ProfilingTypeCPU, RawDataTypeProtobuf, newTestCPUProfile(t, map[string]int64{"main.main": 1e9}))
+	require.NoError(t, s.params.LocalStore.Model(task).Update("task_group_id", group.ID).Error)
+	groupExists := func() bool {
+		var count int64
+		require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Where("id = ?", group.ID).Count(&count).Error)
+		return count > 0
+	}
+	getToken := func(writeable bool, action string) (string, error) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/action_token?id=%d&action=%s", group.ID, action), nil)
+		c.Set(utils.SessionUserKey, &utils.SessionUser{IsWriteable: writeable})
+		s.getActionToken(c)
+		if err := c.Errors.Last(); err != nil {
+			return "", err.Err
+		}
+		return w.Body.String(), nil
+	}
+	download := func(token string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/group/download?auto_delete=true&token="+url.QueryEscape(token), nil)
+		s.downloadGroup(c)
+		return c
+	}
+
+	// A read-only user cannot get a token to delete the task group.
+	_, err := getToken(false, groupDownloadAutoDeleteAction)
+	require.True(t, errorx.IsOfType(err, rest.ErrForbidden))
+
+	// Nor delete it with a token for downloading only.
+	token, err := getToken(false, "group_download")
+	require.NoError(t, err)
+	c := download(token)
+	require.True(t, errorx.IsOfType(c.Errors.Last().Err, rest.ErrBadRequest))
+	require.True(t, groupExists())
+
+	// A user with the write privilege can.
+	token, err = getToken(true, groupDownloadAutoDeleteAction)
+	require.NoError(t, err)
+	c = download(token)
+	require.Empty(t, c.Errors)
+	require.False(t, groupExists())
+}
+
+func TestGroupExportFilesFilter(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	mock := &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	s.fetchers.tidb = mock
+	s.fetchers.tikv = mock
+	tidb := model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}
+	tikv1 := model.RequestTargetNode{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180}
+	tikv2 := model.RequestTargetNode{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.2:20160", IP: "127.0.0.2", Port: 20180}
+	_, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{tidb, tikv1, tikv2},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU, ProfilingTypeGoroutine},
+	})
+
+	exportedTargets := func(filter ExportFilter) []string {
+		files, err := s.groupExportFiles(group.ID, filter)
+		require.NoError(t, err)
+		names := make([]string, 0, len(files))
+		for _, file := range files {
+			names = append(names, file.name)
+		}
+		return names
+	}
+	// TiKV only supports CPU profiling, so the TiDB target has 2 results.
+	require.Len(t, exportedTargets(ExportFilter{}), 4)
+
+	names := exportedTargets(ExportFilter{Kinds: []model.NodeKind{model.NodeKindTiKV}})
+	require.Len(t, names, 2)
+	for _, name := range names {
+		require.Contains(t, name, "tikv")
+	}
+	require.Len(t, exportedTargets(ExportFilter{Kinds: []model.NodeKind{model.NodeKindTiDB}, ProfilingTypes: []TaskProfilingType{ProfilingTypeCPU}}), 1)
+	names = exportedTargets(ExportFilter{Targets: []string{"127.0.0.2:20180", "127.0.0.1:10080"}})
+	require.Len(t, names, 3)
+	require.Len(t, exportedTargets(ExportFilter{Kinds: []model.NodeKind{model.NodeKindTiKV}, Targets: []string{"127.0.0.1:10080"}}), 0)
+
+	_, err := s.groupExportFiles(group.ID, ExportFilter{Targets: []string{"127.0.0.1"}})
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+}
+
+func TestExportGroupMergedCPU(t *testing.T) {
+	s := newTestService(t)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if strings.Contains(op.path, "profile") {
+			return newTestCPUProfile(t, map[string]int64{"main." + op.ip: 10000000}), nil
+		}
+		return newTestHeapProfile(t, map[string]int64{"main.alloc": 10}), nil
+	}}
+	_, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU, ProfilingTypeHeap},
+	})
+
+	files, err := s.groupExportFiles(group.ID, ExportFilter{})
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+	merged, err := s.groupMergedCPUFile(group.ID, ExportFilter{})
+	require.NoError(t, err)
+	require.NotNil(t, merged)
+	require.True(t, strings.HasSuffix(merged.name, "_cpu_merged.proto"))
+
+	buf := bytes.Buffer{}
+	require.NoError(t, s.exportGroup(&buf, group.ID, append(files, *merged), false))
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 6) // 4 profiles, the merged profile and README.md
+	var mergedEntry *zip.File
+	for _, f := range zr.File {
+		if f.Name == merged.name {
+			mergedEntry = f
+		}
+	}
+	require.NotNil(t, mergedEntry)
+	r, err := mergedEntry.Open()
+	require.NoError(t, err)
+	defer r.Close()
+	p, err := profile.Parse(r)
+	require.NoError(t, err)
+	flat, total := flatByFunction(p, defaultSampleIndex(p))
+	require.Equal(t, int64(10000000), flat["main.127.0.0.1"])
+	require.Equal(t, int64(10000000), flat["main.127.0.0.2"])
+	require.Equal(t, int64(20000000), total)
+
+	// The merged profile respects the filter, and is absent without CPU profiles.
+	merged, err = s.groupMergedCPUFile(group.ID, ExportFilter{Targets: []string{"127.0.0.2:10080"}})
+	require.NoError(t, err)
+	p, err = profile.ParseData(merged.content)
+	require.NoError(t, err)
+	_, total = flatByFunction(p, defaultSampleIndex(p))
+	require.Equal(t, int64(10000000), total)
+	merged, err = s.groupMergedCPUFile(group.ID, ExportFilter{ProfilingTypes: []TaskProfilingType{ProfilingTypeHeap}})
+	require.NoError(t, err)
+	require.Nil(t, merged)
+}
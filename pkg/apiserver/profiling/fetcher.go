@@ -3,12 +3,18 @@
 package profiling
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
 	"github.com/pingcap/tidb-dashboard/pkg/config"
+	"github.com/pingcap/tidb-dashboard/pkg/httpc"
 	"github.com/pingcap/tidb-dashboard/pkg/pd"
 	"github.com/pingcap/tidb-dashboard/pkg/tidb"
 	"github.com/pingcap/tidb-dashboard/pkg/tiflash"
@@ -16,13 +22,22 @@ import (
 )
 
 const (
-	maxProfilingTimeout = time.Minute * 5
+	defaultPprofPathPrefix = "/debug/pprof"
 )
 
 type fetchOptions struct {
+	ctx  context.Context // Cancels the fetch when done. The lifecycle context of the client is used when nil.
 	ip   string
 	port int
 	path string
+	// The time allowed for the request, e.g. the profile duration plus some slack. It is not limited when it is 0.
+	timeout time.Duration
+	// Receives the bytes of the response as they arrive, if it is set and the fetcher supports partial results.
+	partial *partialBuffer
+	// Throttles reading the response, if it is set.
+	limiter *bandwidthLimiter
+	// Logs the fetch, e.g. retries, with the fields of the task it is for. The global logger is used when nil.
+	logger *zap.Logger
 }
 
 type profileFetcher interface {
@@ -34,16 +49,212 @@ type fetchers struct {
 	tiflash profileFetcher
 	tidb    profileFetcher
 	pd      profileFetcher
+	tiproxy profileFetcher
+	ticdc   profileFetcher
+	// PD micro-services serve profiles on their service ports in the same way as PD.
+	pdTSO        profileFetcher
+	pdScheduling profileFetcher
+
+	resultDir string // The directory to write results, or the temporary directory of the OS if it is empty
+	// The path under which pprof handlers of a kind of component are served, if it is not the default one.
+	pprofPathPrefixes map[model.NodeKind]string
+}
+
+var newFetchers = fx.Provide(buildFetchers)
+
+// shared returns fetchers which share the result of identical in-flight fetches, so that profiling types of a
+// target resolving to the same endpoint (e.g. heap and the first snapshot of heap_diff) are fetched only once.
+// Each caller still stops waiting when its own context is done.
+func (fts *fetchers) shared() *fetchers {
+	return fts.wrap(func(f profileFetcher) profileFetcher {
+		return &sharedFetcher{profileFetcher: f}
+	})
+}
+
+// wrap returns fetchers which are wrapped by fn. Fetchers not configured are kept nil.
+func (fts *fetchers) wrap(fn func(f profileFetcher) profileFetcher) *fetchers {
+	wrapped := func(f profileFetcher) profileFetcher {
+		if f == nil {
+			return nil
+		}
+		return fn(f)
+	}
+	return &fetchers{
+		tikv:    wrapped(fts.tikv),
+		tiflash: wrapped(fts.tiflash),
+		tidb:    wrapped(fts.tidb),
+		pd:      wrapped(fts.pd),
+		tiproxy: wrapped(fts.tiproxy),
+		ticdc:   wrapped(fts.ticdc),
+
+		pdTSO:        wrapped(fts.pdTSO),
+		pdScheduling: wrapped(fts.pdScheduling),
+
+		resultDir:         fts.resultDir,
+		pprofPathPrefixes: fts.pprofPathPrefixes,
+	}
+}
+
+// pprofPathPrefix returns the path under which pprof handlers of a kind of component are served, without the
+// trailing slash.
+func (fts *fetchers) pprofPathPrefix(kind model.NodeKind) string {
+	if prefix, ok := fts.pprofPathPrefixes[kind]; ok && prefix != "" {
+		return "/" + strings.Trim(prefix, "/")
+	}
+	return defaultPprofPathPrefix
+}
+
+type sharedFetcher struct {
+	profileFetcher
+	mu    sync.Mutex
+	calls map[string]*sharedFetch // In-flight fetches by their addresses and paths
+}
+
+// sharedFetch is an in-flight fetch shared by the callers fetching the same address and path. It is not bound to
+// the context of any caller, and is only cancelled when all callers are gone, so that cancelling one task does
+// not fail the others sharing the fetch.
+type sharedFetch struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	callers int            // Guarded by sharedFetcher.mu
+	partial *partialBuffer // Receives the bytes for the partial buffers of the callers, or nil if they have none
+	done    chan struct{}  // Closed when data and err are set
+	data    []byte
+	err     error
+}
+
+func (f *sharedFetcher) unwrap() profileFetcher {
+	return f.profileFetcher
+}
+
+func (f *sharedFetcher) fetch(op *fetchOptions) ([]byte, error) {
+	key := fmt.Sprintf("%s:%d%s", op.ip, op.port, op.path)
+	f.mu.Lock()
+	call, ok := f.calls[key]
+	if !ok {
+		call = &sharedFetch{done: make(chan struct{})}
+		call.ctx, call.cancel = context.WithCancel(context.Background())
+		if op.partial != nil {
+			call.partial = &partialBuffer{}
+		}
+		if f.calls == nil {
+			f.calls = make(map[string]*sharedFetch)
+		}
+		f.calls[key] = call
+		go f.run(key, call, op)
+	}
+	call.callers++
+	if call.partial != nil && op.partial != nil {
+		call.partial.addMirror(op.partial)
+	}
+	f.mu.Unlock()
+
+	var cancelled <-chan struct{}
+	if op.ctx != nil {
+		cancelled = op.ctx.Done()
+	}
+	select {
+	case <-call.done:
+		return call.data, call.err
+	case <-cancelled:
+		f.leave(key, call, op.partial)
+		return nil, op.ctx.Err()
+	}
+}
+
+// run fetches on behalf of all callers of a shared fetch.
+func (f *sharedFetcher) run(key string, call *sharedFetch, op *fetchOptions) {
+	sharedOp := *op
+	sharedOp.ctx = call.ctx
+	sharedOp.partial = call.partial
+	data, err := f.profileFetcher.fetch(&sharedOp)
+
+	f.mu.Lock()
+	if f.calls[key] == call {
+		delete(f.calls, key)
+	}
+	f.mu.Unlock()
+	call.cancel()
+	call.data, call.err = data, err
+	close(call.done)
+}
+
+// leave removes a cancelled caller from a shared fetch, which is cancelled if there is no caller left.
+func (f *sharedFetcher) leave(key string, call *sharedFetch, partial *partialBuffer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if call.partial != nil && partial != nil {
+		call.partial.removeMirror(partial)
+	}
+	call.callers--
+	if call.callers > 0 {
+		return
+	}
+	// Later callers start a new fetch instead of joining the cancelled one.
+	if f.calls[key] == call {
+		delete(f.calls, key)
+	}
+	call.cancel()
+}
+
+// recordingFetcher records the address and the path of the first fetch, so that the request of a task can be
+// looked up when debugging, e.g. when the status port of a target in the topology is wrong.
+type recordingFetcher struct {
+	profileFetcher
+	requestURL *string
+}
+
+func (f *recordingFetcher) unwrap() profileFetcher {
+	return f.profileFetcher
+}
+
+func (f *recordingFetcher) fetch(op *fetchOptions) ([]byte, error) {
+	if *f.requestURL == "" {
+		*f.requestURL = fmt.Sprintf("%s:%d%s", op.ip, op.port, op.path)
+	}
+	return f.profileFetcher.fetch(op)
+}
+
+// recording returns fetchers which record the first fetch to requestURL. It must not be shared by concurrent tasks.
+func (fts *fetchers) recording(requestURL *string) *fetchers {
+	return fts.wrap(func(f profileFetcher) profileFetcher {
+		return &recordingFetcher{profileFetcher: f, requestURL: requestURL}
+	})
+}
+
+// loggingFetcher passes the logger of a task to its fetches.
+type loggingFetcher struct {
+	profileFetcher
+	logger *zap.Logger
+}
+
+func (f *loggingFetcher) unwrap() profileFetcher {
+	return f.profileFetcher
 }
 
-var newFetchers = fx.Provide(func(
+func (f *loggingFetcher) fetch(op *fetchOptions) ([]byte, error) {
+	loggedOp := *op
+	loggedOp.logger = f.logger
+	return f.profileFetcher.fetch(&loggedOp)
+}
+
+// logging returns fetchers which log with logger, e.g. the logger of a task, so that the logs of the fetches can be
+// traced back to the task. A fetch shared by tasks is logged with the logger of the task which starts it.
+func (fts *fetchers) logging(logger *zap.Logger) *fetchers {
+	return fts.wrap(func(f profileFetcher) profileFetcher {
+		return &loggingFetcher{profileFetcher: f, logger: logger}
+	})
+}
+
+func buildFetchers(
+	lc fx.Lifecycle,
 	tikvClient *tikv.Client,
 	tidbClient *tidb.Client,
 	pdClient *pd.Client,
 	tiflashClient *tiflash.Client,
 	config *config.Config,
 ) *fetchers {
-	return &fetchers{
+	fts := &fetchers{
 		tikv: &tikvFetcher{
 			client: tikvClient,
 		},
@@ -57,43 +268,230 @@ var newFetchers = fx.Provide(func(
 			client:              pdClient,
 			statusAPIHTTPScheme: config.GetClusterHTTPScheme(),
 		},
+		// TiProxy serves profiles on its status port in the same way as TiDB.
+		tiproxy: &tidbFetcher{
+			client: tidbClient,
+		},
+		// TiCDC serves profiles on its HTTP API port in the same way as TiDB.
+		ticdc: &tidbFetcher{
+			client: tidbClient,
+		},
+		pdTSO: &pdFetcher{
+			client:              pdClient,
+			statusAPIHTTPScheme: config.GetClusterHTTPScheme(),
+		},
+		pdScheduling: &pdFetcher{
+			client:              pdClient,
+			statusAPIHTTPScheme: config.GetClusterHTTPScheme(),
+		},
+		resultDir:         config.ProfilingResultDir,
+		pprofPathPrefixes: make(map[model.NodeKind]string, len(config.ProfilingPprofPathPrefixes)),
+	}
+	for kind, prefix := range config.ProfilingPprofPathPrefixes {
+		fts.pprofPathPrefixes[model.NodeKind(kind)] = prefix
+	}
+
+	// Components signed by a different CA or reached through an egress proxy are fetched using dedicated HTTP clients.
+	// Note: the component clients must not be cloned here, otherwise the clones will miss the lifecycle context.
+	for _, targetKind := range profilingTargetKinds {
+		kind := model.NodeKind(targetKind)
+		dedicated := newDedicatedHTTPClient(lc, config, kind)
+		if dedicated == nil {
+			continue
+		}
+		switch kind {
+		case model.NodeKindTiKV:
+			fts.tikv.(*tikvFetcher).dedicated = dedicated
+		case model.NodeKindTiFlash:
+			fts.tiflash.(*tiflashFetcher).dedicated = dedicated
+		case model.NodeKindTiDB:
+			fts.tidb.(*tidbFetcher).dedicated = dedicated
+		case model.NodeKindPD:
+			fts.pd.(*pdFetcher).dedicated = dedicated
+		case model.NodeKindTiProxy:
+			fts.tiproxy.(*tidbFetcher).dedicated = dedicated
+		case model.NodeKindTiCDC:
+			fts.ticdc.(*tidbFetcher).dedicated = dedicated
+		case model.NodeKindPDTSO:
+			fts.pdTSO.(*pdFetcher).dedicated = dedicated
+		case model.NodeKindPDScheduling:
+			fts.pdScheduling.(*pdFetcher).dedicated = dedicated
+		}
+	}
+
+	for kind, authorization := range config.ProfilingAuthorizations {
+		switch model.NodeKind(kind) {
+		case model.NodeKindTiKV:
+			fts.tikv.(*tikvFetcher).authorization = authorization
+		case model.NodeKindTiFlash:
+			fts.tiflash.(*tiflashFetcher).authorization = authorization
+		case model.NodeKindTiDB:
+			fts.tidb.(*tidbFetcher).authorization = authorization
+		case model.NodeKindPD:
+			fts.pd.(*pdFetcher).authorization = authorization
+		case model.NodeKindTiProxy:
+			fts.tiproxy.(*tidbFetcher).authorization = authorization
+		case model.NodeKindTiCDC:
+			fts.ticdc.(*tidbFetcher).authorization = authorization
+		case model.NodeKindPDTSO:
+			fts.pdTSO.(*pdFetcher).authorization = authorization
+		case model.NodeKindPDScheduling:
+			fts.pdScheduling.(*pdFetcher).authorization = authorization
+		}
+	}
+
+	maxRetries := config.ProfilingFetchMaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultFetchMaxRetries
+	}
+	if maxRetries > 0 {
+		fts = fts.retrying(maxRetries, defaultFetchRetryBackoff)
+	}
+	if config.ProfilingBandwidthLimit > 0 {
+		return fts.limited(config.ProfilingBandwidthLimit)
 	}
-})
+	return fts
+}
+
+// dedicatedHTTPClient is used instead of the cluster HTTP client to fetch profiles of a kind of component.
+type dedicatedHTTPClient struct {
+	*httpc.Client
+	scheme string
+}
+
+// newDedicatedHTTPClient returns the HTTP client for a kind of component, or nil if neither a TLS config nor a proxy
+// is configured for the kind, so that the cluster HTTP client is used.
+func newDedicatedHTTPClient(lc fx.Lifecycle, config *config.Config, kind model.NodeKind) *dedicatedHTTPClient {
+	proxyURL, ok := config.ProfilingProxyURLs[string(kind)]
+	if !ok {
+		proxyURL = config.ProfilingProxyURL
+	}
+	tlsConfig, hasTLSConfig := config.ComponentTLSConfigs[string(kind)]
+	if proxyURL == nil {
+		if !hasTLSConfig {
+			return nil
+		}
+		return &dedicatedHTTPClient{Client: httpc.NewHTTPClientWithTLSConfig(lc, tlsConfig), scheme: "https"}
+	}
+	scheme := "https"
+	if !hasTLSConfig {
+		tlsConfig = config.ClusterTLSConfig
+		scheme = config.GetClusterHTTPScheme()
+	}
+	return &dedicatedHTTPClient{Client: httpc.NewHTTPClientWithProxy(lc, tlsConfig, proxyURL), scheme: scheme}
+}
 
 type tikvFetcher struct {
-	client *tikv.Client
+	client        *tikv.Client
+	dedicated     *dedicatedHTTPClient // Used instead of the cluster HTTP client when set
+	authorization string               // The Authorization header of requests, which is not sent when it is empty
 }
 
 func (f *tikvFetcher) fetch(op *fetchOptions) ([]byte, error) {
-	return f.client.WithTimeout(maxProfilingTimeout).AddRequestHeader("Content-Type", "application/protobuf").SendGetRequest(op.ip, op.port, op.path)
+	client := f.client
+	if f.dedicated != nil {
+		client = client.WithHTTPClient(f.dedicated.Client, f.dedicated.scheme)
+	}
+	if op.ctx != nil {
+		client = client.WithContext(op.ctx)
+	}
+	if f.authorization != "" {
+		client = client.AddRequestHeader("Authorization", f.authorization)
+	}
+	res, err := client.WithTimeout(op.timeout).
+		AddRequestHeader("Content-Type", "application/protobuf").
+		AddRequestHeader("Accept-Encoding", "gzip").
+		Get(op.ip, op.port, op.path)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(op.limitBody(res), op.partial)
 }
 
 type tiflashFetcher struct {
-	client *tiflash.Client
+	client        *tiflash.Client
+	dedicated     *dedicatedHTTPClient // Used instead of the cluster HTTP client when set
+	authorization string               // The Authorization header of requests, which is not sent when it is empty
 }
 
 func (f *tiflashFetcher) fetch(op *fetchOptions) ([]byte, error) {
-	return f.client.WithTimeout(maxProfilingTimeout).AddRequestHeader("Content-Type", "application/protobuf").SendGetRequest(op.ip, op.port, op.path)
+	client := f.client
+	if f.dedicated != nil {
+		client = client.WithHTTPClient(f.dedicated.Client, f.dedicated.scheme)
+	}
+	if op.ctx != nil {
+		client = client.WithContext(op.ctx)
+	}
+	if f.authorization != "" {
+		client = client.AddRequestHeader("Authorization", f.authorization)
+	}
+	res, err := client.WithTimeout(op.timeout).
+		AddRequestHeader("Content-Type", "application/protobuf").
+		AddRequestHeader("Accept-Encoding", "gzip").
+		Get(op.ip, op.port, op.path)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(op.limitBody(res), op.partial)
 }
 
 type tidbFetcher struct {
-	client *tidb.Client
+	client        *tidb.Client
+	dedicated     *dedicatedHTTPClient // Used instead of the cluster HTTP client when set
+	authorization string               // The Authorization header of requests, which is not sent when it is empty
 }
 
 func (f *tidbFetcher) fetch(op *fetchOptions) ([]byte, error) {
-	return f.client.WithEnforcedStatusAPIAddress(op.ip, op.port).WithStatusAPITimeout(maxProfilingTimeout).SendGetRequest(op.path)
+	client := f.client
+	if f.dedicated != nil {
+		client = client.WithStatusAPIHTTPClient(f.dedicated.Client, f.dedicated.scheme)
+	}
+	if op.ctx != nil {
+		client = client.WithContext(op.ctx)
+	}
+	if f.authorization != "" {
+		client = client.AddStatusAPIRequestHeader("Authorization", f.authorization)
+	}
+	res, err := client.AddStatusAPIRequestHeader("Accept-Encoding", "gzip").
+		WithEnforcedStatusAPIAddress(op.ip, op.port).
+		WithStatusAPITimeout(op.timeout).
+		Get(op.path)
+	if err != nil {
+		return nil, err
+	}
+	// Partial results are not provided for TiDB, see supportsPartialResult.
+	return readBody(op.limitBody(res), nil)
 }
 
 type pdFetcher struct {
 	client              *pd.Client
 	statusAPIHTTPScheme string
+	dedicated           *dedicatedHTTPClient // Used instead of the cluster HTTP client when set
+	authorization       string               // The Authorization header of requests, which is not sent when it is empty
 }
 
 func (f *pdFetcher) fetch(op *fetchOptions) ([]byte, error) {
-	baseURL := fmt.Sprintf("%s://%s:%d", f.statusAPIHTTPScheme, op.ip, op.port)
-	return f.client.
-		WithTimeout(maxProfilingTimeout).
+	client := f.client
+	scheme := f.statusAPIHTTPScheme
+	if f.dedicated != nil {
+		client = client.WithHTTPClient(f.dedicated.Client, f.dedicated.scheme)
+		scheme = f.dedicated.scheme
+	}
+	if op.ctx != nil {
+		client = client.WithContext(op.ctx)
+	}
+	if f.authorization != "" {
+		client = client.AddRequestHeader("Authorization", f.authorization)
+	}
+	baseURL := fmt.Sprintf("%s://%s:%d", scheme, op.ip, op.port)
+	res, err := client.
+		AddRequestHeader("Accept-Encoding", "gzip").
+		WithTimeout(op.timeout).
 		WithBaseURL(baseURL).
 		WithoutPrefix(). // pprof API does not have /pd/api/v1 prefix
-		SendGetRequest(op.path)
+		Get(op.path)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(op.limitBody(res), op.partial)
 }
@@ -0,0 +1,622 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+	"github.com/pingcap/tidb-dashboard/pkg/httpc"
+	"github.com/pingcap/tidb-dashboard/pkg/pd"
+	"github.com/pingcap/tidb-dashboard/pkg/tiflash"
+	"github.com/pingcap/tidb-dashboard/pkg/tikv"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+// newTestCert creates a self-signed certificate which can be used by both the server and the client.
+func newTestCert(t *testing.T, commonName string) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
+
+// newTestMTLSServer starts a server which only accepts clients presenting the given certificate,
+// and responds the common name of the client certificate.
+func newTestMTLSServer(t *testing.T, cert tls.Certificate, pool *x509.CertPool) (string, int) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+	return u.Hostname(), port
+}
+
+func TestFetchersUseComponentTLSConfig(t *testing.T) {
+	tikvCert, tikvPool := newTestCert(t, "tikv")
+	pdCert, pdPool := newTestCert(t, "pd")
+	tikvIP, tikvPort := newTestMTLSServer(t, tikvCert, tikvPool)
+	pdIP, pdPort := newTestMTLSServer(t, pdCert, pdPool)
+
+	cfg := &config.Config{
+		ComponentTLSConfigs: map[string]*tls.Config{
+			"tikv": {Certificates: []tls.Certificate{tikvCert}, RootCAs: tikvPool, MinVersion: tls.VersionTLS12},
+			"pd":   {Certificates: []tls.Certificate{pdCert}, RootCAs: pdPool, MinVersion: tls.VersionTLS12},
+		},
+	}
+	lc := fxtest.NewLifecycle(t)
+	httpClient := httpc.NewHTTPClient(lc, cfg)
+	fts := buildFetchers(lc, tikv.NewTiKVClient(lc, httpClient, cfg), nil, pd.NewPDClient(lc, httpClient, cfg), nil, cfg)
+	// The clients keep the start context for sending requests, so it must not be cancelled.
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	resp, err := fts.tikv.fetch(&fetchOptions{ip: tikvIP, port: tikvPort, path: "/debug/pprof/profile"})
+	require.NoError(t, err)
+	require.Equal(t, "tikv", string(resp))
+
+	resp, err = fts.pd.fetch(&fetchOptions{ip: pdIP, port: pdPort, path: "/debug/pprof/profile"})
+	require.NoError(t, err)
+	require.Equal(t, "pd", string(resp))
+
+	// The TiKV client neither trusts the PD server nor is trusted by it.
+	_, err = fts.tikv.fetch(&fetchOptions{ip: pdIP, port: pdPort, path: "/debug/pprof/profile"})
+	require.Error(t, err)
+}
+
+func TestFetchersSendAuthorization(t *testing.T) {
+	cfg := &config.Config{
+		ProfilingAuthorizations: map[string]string{"tikv": "Bearer tikv-token"},
+	}
+	lc := fxtest.NewLifecycle(t)
+	httpClient := httpc.NewHTTPClient(lc, cfg)
+	mockTransport := httpmock.NewMockTransport()
+	httpClient.Transport = mockTransport
+	var mu sync.Mutex
+	authorizations := make(map[string]string)
+	responder := func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		authorizations[req.URL.Host] = req.Header.Get("Authorization")
+		return httpmock.NewBytesResponse(http.StatusOK, []byte("profile")), nil
+	}
+	mockTransport.RegisterResponder("GET", "http://127.0.0.1:20180/debug/pprof/profile", responder)
+	mockTransport.RegisterResponder("GET", "http://127.0.0.1:2379/debug/pprof/profile", responder)
+	fts := buildFetchers(lc, tikv.NewTiKVClient(lc, httpClient, cfg), nil, pd.NewPDClient(lc, httpClient, cfg), nil, cfg)
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	_, err := fts.tikv.fetch(&fetchOptions{ip: "127.0.0.1", port: 20180, path: "/debug/pprof/profile"})
+	require.NoError(t, err)
+	_, err = fts.pd.fetch(&fetchOptions{ip: "127.0.0.1", port: 2379, path: "/debug/pprof/profile"})
+	require.NoError(t, err)
+
+	require.Equal(t, "Bearer tikv-token", authorizations["127.0.0.1:20180"])
+	// No header is sent to kinds without a configured authorization.
+	require.Contains(t, authorizations, "127.0.0.1:2379")
+	require.Empty(t, authorizations["127.0.0.1:2379"])
+}
+
+func TestFetchersUseProxy(t *testing.T) {
+	newProxy := func(name string, requested *[]string, mu *sync.Mutex) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			// Requests through a proxy are sent with absolute URLs.
+			*requested = append(*requested, name+" "+r.URL.String())
+			_, _ = w.Write([]byte("profile"))
+		}))
+	}
+	var mu sync.Mutex
+	var requested []string
+	proxy := newProxy("proxy", &requested, &mu)
+	defer proxy.Close()
+	tikvProxy := newProxy("tikv-proxy", &requested, &mu)
+	defer tikvProxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	tikvProxyURL, err := url.Parse(tikvProxy.URL)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		ProfilingProxyURL:  proxyURL,
+		ProfilingProxyURLs: map[string]*url.URL{"tikv": tikvProxyURL},
+	}
+	lc := fxtest.NewLifecycle(t)
+	httpClient := httpc.NewHTTPClient(lc, cfg)
+	fts := buildFetchers(lc, tikv.NewTiKVClient(lc, httpClient, cfg), nil, pd.NewPDClient(lc, httpClient, cfg), nil, cfg)
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	// The targets are not reachable except through the proxies.
+	data, err := fts.tikv.fetch(&fetchOptions{ip: "192.0.2.1", port: 20180, path: "/debug/pprof/profile"})
+	require.NoError(t, err)
+	require.Equal(t, []byte("profile"), data)
+	data, err = fts.pd.fetch(&fetchOptions{ip: "192.0.2.2", port: 2379, path: "/debug/pprof/profile"})
+	require.NoError(t, err)
+	require.Equal(t, []byte("profile"), data)
+
+	require.Equal(t, []string{
+		"tikv-proxy http://192.0.2.1:20180/debug/pprof/profile",
+		"proxy http://192.0.2.2:2379/debug/pprof/profile",
+	}, requested)
+	require.Nil(t, newDedicatedHTTPClient(lc, &config.Config{}, model.NodeKindTiKV))
+}
+
+func TestFetchErrorHasHTTPStatus(t *testing.T) {
+	cfg := &config.Config{}
+	lc := fxtest.NewLifecycle(t)
+	httpClient := httpc.NewHTTPClient(lc, cfg)
+	mockTransport := httpmock.NewMockTransport()
+	httpClient.Transport = mockTransport
+	mockTransport.RegisterResponder("GET", "http://127.0.0.1:20180/debug/pprof/profile",
+		httpmock.NewStringResponder(http.StatusServiceUnavailable, "server is busy"))
+	fts := buildFetchers(lc, tikv.NewTiKVClient(lc, httpClient, cfg), nil, nil, nil, cfg)
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	s := newTestService(t)
+	s.fetchers.tikv = fts.tikv
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Len(t, tasks, 1)
+	require.Equal(t, TaskStateError, tasks[0].State)
+	require.Contains(t, tasks[0].Error, "503 Service Unavailable")
+	require.Contains(t, tasks[0].Error, "server is busy")
+}
+
+func TestFetchersDecodeGzipTransferEncoding(t *testing.T) {
+	cfg := &config.Config{}
+	lc := fxtest.NewLifecycle(t)
+	httpClient := httpc.NewHTTPClient(lc, cfg)
+	mockTransport := httpmock.NewMockTransport()
+	httpClient.Transport = mockTransport
+	goroutines := []byte("goroutine 1 [running]:\nmain.main()\n")
+	cpuProfile := newTestCPUProfile(t, map[string]int64{"main.work": 100})
+	gzipResponder := func(body []byte) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, "gzip", req.Header.Get("Accept-Encoding"))
+			var buf bytes.Buffer
+			zw := gzip.NewWriter(&buf)
+			_, _ = zw.Write(body)
+			_ = zw.Close()
+			res := httpmock.NewBytesResponse(http.StatusOK, buf.Bytes())
+			res.Header.Set("Content-Encoding", "gzip")
+			return res, nil
+		}
+	}
+	mockTransport.RegisterResponder("GET", "http://127.0.0.1:2379/debug/pprof/goroutine", gzipResponder(goroutines))
+	mockTransport.RegisterResponder("GET", "http://127.0.0.1:2379/debug/pprof/profile", gzipResponder(cpuProfile))
+	fts := buildFetchers(lc, nil, nil, pd.NewPDClient(lc, httpClient, cfg), nil, cfg)
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	data, err := fts.pd.fetch(&fetchOptions{ip: "127.0.0.1", port: 2379, path: "/debug/pprof/goroutine"})
+	require.NoError(t, err)
+	require.Equal(t, goroutines, data)
+
+	// Only the transfer encoding is decoded, the gzip of the protobuf profile itself is kept.
+	data, err = fts.pd.fetch(&fetchOptions{ip: "127.0.0.1", port: 2379, path: "/debug/pprof/profile"})
+	require.NoError(t, err)
+	require.Equal(t, cpuProfile, data)
+}
+
+func TestSharedFetcherDedupesSameEndpoint(t *testing.T) {
+	s := newTestService(t)
+	snapshots := [][]byte{
+		newTestHeapProfile(t, map[string]int64{"main.cache.Put": 100}),
+		newTestHeapProfile(t, map[string]int64{"main.cache.Put": 300}),
+	}
+	release := make(chan struct{})
+	var fetched int32
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		require.Equal(t, "/debug/pprof/heap", op.path)
+		n := atomic.AddInt32(&fetched, 1)
+		if n == 1 {
+			<-release
+		}
+		return snapshots[n-1], nil
+	}}
+	go func() {
+		// Leave time for both tasks to request the first snapshot.
+		time.Sleep(200 * time.Millisecond)
+		close(release)
+	}()
+
+	// Both heap and the first snapshot of heap_diff resolve to /debug/pprof/heap of the same target.
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap, ProfilingTypeHeapDiff},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Equal(t, int32(2), fetched)
+	require.Len(t, tasks, 2)
+
+	heap, err := ioutil.ReadFile(tasks[0].FilePath)
+	require.NoError(t, err)
+	require.Equal(t, snapshots[0], heap)
+
+	content, err := ioutil.ReadFile(tasks[1].FilePath)
+	require.NoError(t, err)
+	diff, err := parseProtobufProfile(content)
+	require.NoError(t, err)
+	flat, _ := flatByFunction(diff, defaultSampleIndex(diff))
+	require.Equal(t, int64(200), flat["main.cache.Put"])
+}
+
+func TestSharedFetcherDoesNotCacheFinishedFetches(t *testing.T) {
+	var fetched int32
+	fts := (&fetchers{tidb: &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return []byte(strconv.Itoa(int(atomic.AddInt32(&fetched, 1)))), nil
+	}}}).shared()
+	op := &fetchOptions{ip: "127.0.0.1", port: 10080, path: "/debug/pprof/heap"}
+	first, err := fts.tidb.fetch(op)
+	require.NoError(t, err)
+	second, err := fts.tidb.fetch(op)
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), first)
+	require.Equal(t, []byte("2"), second)
+}
+
+func TestSharedFetcherOutlivesCancelledCaller(t *testing.T) {
+	var fetched int32
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	fts := (&fetchers{tikv: &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		atomic.AddInt32(&fetched, 1)
+		started <- struct{}{}
+		select {
+		case <-release:
+		case <-op.ctx.Done():
+			return nil, op.ctx.Err()
+		}
+		if op.partial != nil {
+			_, _ = op.partial.Write([]byte("profile"))
+		}
+		return []byte("profile"), nil
+	}}}).shared()
+	f := fts.tikv.(*sharedFetcher)
+	callers := func() int {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for _, call := range f.calls {
+			return call.callers
+		}
+		return 0
+	}
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	fetch := func(ctx context.Context, partial *partialBuffer) chan result {
+		ch := make(chan result, 1)
+		go func() {
+			data, err := fts.tikv.fetch(&fetchOptions{ctx: ctx, ip: "127.0.0.1", port: 20180, path: "/debug/pprof/profile", partial: partial})
+			ch <- result{data, err}
+		}()
+		return ch
+	}
+
+	// The first caller is cancelled, while the second caller still gets the profile.
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	first := fetch(firstCtx, &partialBuffer{})
+	<-started
+	secondPartial := &partialBuffer{}
+	second := fetch(context.Background(), secondPartial)
+	require.Eventually(t, func() bool { return callers() == 2 }, time.Second, time.Millisecond)
+	cancelFirst()
+	r := <-first
+	require.Equal(t, context.Canceled, r.err)
+	close(release)
+	r = <-second
+	require.NoError(t, r.err)
+	require.Equal(t, []byte("profile"), r.data)
+	require.Equal(t, []byte("profile"), secondPartial.bytes())
+	require.Equal(t, int32(1), atomic.LoadInt32(&fetched))
+
+	// The fetch is cancelled once all callers are cancelled, and a later caller starts a new fetch.
+	release = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := fetch(ctx, nil)
+	<-started
+	cancel()
+	r = <-cancelled
+	require.Equal(t, context.Canceled, r.err)
+	require.Eventually(t, func() bool { return callers() == 0 }, time.Second, time.Millisecond)
+	later := fetch(context.Background(), nil)
+	<-started
+	close(release)
+	r = <-later
+	require.NoError(t, r.err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&fetched))
+}
+
+func TestFetchTimeout(t *testing.T) {
+	s := newTestService(t)
+	cfg := &config.Config{}
+	lc := fxtest.NewLifecycle(t)
+	httpClient := httpc.NewHTTPClient(lc, cfg)
+	mockTransport := httpmock.NewMockTransport()
+	httpClient.Transport = mockTransport
+	// The unresponsive TiKV never responds until the request is cancelled.
+	mockTransport.RegisterResponder("GET", "http://127.0.0.1:20180/debug/pprof/profile", func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+	s.fetchers.tikv = &tikvFetcher{client: tikv.NewTiKVClient(lc, httpClient, cfg)}
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	start := time.Now()
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+		RequestTimeoutSecs:     1,
+	})
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.Equal(t, TaskStateError, group.State)
+	require.Len(t, tasks, 1)
+	require.Equal(t, TaskStateError, tasks[0].State)
+	require.Contains(t, tasks[0].Error, "timeout")
+
+	// The request is allowed as long as the task, even if it is longer than a few minutes.
+	var requestTimeout time.Duration
+	s.fetchers.tikv = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		requestTimeout = op.timeout
+		return newTestCPUProfile(t, map[string]int64{"main.main": 1e9}), nil
+	}}
+	tasks, _ = runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180}},
+		DurationSecs:           600,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, 630*time.Second, requestTimeout)
+
+	require.Equal(t, 40*time.Second, s.fetchTimeout(10, 0))
+	s.params.Config = &config.Config{ProfilingRequestTimeoutSlack: 5 * time.Second}
+	require.Equal(t, 15*time.Second, s.fetchTimeout(10, 0))
+	require.Equal(t, 12*time.Second, s.fetchTimeout(10, 2))
+}
+
+func TestTraceProfiling(t *testing.T) {
+	s := newTestService(t)
+	cfg := &config.Config{}
+	lc := fxtest.NewLifecycle(t)
+	httpClient := httpc.NewHTTPClient(lc, cfg)
+	mockTransport := httpmock.NewMockTransport()
+	httpClient.Transport = mockTransport
+	trace := []byte("go 1.19 trace\x00\x00\x00\x00")
+	mockTransport.RegisterResponder("GET", "http://127.0.0.1:2379/debug/pprof/trace?seconds=1",
+		httpmock.NewBytesResponder(http.StatusOK, trace))
+	s.fetchers.pd = &pdFetcher{client: pd.NewPDClient(lc, httpClient, cfg), statusAPIHTTPScheme: "http"}
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindPD, DisplayName: "127.0.0.1:2379", IP: "127.0.0.1", Port: 2379},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeTrace},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Len(t, tasks, 2)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, RawDataTypeTrace, tasks[0].RawDataType)
+	require.Equal(t, ".trace", filepath.Ext(tasks[0].FilePath))
+	data, err := s.results.readResult(&tasks[0])
+	require.NoError(t, err)
+	require.Equal(t, trace, data)
+	// TiKV does not support execution traces.
+	require.Equal(t, TaskStateSkipped, tasks[1].State)
+	require.Equal(t, SkipReasonUnsupportedProfilingType, tasks[1].SkipReason)
+}
+
+func TestRequestURL(t *testing.T) {
+	s := newTestService(t)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if op.ip == "127.0.0.2" {
+			return nil, errors.New("no responder found")
+		}
+		return newTestCPUProfile(t, map[string]int64{"main.work": 10000000}), nil
+	}}
+	s.fetchers.tikv = s.fetchers.tidb
+
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10081},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.3:20160", IP: "127.0.0.3", Port: 20180},
+		},
+		DurationSecs:           3,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+		ProfilingTypesByKind:   map[model.NodeKind]TaskProfilingTypeList{model.NodeKindTiKV: {ProfilingTypeHeap}},
+	})
+	require.Len(t, tasks, 3)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, "127.0.0.1:10080/debug/pprof/profile?seconds=3", tasks[0].RequestURL)
+	// The URL is recorded for failed tasks, which is where it is useful.
+	require.Equal(t, TaskStateError, tasks[1].State)
+	require.Equal(t, "127.0.0.2:10081/debug/pprof/profile?seconds=3", tasks[1].RequestURL)
+	// Nothing is requested for skipped tasks.
+	require.Equal(t, TaskStateSkipped, tasks[2].State)
+	require.Empty(t, tasks[2].RequestURL)
+}
+
+func TestPprofPathPrefix(t *testing.T) {
+	s := newTestService(t)
+	var mu sync.Mutex
+	paths := make(map[model.NodeKind]string)
+	newFetcher := func(kind model.NodeKind) profileFetcher {
+		return &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			paths[kind] = op.path
+			return newTestCPUProfile(t, map[string]int64{"main.work": 10000000}), nil
+		}}
+	}
+	s.fetchers.tidb = newFetcher(model.NodeKindTiDB)
+	s.fetchers.pd = newFetcher(model.NodeKindPD)
+	s.fetchers.pprofPathPrefixes = map[model.NodeKind]string{model.NodeKindTiDB: "/custom/pprof/"}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindPD, DisplayName: "127.0.0.1:2379", IP: "127.0.0.1", Port: 2379},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Equal(t, "/custom/pprof/profile?seconds=1", paths[model.NodeKindTiDB])
+	require.Equal(t, "127.0.0.1:10080/custom/pprof/profile?seconds=1", tasks[0].RequestURL)
+	// Kinds without a prefix are fetched from the default path.
+	require.Equal(t, "/debug/pprof/profile?seconds=1", paths[model.NodeKindPD])
+
+	require.Equal(t, "/custom/pprof/", s.fetchers.pingPath(model.NodeKindTiDB))
+	require.Equal(t, "/debug/pprof/", s.fetchers.pingPath(model.NodeKindPD))
+}
+
+func TestTiFlashProfiling(t *testing.T) {
+	s := newTestService(t)
+	cfg := &config.Config{}
+	lc := fxtest.NewLifecycle(t)
+	httpClient := httpc.NewHTTPClient(lc, cfg)
+	mockTransport := httpmock.NewMockTransport()
+	httpClient.Transport = mockTransport
+	// The CPU profile is fetched from the status port of the TiFlash proxy.
+	mockTransport.RegisterResponder("GET", "http://127.0.0.1:20292/debug/pprof/profile?seconds=1",
+		httpmock.NewBytesResponder(http.StatusOK, newTestCPUProfile(t, map[string]int64{"raftstore::store::fsm::apply": 10000000})))
+	s.fetchers.tiflash = &tiflashFetcher{client: tiflash.NewTiFlashClient(lc, httpClient, cfg)}
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiFlash, DisplayName: "127.0.0.1:3930", IP: "127.0.0.1", Port: 20292}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU, ProfilingTypeHeap},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Len(t, tasks, 2)
+	require.Equal(t, ProfilingTypeCPU, tasks[0].ProfilingType)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, RawDataTypeProtobuf, tasks[0].RawDataType)
+	// Other profiling types are not supported by TiFlash.
+	require.Equal(t, TaskStateSkipped, tasks[1].State)
+	require.Equal(t, SkipReasonUnsupportedProfilingType, tasks[1].SkipReason)
+}
+
+func TestCustomProfiling(t *testing.T) {
+	s := newTestService(t)
+	cfg := &config.Config{}
+	lc := fxtest.NewLifecycle(t)
+	httpClient := httpc.NewHTTPClient(lc, cfg)
+	mockTransport := httpmock.NewMockTransport()
+	httpClient.Transport = mockTransport
+	custom := []byte("custom profile")
+	mockTransport.RegisterResponder("GET", "http://127.0.0.1:2379/debug/pprof/custom?debug=1&seconds=2",
+		httpmock.NewBytesResponder(http.StatusOK, custom))
+	s.fetchers.pd = &pdFetcher{client: pd.NewPDClient(lc, httpClient, cfg), statusAPIHTTPScheme: "http"}
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	req := &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindPD, DisplayName: "127.0.0.1:2379", IP: "127.0.0.1", Port: 2379},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCustom},
+		CustomPprofPath:        "/debug/pprof/custom?debug=1",
+		CustomPprofSeconds:     2,
+	}
+	tasks, group := runTestGroup(t, s, req)
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Len(t, tasks, 2)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, RawDataTypeRaw, tasks[0].RawDataType)
+	require.Equal(t, "/debug/pprof/custom?debug=1&seconds=2", tasks[0].CustomPath)
+	data, err := s.results.readResult(&tasks[0])
+	require.NoError(t, err)
+	require.Equal(t, custom, data)
+	// TiKV does not support custom profiles.
+	require.Equal(t, TaskStateSkipped, tasks[1].State)
+
+	// Paths which may reach other APIs or hosts are rejected before any task is created.
+	for _, path := range []string{
+		"",
+		"/debug/pprof/",
+		"/status",
+		"/debug/pprof/../../status",
+		"/debug/pprof//custom",
+		"http://10.0.0.1:10080/debug/pprof/custom",
+		"//10.0.0.1:10080/debug/pprof/custom",
+		"/debug/pprof/custom#fragment",
+	} {
+		req.CustomPprofPath = path
+		_, err := s.startGroup(context.Background(), req)
+		require.True(t, errorx.IsOfType(err, rest.ErrBadRequest), path)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"sort"
+	"time"
+
+	"github.com/ReneKroon/ttlcache/v2"
+	"github.com/google/pprof/profile"
+)
+
+const (
+	flameGraphCacheTTL       = 10 * time.Minute
+	flameGraphCacheSizeLimit = 64
+
+	flameGraphWidth       = 1200.0
+	flameGraphFrameHeight = 16.0
+	flameGraphFontSize    = 12.0
+	flameGraphCharWidth   = 7.0
+	// Frames narrower than this are not rendered to keep the SVG small.
+	flameGraphMinFrameWidth = 0.5
+)
+
+func newFlameGraphCache() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SkipTTLExtensionOnHit(true)
+	_ = c.SetTTL(flameGraphCacheTTL)
+	c.SetCacheSizeLimit(flameGraphCacheSizeLimit)
+	return c
+}
+
+// flameGraphOfTask renders the result of a finished task as an SVG flame graph. Rendered flame graphs are cached.
+// The attempt is a part of the cache key since the result is replaced in place when the task is refreshed.
+func (s *Service) flameGraphOfTask(taskID uint) ([]byte, error) {
+	var task TaskModel
+	if err := s.params.LocalStore.Where("id = ? AND state = ?", taskID, TaskStateFinish).First(&task).Error; err != nil {
+		return nil, err
+	}
+	if task.RawDataType != RawDataTypeProtobuf {
+		return nil, ErrUnsupportedProfilingType.New("flame graph is not supported for %s profiles in %s format", task.ProfilingType, task.RawDataType)
+	}
+
+	cacheKey := fmt.Sprintf("%d/%d", task.ID, task.Attempt)
+	if svg, err := s.flameGraphCache.Get(cacheKey); err == nil {
+		return svg.([]byte), nil
+	}
+	content, err := s.results.readResult(&task)
+	if err != nil {
+		return nil, err
+	}
+	p, err := parseProtobufProfile(content)
+	if err != nil {
+		return nil, err
+	}
+	svg := renderFlameGraph(p, fmt.Sprintf("%s profile of %s", task.ProfilingType, task.Target.DisplayName))
+	_ = s.flameGraphCache.Set(cacheKey, svg)
+	return svg, nil
+}
+
+type flameGraphNode struct {
+	name     string
+	value    int64
+	children map[string]*flameGraphNode
+}
+
+func (n *flameGraphNode) child(name string) *flameGraphNode {
+	if n.children == nil {
+		n.children = make(map[string]*flameGraphNode)
+	}
+	c, ok := n.children[name]
+	if !ok {
+		c = &flameGraphNode{name: name}
+		n.children[name] = c
+	}
+	return c
+}
+
+// sortedChildren returns children ordered by name, so that the rendered flame graph is deterministic.
+func (n *flameGraphNode) sortedChildren() []*flameGraphNode {
+	children := make([]*flameGraphNode, 0, len(n.children))
+	for _, c := range n.children {
+		children = append(children, c)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].name < children[j].name
+	})
+	return children
+}
+
+func (n *flameGraphNode) depth() int {
+	d := 0
+	for _, c := range n.children {
+		if cd := c.depth(); cd > d {
+			d = cd
+		}
+	}
+	return d + 1
+}
+
+// buildFlameGraph merges the stacks of all samples into a tree from the root frame to the leaf frames,
+// using the value which pprof displays by default.
+func buildFlameGraph(p *profile.Profile) *flameGraphNode {
+	root := &flameGraphNode{name: "root"}
+	sampleIndex := defaultSampleIndex(p)
+	for _, s := range p.Sample {
+		if sampleIndex < 0 || sampleIndex >= len(s.Value) || s.Value[sampleIndex] <= 0 {
+			continue
+		}
+		v := s.Value[sampleIndex]
+		root.value += v
+		node := root
+		// Locations are ordered from the leaf, and lines in a location are ordered from the innermost inlined function.
+		for i := len(s.Location) - 1; i >= 0; i-- {
+			loc := s.Location[i]
+			if len(loc.Line) == 0 {
+				node = node.child(fmt.Sprintf("0x%x", loc.Address))
+				node.value += v
+				continue
+			}
+			for j := len(loc.Line) - 1; j >= 0; j-- {
+				name := "<unknown>"
+				if loc.Line[j].Function != nil {
+					name = loc.Line[j].Function.Name
+				}
+				node = node.child(name)
+				node.value += v
+			}
+		}
+	}
+	return root
+}
+
+func renderFlameGraph(p *profile.Profile, title string) []byte {
+	root := buildFlameGraph(p)
+	unit := ""
+	if idx := defaultSampleIndex(p); idx >= 0 && idx < len(p.SampleType) {
+		unit = p.SampleType[idx].Unit
+	}
+	depth := root.depth()
+	height := float64(depth+2) * flameGraphFrameHeight
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<?xml version="1.0" standalone="no"?>`+"\n")
+	fmt.Fprintf(&buf, `<svg version="1.1" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" xmlns="http://www.w3.org/2000/svg">`+"\n",
+		flameGraphWidth, height, flameGraphWidth, height)
+	fmt.Fprintf(&buf, `<style>text { font-family: monospace; font-size: %.0fpx; }</style>`+"\n", flameGraphFontSize)
+	fmt.Fprintf(&buf, `<text x="%.1f" y="%.1f">%s</text>`+"\n", 4.0, flameGraphFontSize, html.EscapeString(title))
+	if root.value > 0 {
+		scale := flameGraphWidth / float64(root.value)
+		renderFlameGraphNode(&buf, root, root.value, unit, 0, height-flameGraphFrameHeight, scale)
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+// renderFlameGraphNode renders a frame and its children above it. The root frame is at the bottom.
+func renderFlameGraphNode(buf *bytes.Buffer, n *flameGraphNode, total int64, unit string, x, y, scale float64) {
+	width := float64(n.value) * scale
+	if width < flameGraphMinFrameWidth {
+		return
+	}
+	name := html.EscapeString(n.name)
+	fmt.Fprintf(buf, `<g><title>%s (%d %s, %.2f%%)</title>`, name, n.value, html.EscapeString(unit), float64(n.value)*100/float64(total))
+	fmt.Fprintf(buf, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" rx="2" ry="2"/>`,
+		x, y, width, flameGraphFrameHeight-1, flameGraphColor(n.name))
+	if maxChars := int((width - 6) / flameGraphCharWidth); maxChars >= 3 {
+		label := n.name
+		if len(label) > maxChars {
+			label = label[:maxChars-2] + ".."
+		}
+		fmt.Fprintf(buf, `<text x="%.1f" y="%.1f">%s</text>`, x+3, y+flameGraphFontSize, html.EscapeString(label))
+	}
+	buf.WriteString("</g>\n")
+
+	for _, c := range n.sortedChildren() {
+		renderFlameGraphNode(buf, c, total, unit, x, y-flameGraphFrameHeight, scale)
+		x += float64(c.value) * scale
+	}
+}
+
+// flameGraphColor returns a warm color which is stable for the same function name.
+func flameGraphColor(name string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	v := h.Sum32()
+	return fmt.Sprintf("rgb(%d,%d,%d)", 205+v%50, 80+(v>>8)%150, 40+(v>>16)%50)
+}
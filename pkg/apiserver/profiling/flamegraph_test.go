@@ -0,0 +1,83 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFlameGraph(t *testing.T) {
+	mainFn := &profile.Function{ID: 1, Name: "main.main"}
+	workFn := &profile.Function{ID: 2, Name: "main.work"}
+	inlinedFn := &profile.Function{ID: 3, Name: "main.inlined"}
+	mainLoc := &profile.Location{ID: 1, Line: []profile.Line{{Function: mainFn}}}
+	// The inlined function is the innermost one.
+	workLoc := &profile.Location{ID: 2, Line: []profile.Line{{Function: inlinedFn}, {Function: workFn}}}
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "cpu", Unit: "nanoseconds"}},
+		Sample: []*profile.Sample{
+			{Location: []*profile.Location{workLoc, mainLoc}, Value: []int64{30}},
+			{Location: []*profile.Location{mainLoc}, Value: []int64{10}},
+		},
+		Location: []*profile.Location{mainLoc, workLoc},
+		Function: []*profile.Function{mainFn, workFn, inlinedFn},
+	}
+
+	root := buildFlameGraph(p)
+	require.Equal(t, int64(40), root.value)
+	require.Equal(t, 4, root.depth())
+	main := root.children["main.main"]
+	require.Equal(t, int64(40), main.value)
+	work := main.children["main.work"]
+	require.Equal(t, int64(30), work.value)
+	require.Equal(t, int64(30), work.children["main.inlined"].value)
+}
+
+func TestFlameGraphOfTask(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 30000000, "main.<idle>": 10000000})
+	task := newTestFinishedTask(t, s, ProfilingTypeCPU, RawDataTypeProtobuf, content)
+
+	svg, err := s.flameGraphOfTask(task.ID)
+	require.NoError(t, err)
+	requireWellFormedXML(t, svg)
+	require.Contains(t, string(svg), "<svg")
+	require.Contains(t, string(svg), "main.work")
+	// Function names are escaped.
+	require.Contains(t, string(svg), "main.&lt;idle&gt;")
+
+	// The rendered flame graph is cached, so the result file is not read again.
+	require.NoError(t, os.Remove(task.FilePath))
+	cached, err := s.flameGraphOfTask(task.ID)
+	require.NoError(t, err)
+	require.Equal(t, svg, cached)
+
+	// A refreshed task is rendered again.
+	task.Attempt++
+	require.NoError(t, s.params.LocalStore.Save(task).Error)
+	_, err = s.flameGraphOfTask(task.ID)
+	require.Error(t, err)
+
+	textTask := newTestFinishedTask(t, s, ProfilingTypeGoroutine, RawDataTypeText, []byte("goroutine profile: total 1"))
+	_, err = s.flameGraphOfTask(textTask.ID)
+	require.True(t, errorx.IsOfType(err, ErrUnsupportedProfilingType))
+}
+
+func requireWellFormedXML(t *testing.T, data []byte) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/google/pprof/profile"
+)
+
+// heapDiffSampleType is the sample type displayed by default for a heap diff, so that
+// the result is attributed to the allocation sites of leaked objects.
+const heapDiffSampleType = "inuse_objects"
+
+// fetchHeapDiff fetches two heap profiles which are `gapSecs` apart and returns their difference.
+func (f *fetcher) fetchHeapDiff(url string, gapSecs uint) ([]byte, error) {
+	fetchOp := &fetchOptions{ctx: f.ctx, ip: f.target.IP, port: f.target.Port, path: url, timeout: f.timeout}
+	base, err := (*f.profileFetcher).fetch(fetchOp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the first heap profile: %v", err)
+	}
+
+	timer := time.NewTimer(time.Duration(gapSecs) * time.Second)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-f.ctx.Done():
+		return nil, f.ctx.Err()
+	}
+
+	current, err := (*f.profileFetcher).fetch(fetchOp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the second heap profile: %v", err)
+	}
+	return diffHeapProfiles(base, current)
+}
+
+// diffHeapProfiles subtracts the base heap profile from the current one. The result is a regular
+// pprof profile, so that it can be rendered or analyzed like other profiles.
+func diffHeapProfiles(baseContent, currentContent []byte) ([]byte, error) {
+	base, err := parseProtobufProfile(baseContent)
+	if err != nil {
+		return nil, err
+	}
+	current, err := parseProtobufProfile(currentContent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := current.SampleIndexByName(heapDiffSampleType); err != nil {
+		return nil, fmt.Errorf("profile is not a heap profile: %v", err)
+	}
+
+	base.Scale(-1)
+	diff, err := profile.Merge([]*profile.Profile{current, base})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff heap profiles: %v", err)
+	}
+	diff.DefaultSampleType = heapDiffSampleType
+	diff.TimeNanos = current.TimeNanos
+	diff.DurationNanos = current.TimeNanos - base.TimeNanos
+
+	buf := bytes.Buffer{}
+	if err := diff.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
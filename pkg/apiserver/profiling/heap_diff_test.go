@@ -0,0 +1,57 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"io/ioutil"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+func TestHeapDiff(t *testing.T) {
+	s := newTestService(t)
+	snapshots := [][]byte{
+		newTestHeapProfile(t, map[string]int64{
+			"main.cache.Put": 100,
+			"main.newBuffer": 50,
+		}),
+		newTestHeapProfile(t, map[string]int64{
+			"main.cache.Put": 900,
+			"main.newBuffer": 50,
+		}),
+	}
+	var fetched int32
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		require.Equal(t, "/debug/pprof/heap", op.path)
+		return snapshots[atomic.AddInt32(&fetched, 1)-1], nil
+	}}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		DurationSecs:           0,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeapDiff},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Len(t, tasks, 1)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, RawDataTypeProtobuf, tasks[0].RawDataType)
+	require.Equal(t, int32(2), fetched)
+
+	content, err := ioutil.ReadFile(tasks[0].FilePath)
+	require.NoError(t, err)
+	diff, err := parseProtobufProfile(content)
+	require.NoError(t, err)
+	require.Equal(t, "inuse_objects", diff.DefaultSampleType)
+	flat, total := flatByFunction(diff, defaultSampleIndex(diff))
+	require.Equal(t, int64(800), flat["main.cache.Put"])
+	require.Zero(t, flat["main.newBuffer"])
+	require.Equal(t, int64(800), total)
+
+	svg, err := convertProtobufToSVG(content, tasks[0])
+	require.NoError(t, err)
+	require.Contains(t, string(svg), "<svg")
+}
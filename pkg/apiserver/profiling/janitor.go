@@ -0,0 +1,251 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+const (
+	janitorInterval  = time.Hour
+	janitorBatchSize = 100
+)
+
+var ErrStorageBudgetExceeded = ErrNS.NewType("storage_budget_exceeded")
+
+// retention returns the time for which stopped task groups are kept, or 0 if they are kept forever.
+func (s *Service) retention() time.Duration {
+	if s.params.Config != nil && s.params.Config.ProfilingRetention > 0 {
+		return s.params.Config.ProfilingRetention
+	}
+	return 0
+}
+
+// storageBudget returns the total size in bytes of stored results, or 0 if it is not limited.
+func (s *Service) storageBudget() int64 {
+	if s.params.Config != nil && s.params.Config.ProfilingStorageBudget > 0 {
+		return s.params.Config.ProfilingStorageBudget
+	}
+	return 0
+}
+
+// storedBytesExpr is the stored size of a finished task. The size before compression is used for results saved by
+// earlier versions, whose stored sizes are unknown.
+const storedBytesExpr = "CASE WHEN stored_size_bytes > 0 THEN stored_size_bytes ELSE size_bytes END"
+
+type groupStoredBytes struct {
+	TaskGroupID uint
+	Bytes       int64
+}
+
+// storedBytesByGroup returns the stored sizes of the results of task groups, ordered by the IDs of task groups.
+func (s *Service) storedBytesByGroup() ([]groupStoredBytes, error) {
+	var groups []groupStoredBytes
+	err := s.params.LocalStore.Model(&TaskModel{}).
+		Select("task_group_id, SUM("+storedBytesExpr+") AS bytes").
+		Where("state = ?", TaskStateFinish).
+		Group("task_group_id").
+		Order("task_group_id ASC").
+		Scan(&groups).Error
+	return groups, err
+}
+
+// reserveStorage makes room for the results of a profiling request when the storage budget is set, by deleting
+// the oldest stopped task groups. The size of the results is estimated from recently finished tasks. Nothing is
+// deleted and the request is rejected if there is not enough room even after deleting all stopped task groups.
+// The reserved bytes are returned, which must be released by releaseStorage once the task group is stopped.
+func (s *Service) reserveStorage(req *StartRequest) (int64, error) {
+	if s.storageBudget() == 0 {
+		return 0, nil
+	}
+	estimate, err := s.estimateGroup(req)
+	if err != nil {
+		return 0, err
+	}
+	return s.reserveBytes(estimate.ApproxBytes)
+}
+
+// reserveTaskStorage is the same as reserveStorage for tasks of a task group to be run again, e.g. to be refreshed
+// or retried. The task group must be marked as running, so that it is not deleted to make room for its own results.
+func (s *Service) reserveTaskStorage(tasks []TaskModel, groupDurationSecs uint) (int64, error) {
+	if s.storageBudget() == 0 {
+		return 0, nil
+	}
+	approxBytes, err := s.estimateTasks(tasks, groupDurationSecs)
+	if err != nil {
+		return 0, err
+	}
+	return s.reserveBytes(approxBytes)
+}
+
+// reserveBytes deletes the oldest stopped task groups until the estimated bytes fit in the storage budget, counting
+// the bytes reserved for running task groups whose results are not saved yet. Reservations are serialized, so that
+// concurrent ones do not exceed the budget together or delete the same task groups.
+func (s *Service) reserveBytes(approxBytes int64) (int64, error) {
+	s.storageMu.Lock()
+	defer s.storageMu.Unlock()
+	budget := s.storageBudget()
+	groups, err := s.storedBytesByGroup()
+	if err != nil {
+		return 0, err
+	}
+	// Running task groups and task groups with results registered as baselines cannot be deleted.
+	var keptGroupIDs []uint
+	err = s.params.LocalStore.Model(&TaskGroupModel{}).
+		Where("state = ? OR id IN (?)", TaskStateRunning, s.pinnedGroupIDs()).
+		Pluck("id", &keptGroupIDs).Error
+	if err != nil {
+		return 0, err
+	}
+	kept := make(map[uint]struct{}, len(keptGroupIDs))
+	for _, id := range keptGroupIDs {
+		kept[id] = struct{}{}
+	}
+
+	used, pinned := s.reservedBytes, s.reservedBytes
+	evictable := make([]groupStoredBytes, 0, len(groups))
+	for _, group := range groups {
+		used += group.Bytes
+		if _, ok := kept[group.TaskGroupID]; ok {
+			pinned += group.Bytes
+		} else {
+			evictable = append(evictable, group)
+		}
+	}
+	if used+approxBytes <= budget {
+		s.reservedBytes += approxBytes
+		return approxBytes, nil
+	}
+	if pinned+approxBytes > budget {
+		return 0, ErrStorageBudgetExceeded.New("the results are estimated to take %d bytes, which exceed the storage budget of %d bytes even after deleting all stopped task groups which are not used by baselines",
+			approxBytes, budget)
+	}
+	for _, group := range evictable {
+		if used+approxBytes <= budget {
+			break
+		}
+		if err := s.evictGroup(group.TaskGroupID); err != nil {
+			if errorx.IsOfType(err, ErrGroupPinned) {
+				// A baseline is registered from the task group in the meantime.
+				continue
+			}
+			if !errorx.IsOfType(err, rest.ErrNotFound) {
+				return 0, err
+			}
+			// The task group is deleted by others in the meantime, e.g. by the user or the janitor.
+		} else {
+			log.Info("profiling task group is deleted to make room for new results",
+				zap.Uint("task_group_id", group.TaskGroupID), zap.Int64("bytes", group.Bytes))
+		}
+		used -= group.Bytes
+	}
+	s.reservedBytes += approxBytes
+	return approxBytes, nil
+}
+
+// releaseStorage stops counting the bytes reserved by reserveStorage, once the results are saved or not started.
+func (s *Service) releaseStorage(reservedBytes int64) {
+	if reservedBytes == 0 {
+		return
+	}
+	s.storageMu.Lock()
+	defer s.storageMu.Unlock()
+	s.reservedBytes -= reservedBytes
+}
+
+// errInterruptedByRestart is the error of tasks which were running when the previous process stopped.
+const errInterruptedByRestart = "interrupted by restart"
+
+// markInterruptedGroups fails the tasks which are still marked as running when the service starts, since they were
+// run by a previous process and will never stop. The states of their task groups are recomputed from the tasks.
+func (s *Service) markInterruptedGroups() error {
+	var groups []TaskGroupModel
+	if err := s.params.LocalStore.Where("state = ?", TaskStateRunning).Find(&groups).Error; err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	return s.params.LocalStore.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&TaskModel{}).
+			Where("state = ?", TaskStateRunning).
+			Updates(map[string]interface{}{"state": TaskStateError, "error": errInterruptedByRestart}).Error
+		if err != nil {
+			return err
+		}
+		for _, group := range groups {
+			var states []TaskState
+			if err := tx.Model(&TaskModel{}).Where("task_group_id = ?", group.ID).Pluck("state", &states).Error; err != nil {
+				return err
+			}
+			state := TaskStateError
+			if len(states) > 0 {
+				state = taskGroupState(states)
+			}
+			if err := tx.Model(&TaskGroupModel{}).Where("id = ?", group.ID).Update("state", state).Error; err != nil {
+				return err
+			}
+			log.Warn("profiling task group is interrupted by restart", zap.Uint("task_group_id", group.ID))
+		}
+		return nil
+	})
+}
+
+func (s *Service) janitorLoop(ctx context.Context) {
+	ticker := time.NewTicker(janitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.deleteExpiredGroups(time.Now()); err != nil {
+				log.Warn("failed to delete expired profiling task groups", zap.Error(err))
+			}
+		}
+	}
+}
+
+// deleteExpiredGroups deletes stopped task groups started before the retention, in batches so that
+// each transaction is short. Task groups with results registered as baselines are kept. Nothing is deleted if the
+// retention is not set. The number of deleted task groups is returned.
+func (s *Service) deleteExpiredGroups(now time.Time) (int, error) {
+	retention := s.retention()
+	if retention == 0 {
+		return 0, nil
+	}
+	expiredBefore := now.Add(-retention).Unix()
+	deleted := 0
+	for {
+		var taskGroupIDs []uint
+		err := s.params.LocalStore.Model(&TaskGroupModel{}).
+			Where("started_at < ? AND state <> ? AND id NOT IN (?)", expiredBefore, TaskStateRunning, s.pinnedGroupIDs()).
+			Order("id ASC").
+			Limit(janitorBatchSize).
+			Pluck("id", &taskGroupIDs).Error
+		if err != nil {
+			return deleted, err
+		}
+		for _, id := range taskGroupIDs {
+			if err := s.evictGroup(id); err != nil {
+				if errorx.IsOfType(err, ErrGroupPinned) {
+					// A baseline is registered from the task group in the meantime.
+					continue
+				}
+				return deleted, err
+			}
+			deleted++
+		}
+		if len(taskGroupIDs) < janitorBatchSize {
+			return deleted, nil
+		}
+	}
+}
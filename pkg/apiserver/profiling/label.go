@@ -0,0 +1,80 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+// TaskGroupLabelModel is a label of a task group, stored in its own table so that task groups can be filtered by
+// labels.
+type TaskGroupLabelModel struct {
+	ID          uint   `gorm:"primary_key"`
+	TaskGroupID uint   `gorm:"index"`
+	Key         string `gorm:"index"`
+	Value       string
+}
+
+func (TaskGroupLabelModel) TableName() string {
+	return "profiling_task_group_labels"
+}
+
+// checkLabels rejects labels which cannot be used as a filter, i.e. whose key is empty or contains a colon.
+func checkLabels(labels map[string]string) error {
+	for key := range labels {
+		if key == "" || strings.Contains(key, ":") {
+			return rest.ErrBadRequest.New("invalid label key %q, which must not be empty or contain ':'", key)
+		}
+	}
+	return nil
+}
+
+func saveGroupLabels(db *gorm.DB, taskGroupID uint, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	rows := make([]TaskGroupLabelModel, 0, len(labels))
+	for key, value := range labels {
+		rows = append(rows, TaskGroupLabelModel{TaskGroupID: taskGroupID, Key: key, Value: value})
+	}
+	return db.Create(&rows).Error
+}
+
+// fillGroupLabels loads the labels of task groups into their models.
+func (s *Service) fillGroupLabels(taskGroups []TaskGroupModel) error {
+	if len(taskGroups) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(taskGroups))
+	for _, taskGroup := range taskGroups {
+		ids = append(ids, taskGroup.ID)
+	}
+	var rows []TaskGroupLabelModel
+	if err := s.params.LocalStore.Where("task_group_id IN ?", ids).Find(&rows).Error; err != nil {
+		return err
+	}
+	byGroup := make(map[uint]map[string]string)
+	for _, row := range rows {
+		if byGroup[row.TaskGroupID] == nil {
+			byGroup[row.TaskGroupID] = make(map[string]string)
+		}
+		byGroup[row.TaskGroupID][row.Key] = row.Value
+	}
+	for i := range taskGroups {
+		taskGroups[i].Labels = byGroup[taskGroups[i].ID]
+	}
+	return nil
+}
+
+// parseLabelFilter parses a label filter in the form of "key:value".
+func parseLabelFilter(filter string) (string, string, error) {
+	parts := strings.SplitN(filter, ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", rest.ErrBadRequest.New("invalid label filter %q, expect key:value", filter)
+	}
+	return parts[0], parts[1], nil
+}
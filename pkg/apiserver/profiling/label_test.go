@@ -0,0 +1,67 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+func TestGroupNoteAndLabels(t *testing.T) {
+	s := newTestService(t)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return []byte("goroutine profile"), nil
+	}}
+	newReq := func(note string, labels map[string]string) *StartRequest {
+		return &StartRequest{
+			Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+			RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeGoroutine},
+			Note:                   note,
+			Labels:                 labels,
+		}
+	}
+
+	_, incident := runTestGroup(t, s, newReq("latency spike at 10:00", map[string]string{"reason": "incident", "team": "sql"}))
+	_, routine := runTestGroup(t, s, newReq("", map[string]string{"reason": "routine", "team": "sql"}))
+	_, unlabeled := runTestGroup(t, s, newReq("", nil))
+
+	detail, err := s.groupDetail(incident.ID, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, "latency spike at 10:00", detail.TaskGroup.Note)
+	require.Equal(t, map[string]string{"reason": "incident", "team": "sql"}, detail.TaskGroup.Labels)
+
+	listIDs := func(labels ...string) []uint {
+		resp, err := s.listGroups(ListGroupsRequest{Labels: labels})
+		require.NoError(t, err)
+		ids := make([]uint, 0, len(resp.Groups))
+		for _, group := range resp.Groups {
+			ids = append(ids, group.ID)
+		}
+		return ids
+	}
+	require.Equal(t, []uint{unlabeled.ID, routine.ID, incident.ID}, listIDs())
+	require.Equal(t, []uint{routine.ID, incident.ID}, listIDs("team:sql"))
+	require.Equal(t, []uint{incident.ID}, listIDs("team:sql", "reason:incident"))
+	require.Empty(t, listIDs("team:storage"))
+
+	resp, err := s.listGroups(ListGroupsRequest{Labels: []string{"reason:routine"}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"reason": "routine", "team": "sql"}, resp.Groups[0].Labels)
+
+	_, err = s.listGroups(ListGroupsRequest{Labels: []string{"team"}})
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+	_, err = s.startGroup(context.Background(), newReq("", map[string]string{"a:b": "c"}))
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+
+	// Labels are deleted along with the task group.
+	require.NoError(t, s.deleteGroup(incident.ID))
+	var count int64
+	require.NoError(t, s.params.LocalStore.Model(&TaskGroupLabelModel{}).Where("task_group_id = ?", incident.ID).Count(&count).Error)
+	require.Zero(t, count)
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+
+	"github.com/google/pprof/profile"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+// Labels added to the samples of a merged profile, so that the samples can still be filtered by their source,
+// e.g. `go tool pprof -tagfocus=instance=127.0.0.1:4000`.
+const (
+	mergedProfileLabelInstance  = "instance"
+	mergedProfileLabelComponent = "component"
+)
+
+// mergeGroup merges the finished protobuf profiles of a profiling type across all targets of a task group into a
+// single profile. Tasks which are failed or skipped are ignored.
+func (s *Service) mergeGroup(taskGroupID uint, profilingType TaskProfilingType) ([]byte, error) {
+	var taskGroup TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", taskGroupID).Find(&taskGroup).Error; err != nil {
+		return nil, err
+	}
+	if taskGroup.ID == 0 {
+		return nil, rest.ErrNotFound.New("task group %d does not exist", taskGroupID)
+	}
+	var tasks []TaskModel
+	err := s.params.LocalStore.
+		Where("task_group_id = ? AND state = ? AND profiling_type = ? AND raw_data_type = ?", taskGroupID, TaskStateFinish, profilingType, RawDataTypeProtobuf).
+		Order("id ASC").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, rest.ErrNotFound.New("task group %d has no finished %s profile", taskGroupID, profilingType)
+	}
+	return s.mergeTasks(tasks)
+}
+
+// mergeTasks merges the protobuf profiles of finished tasks into a single profile, whose samples are labeled with
+// their source instance and component.
+func (s *Service) mergeTasks(tasks []TaskModel) ([]byte, error) {
+	parsed := make([]*profile.Profile, len(tasks))
+	err := forEachParallel(len(tasks), analyzeConcurrency, func(i int) error {
+		content, err := s.results.readResult(&tasks[i])
+		if err != nil {
+			return err
+		}
+		p, err := parseProtobufProfile(content)
+		if err != nil {
+			return err
+		}
+		labelSamples(p, map[string]string{
+			mergedProfileLabelInstance:  tasks[i].Target.DisplayName,
+			mergedProfileLabelComponent: string(tasks[i].Target.Kind),
+		})
+		parsed[i] = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := profile.Merge(parsed)
+	if err != nil {
+		return nil, ErrUnsupportedProfilingType.Wrap(err, "failed to merge %s profiles of task group %d", tasks[0].ProfilingType, tasks[0].TaskGroupID)
+	}
+	buf := bytes.Buffer{}
+	if err := p.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// labelSamples adds string labels to all samples of a profile. Samples with different labels are kept apart
+// when profiles are merged.
+func labelSamples(p *profile.Profile, labels map[string]string) {
+	for _, sample := range p.Sample {
+		if sample.Label == nil {
+			sample.Label = make(map[string][]string, len(labels))
+		}
+		for k, v := range labels {
+			sample.Label[k] = []string{v}
+		}
+	}
+}
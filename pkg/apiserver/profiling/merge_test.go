@@ -0,0 +1,61 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+func TestMergeGroup(t *testing.T) {
+	s := newTestService(t)
+	profiles := map[string][]byte{
+		"127.0.0.1": newTestCPUProfile(t, map[string]int64{"main.a": 10000000, "main.b": 20000000}),
+		"127.0.0.2": newTestCPUProfile(t, map[string]int64{"main.a": 30000000}),
+	}
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if op.ip == "127.0.0.3" {
+			return nil, errorx.IllegalState.New("connection refused")
+		}
+		return profiles[op.ip], nil
+	}}
+	_, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.3:4000", IP: "127.0.0.3", Port: 10080},
+			// TiKV does not support heap profiling, so its task is skipped.
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.4:20160", IP: "127.0.0.4", Port: 20180},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU, ProfilingTypeHeap},
+	})
+	require.Equal(t, TaskStatePartialFinish, group.State)
+
+	data, err := s.mergeGroup(group.ID, ProfilingTypeCPU)
+	require.NoError(t, err)
+	p, err := parseProtobufProfile(data)
+	require.NoError(t, err)
+	flat, total := flatByFunction(p, defaultSampleIndex(p))
+	require.Equal(t, int64(40000000), flat["main.a"])
+	require.Equal(t, int64(20000000), flat["main.b"])
+	require.Equal(t, int64(60000000), total)
+
+	// Samples keep their source instance.
+	byInstance := make(map[string]int64)
+	for _, sample := range p.Sample {
+		require.Equal(t, []string{"tidb"}, sample.Label[mergedProfileLabelComponent])
+		byInstance[sample.Label[mergedProfileLabelInstance][0]] += sample.Value[defaultSampleIndex(p)]
+	}
+	require.Equal(t, map[string]int64{"127.0.0.1:4000": 30000000, "127.0.0.2:4000": 30000000}, byInstance)
+
+	_, err = s.mergeGroup(group.ID, ProfilingTypeGoroutine)
+	require.True(t, errorx.IsOfType(err, rest.ErrNotFound))
+	_, err = s.mergeGroup(group.ID+1, ProfilingTypeCPU)
+	require.True(t, errorx.IsOfType(err, rest.ErrNotFound))
+}
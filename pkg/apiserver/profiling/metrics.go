@@ -0,0 +1,97 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+var taskStateLabels = map[TaskState]string{
+	TaskStateError:     "error",
+	TaskStateRunning:   "running",
+	TaskStateFinish:    "finish",
+	TaskStateSkipped:   "skipped",
+	TaskStateCancelled: "cancelled",
+}
+
+// metrics are the Prometheus metrics of profiling. All methods are no-ops on a nil *metrics, so that tasks created
+// without a service can still run.
+type metrics struct {
+	taskGroupsStarted prometheus.Counter
+	taskGroupsRunning prometheus.Gauge
+	tasksFinished     *prometheus.CounterVec
+	fetchDuration     *prometheus.HistogramVec
+}
+
+// newMetrics creates the metrics and registers them on the registerer. The metrics are not exported if the
+// registerer is nil.
+func newMetrics(reg prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		taskGroupsStarted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "tidb_dashboard",
+			Subsystem: "profiling",
+			Name:      "task_groups_started_total",
+			Help:      "Number of profiling task groups started.",
+		}),
+		taskGroupsRunning: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "tidb_dashboard",
+			Subsystem: "profiling",
+			Name:      "task_groups_running",
+			Help:      "Number of profiling task groups currently running.",
+		}),
+		tasksFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "tidb_dashboard",
+			Subsystem: "profiling",
+			Name:      "tasks_total",
+			Help:      "Number of profiling tasks by their final state.",
+		}, []string{"state"}),
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "tidb_dashboard",
+			Subsystem: "profiling",
+			Name:      "fetch_duration_seconds",
+			Help:      "Time spent on fetching a profile, including the profile duration.",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10), // 0.5s ~ 256s
+		}, []string{"component", "profiling_type"}),
+	}
+	if reg != nil {
+		for _, c := range []prometheus.Collector{m.taskGroupsStarted, m.taskGroupsRunning, m.tasksFinished, m.fetchDuration} {
+			if err := reg.Register(c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *metrics) taskGroupStarted() {
+	if m == nil {
+		return
+	}
+	m.taskGroupsStarted.Inc()
+	m.taskGroupsRunning.Inc()
+}
+
+func (m *metrics) taskGroupStopped() {
+	if m == nil {
+		return
+	}
+	m.taskGroupsRunning.Dec()
+}
+
+func (m *metrics) taskFinished(state TaskState) {
+	if m == nil {
+		return
+	}
+	m.tasksFinished.WithLabelValues(taskStateLabels[state]).Inc()
+}
+
+func (m *metrics) observeFetch(kind model.NodeKind, profilingType TaskProfilingType, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.fetchDuration.WithLabelValues(string(kind), string(profilingType)).Observe(elapsed.Seconds())
+}
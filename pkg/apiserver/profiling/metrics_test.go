@@ -0,0 +1,57 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s := newTestService(t)
+	m, err := newMetrics(reg)
+	require.NoError(t, err)
+	s.metrics = m
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return newTestCPUProfile(t, map[string]int64{"main.work": 10}), nil
+	}}
+	s.fetchers.tikv = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return nil, fmt.Errorf("connection refused")
+	}}
+
+	runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, float64(1), testutil.ToFloat64(m.taskGroupsStarted))
+	require.Equal(t, float64(0), testutil.ToFloat64(m.taskGroupsRunning))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.tasksFinished.WithLabelValues("finish")))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.tasksFinished.WithLabelValues("error")))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	var fetchCount uint64
+	for _, family := range families {
+		if family.GetName() == "tidb_dashboard_profiling_fetch_duration_seconds" {
+			for _, metric := range family.GetMetric() {
+				fetchCount += metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	require.Equal(t, uint64(2), fetchCount)
+
+	// The metrics cannot be registered twice on the same registerer.
+	_, err = newMetrics(reg)
+	require.Error(t, err)
+}
@@ -7,9 +7,12 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/joomcode/errorx"
+	"go.uber.org/zap"
 
 	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
 	"github.com/pingcap/tidb-dashboard/pkg/dbstore"
@@ -25,6 +28,17 @@ const (
 	TaskStateFinish
 	TaskStatePartialFinish // Only valid for task group
 	TaskStateSkipped
+	TaskStateCancelled
+)
+
+// SkipReason tells why a task is skipped.
+type SkipReason string
+
+const (
+	// SkipReasonUnsupportedProfilingType means the component of the target does not support the profiling type.
+	SkipReasonUnsupportedProfilingType SkipReason = "unsupported_profiling_type"
+	// SkipReasonClientNotConfigured means there is no client to fetch profiles from the component of the target.
+	SkipReasonClientNotConfigured SkipReason = "client_not_configured"
 )
 
 type TaskRawDataType string
@@ -32,6 +46,10 @@ type TaskRawDataType string
 const (
 	RawDataTypeProtobuf TaskRawDataType = "protobuf"
 	RawDataTypeText     TaskRawDataType = "text"
+	// RawDataTypeTrace is a runtime execution trace, which can only be opened by `go tool trace`.
+	RawDataTypeTrace TaskRawDataType = "trace"
+	// RawDataTypeRaw is the response of a custom pprof handler, whose format is unknown, so it can only be downloaded.
+	RawDataTypeRaw TaskRawDataType = "raw"
 )
 
 type (
@@ -53,6 +71,22 @@ const (
 	ProfilingTypeHeap      TaskProfilingType = "heap"
 	ProfilingTypeGoroutine TaskProfilingType = "goroutine"
 	ProfilingTypeMutex     TaskProfilingType = "mutex"
+	// ProfilingTypeHeapDiff captures two heap profiles separated by the profile duration,
+	// and stores the growth of in-use objects between them.
+	ProfilingTypeHeapDiff TaskProfilingType = "heap_diff"
+	ProfilingTypeBlock    TaskProfilingType = "block"
+	// ProfilingTypeGoroutineFull dumps the full stack and the wait state of every goroutine in plain text,
+	// which is more useful than ProfilingTypeGoroutine when debugging deadlocks.
+	ProfilingTypeGoroutineFull TaskProfilingType = "goroutine_full"
+	// ProfilingTypeTrace captures the runtime execution trace in the profile duration, which shows the scheduling,
+	// syscalls and GC events for latency analysis.
+	ProfilingTypeTrace TaskProfilingType = "trace"
+	// ProfilingTypeAllocs samples all allocations since the process started, including the freed ones, which is
+	// useful for finding allocation-rate regressions that the live heap does not show.
+	ProfilingTypeAllocs TaskProfilingType = "allocs"
+	// ProfilingTypeCustom fetches a pprof handler given by path in the profiling request, e.g. a handler only
+	// exposed by some builds, and stores the response as is.
+	ProfilingTypeCustom TaskProfilingType = "custom"
 )
 
 var profilingTypeMap = map[TaskProfilingType]struct{}{
@@ -60,6 +94,23 @@ var profilingTypeMap = map[TaskProfilingType]struct{}{
 	ProfilingTypeHeap:      {},
 	ProfilingTypeGoroutine: {},
 	ProfilingTypeMutex:     {},
+	ProfilingTypeHeapDiff:  {},
+	ProfilingTypeBlock:     {},
+
+	ProfilingTypeGoroutineFull: {},
+	ProfilingTypeTrace:         {},
+	ProfilingTypeAllocs:        {},
+	ProfilingTypeCustom:        {},
+}
+
+// isSnapshot returns whether the profiling type is captured instantly, regardless of the profile duration.
+func (t TaskProfilingType) isSnapshot() bool {
+	switch t {
+	case ProfilingTypeHeap, ProfilingTypeGoroutine, ProfilingTypeMutex, ProfilingTypeBlock, ProfilingTypeGoroutineFull, ProfilingTypeAllocs:
+		return true
+	default:
+		return false
+	}
 }
 
 type TaskModel struct {
@@ -69,15 +120,68 @@ type TaskModel struct {
 	Target        model.RequestTargetNode `json:"target" gorm:"embedded;embedded_prefix:target_"`
 	FilePath      string                  `json:"-" gorm:"type:text"`
 	Error         string                  `json:"error" gorm:"type:text"`
-	StartedAt     int64                   `json:"started_at"` // The start running time, reset when retry. Used to estimate approximate profiling progress.
+	StartedAt     int64                   `json:"started_at"`  // The start running time, reset when retry, or 0 if the task is waiting for a concurrency slot. Used to estimate approximate profiling progress.
+	FinishedAt    int64                   `json:"finished_at"` // The time when the task is stopped in any state, or 0 if it is still running or was interrupted by restart.
 	RawDataType   TaskRawDataType         `json:"raw_data_type" gorm:"raw_data_type"`
 	ProfilingType TaskProfilingType       `json:"profiling_type"`
+	// Starts from 1 and increases on each retry, so that a reset of StartedAt can be told apart from a stalled progress.
+	Attempt uint `json:"attempt" gorm:"default:1"`
+	// The git hash of the target binary when profiled, which can be used to match symbols. Empty if not captured.
+	BuildID string `json:"build_id"`
+	// The ID of the key used to encrypt the result file, or empty if the file is stored in plaintext.
+	EncryptionKeyID string `json:"-"`
+	// Whether the result file is compressed before being encrypted. Results saved by earlier versions are not
+	// compressed.
+	Compressed bool `json:"-"`
+	// The codec which the result file is compressed with, e.g. zstd. It is empty for results compressed with gzip
+	// by earlier versions, which did not record the codec.
+	CompressionCodec string `json:"-"`
+	// The size of the profile downloaded from the target in bytes, before it is compressed or encrypted.
+	// It is 0 unless the task is finished.
+	SizeBytes int64 `json:"size_bytes"`
+	// The size of the result in bytes as it is stored, after it is compressed and encrypted. It is 0 unless the
+	// task is finished, or for results saved by earlier versions.
+	StoredSizeBytes int64 `json:"-"`
+	// The key of the result in the result store, or empty if the result is stored locally at FilePath.
+	StorageKey string `json:"-" gorm:"type:text"`
+	// The path with the query fetched from the target, only for ProfilingTypeCustom.
+	CustomPath string `json:"custom_path"`
+	// The profile duration of the task, which is ignored by snapshot profiling types. The duration of the task
+	// group is used when it is 0, e.g. for tasks saved by earlier versions.
+	DurationSecs uint `json:"duration_secs"`
+	// The address and the path with the query requested from the target, e.g. 10.0.0.1:10080/debug/pprof/heap,
+	// which is recorded for debugging. The scheme is decided by the TLS config of the component and is omitted.
+	RequestURL string `json:"request_url" gorm:"type:text"`
+	// Why the task is skipped. Only valid when the state is TaskStateSkipped.
+	SkipReason SkipReason `json:"skip_reason"`
+	// Estimated progress in range [0, 1]. It is not persisted and is only filled in responses.
+	Progress float64 `json:"progress" gorm:"-"`
 }
 
 func (TaskModel) TableName() string {
 	return "profiling_tasks"
 }
 
+// profileDurationSecs returns the profile duration of the task, falling back to the duration of its task group.
+func (m *TaskModel) profileDurationSecs(groupDurationSecs uint) uint {
+	if m.DurationSecs > 0 {
+		return m.DurationSecs
+	}
+	return groupDurationSecs
+}
+
+// compressionCodec returns the codec which the result file is compressed with, or an empty string if it is not
+// compressed.
+func (m *TaskModel) compressionCodec() string {
+	if !m.Compressed {
+		return ""
+	}
+	if m.CompressionCodec == "" {
+		return compressionCodecGzip
+	}
+	return m.CompressionCodec
+}
+
 type TaskGroupModel struct {
 	ID                     uint                          `json:"id" gorm:"primary_key"`
 	State                  TaskState                     `json:"state" gorm:"index"`
@@ -85,70 +189,333 @@ type TaskGroupModel struct {
 	TargetStats            model.RequestTargetStatistics `json:"target_stats" gorm:"embedded;embedded_prefix:target_stats_"`
 	StartedAt              int64                         `json:"started_at"`
 	RequstedProfilingTypes TaskProfilingTypeList         `json:"requsted_profiling_types"`
+	// The campaign which the task group is an iteration of, or 0 if it is started alone.
+	CampaignID uint `json:"campaign_id" gorm:"index"`
+	// The schedule which started the task group, or 0 if it is not started by a schedule.
+	ScheduleID uint `json:"schedule_id" gorm:"index"`
+	// The task group which is retried by this task group because all of its tasks are failed, or 0 if it is not a retry.
+	RetryOf uint `json:"retry_of" gorm:"index"`
+	// Why the task group is captured, given when it is started.
+	Note string `json:"note" gorm:"type:text"`
+	// The display name of the user who started the task group, or empty if it is started automatically, e.g. by a
+	// schedule.
+	TriggeredBy string `json:"triggered_by" gorm:"index"`
+	// Labels given when the task group is started, which are stored in TaskGroupLabelModel and only filled in
+	// responses.
+	Labels map[string]string `json:"labels" gorm:"-"`
 }
 
 func (TaskGroupModel) TableName() string {
 	return "profiling_task_groups"
 }
 
+// estimateProgress estimates the progress of a task from its running time. A running task never reaches 1,
+// and snapshot profiling types or tasks waiting for a concurrency slot stay at 0 until finished.
+func estimateProgress(task *TaskModel, profileDurationSecs uint, now int64) float64 {
+	if task.State != TaskStateRunning {
+		return 1
+	}
+	profileDurationSecs = task.profileDurationSecs(profileDurationSecs)
+	if task.StartedAt == 0 || task.ProfilingType.isSnapshot() || profileDurationSecs == 0 {
+		return 0
+	}
+	progress := float64(now-task.StartedAt) / float64(profileDurationSecs)
+	if progress < 0 {
+		return 0
+	}
+	if progress > 0.99 {
+		return 0.99
+	}
+	return progress
+}
+
+// observedProfilingTypes returns the distinct profiling types of finished tasks, in the order of their first appearance.
+func observedProfilingTypes(tasks []TaskModel) TaskProfilingTypeList {
+	types := make(TaskProfilingTypeList, 0)
+	seen := make(map[TaskProfilingType]struct{})
+	for _, task := range tasks {
+		if task.State != TaskStateFinish {
+			continue
+		}
+		if _, ok := seen[task.ProfilingType]; ok {
+			continue
+		}
+		seen[task.ProfilingType] = struct{}{}
+		types = append(types, task.ProfilingType)
+	}
+	return types
+}
+
+// errorSummary counts the failed tasks by their distinct error messages. Skipped and cancelled tasks are excluded.
+func errorSummary(tasks []TaskModel) map[string]int {
+	summary := make(map[string]int)
+	for _, task := range tasks {
+		if task.State != TaskStateError {
+			continue
+		}
+		summary[task.Error]++
+	}
+	return summary
+}
+
 func autoMigrate(db *dbstore.DB) error {
-	return db.AutoMigrate(&TaskModel{}, &TaskGroupModel{})
+	return db.AutoMigrate(&TaskModel{}, &TaskGroupModel{}, &BaselineModel{}, &TaskGroupCommentModel{}, &CampaignModel{}, &ScheduleModel{}, &TaskGroupLabelModel{})
 }
 
 // Task is the unit to fetch profiling information.
 type Task struct {
 	*TaskModel
+	mu        sync.Mutex    // Guards changes of TaskModel once the task is started
+	stopped   chan struct{} // Closed when the task is stopped and its final state is saved
 	ctx       context.Context
 	cancel    context.CancelFunc
 	taskGroup *TaskGroup
 	fetchers  *fetchers
+	results   *resultFiles
+
+	captureBuildID       bool
+	timeout              time.Duration // The time allowed for fetching the profile, or 0 if it is not limited
+	mutexProfileFraction int           // The mutex profile fraction set during mutex profiling, or 0 if it is not changed
+	metrics              *metrics
+	partial              *partialBuffer // The bytes received so far, or nil if the target does not provide partial results
+	cancelReason         string         // Recorded as the error of the task once it is cancelled by stopWithReason
+
+	// Checks whether the target is still in the cluster topology once the task is dispatched, or nil if the target
+	// is not checked. It is only set for tasks dispatched later than the task group is started.
+	checkTopology func(ctx context.Context, target model.RequestTargetNode) error
 }
 
 // NewTask creates a new profiling task.
-func NewTask(ctx context.Context, taskGroup *TaskGroup, target model.RequestTargetNode, fts *fetchers, profilingType TaskProfilingType) *Task {
+func NewTask(ctx context.Context, taskGroup *TaskGroup, target model.RequestTargetNode, fts *fetchers, results *resultFiles, profilingType TaskProfilingType) *Task {
 	ctx, cancel := context.WithCancel(ctx)
-	return &Task{
+	t := &Task{
 		TaskModel: &TaskModel{
 			TaskGroupID:   taskGroup.ID,
 			State:         TaskStateRunning,
 			Target:        target,
 			StartedAt:     time.Now().Unix(),
+			Attempt:       1,
 			ProfilingType: profilingType,
 		},
+		stopped:   make(chan struct{}),
 		ctx:       ctx,
 		cancel:    cancel,
 		taskGroup: taskGroup,
 		fetchers:  fts,
+		results:   results,
 	}
+	if supportsPartialResult(target.Kind) {
+		t.partial = &partialBuffer{}
+	}
+	return t
+}
+
+// log returns the logger of the task, which is tagged with the task and its target.
+func (t *Task) log() *zap.Logger {
+	return t.taskGroup.log().With(
+		zap.Uint("task_id", t.ID),
+		zap.String("target", t.Target.String()),
+		zap.String("profiling_type", string(t.ProfilingType)))
 }
 
 func (t *Task) run() {
+	runStartedAt := time.Now()
+	// The outcome is recorded in a copy and saved at once, so that concurrent snapshots are consistent.
+	m := t.snapshot()
+	defer func() {
+		m.FinishedAt = time.Now().Unix()
+		t.save(m)
+		close(t.stopped)
+		t.metrics.taskFinished(m.State)
+		fields := []zap.Field{zap.String("state", taskStateLabels[m.State]), zap.Duration("duration", time.Since(runStartedAt))}
+		switch m.State {
+		case TaskStateError:
+			t.log().Warn("profiling task failed", append(fields, zap.String("error", m.Error))...)
+		case TaskStateSkipped:
+			t.log().Info("profiling task skipped", append(fields, zap.String("skip_reason", string(m.SkipReason)))...)
+		default:
+			t.log().Info("profiling task stopped", fields...)
+		}
+	}()
+	if t.checkTopology != nil {
+		if err := t.checkTopology(t.ctx, t.Target); err != nil {
+			if t.ctx.Err() != nil {
+				// The task is cancelled while waiting to be dispatched, rather than failed to check the target.
+				m.State = TaskStateCancelled
+				m.Error = t.cancelReason
+			} else {
+				m.Error = err.Error()
+				m.State = TaskStateError
+			}
+			return
+		}
+	}
+	if t.captureBuildID {
+		m.BuildID = fetchBuildID(t.ctx, t.fetchers, &t.Target)
+	}
+	fetchCtx := t.ctx
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(t.ctx, t.timeout)
+		defer cancel()
+	}
 	fileNameWithoutExt := fmt.Sprintf("%s_%s", t.ProfilingType, t.Target.FileName())
-	protoFilePath, rawDataType, err := profileAndWritePprof(t.ctx, t.fetchers, &t.Target, fileNameWithoutExt, t.taskGroup.ProfileDurationSecs, t.ProfilingType)
+	var protoFilePath string
+	var rawDataType TaskRawDataType
+	var err error
+	// The URL is recorded even if the fetch is failed, since it is mostly useful for debugging a failed task.
+	fts := t.fetchers.recording(&m.RequestURL).logging(t.log())
+	if t.partial != nil {
+		fts = fts.buffering(t.partial)
+	}
+	fetchStartedAt := time.Now()
+	if t.ProfilingType == ProfilingTypeMutex && t.mutexProfileFraction > 0 {
+		protoFilePath, rawDataType, err = t.profileMutexWithFraction(fetchCtx, fts, fileNameWithoutExt)
+	} else {
+		protoFilePath, rawDataType, err = profileAndWritePprof(fetchCtx, t.timeout, fts, &t.Target, fileNameWithoutExt, t.profileDurationSecs(t.taskGroup.ProfileDurationSecs), t.ProfilingType, t.CustomPath)
+	}
+	t.metrics.observeFetch(t.Target.Kind, t.ProfilingType, time.Since(fetchStartedAt))
 	if err != nil {
-		if errorx.IsOfType(err, ErrUnsupportedProfilingType) {
-			t.State = TaskStateSkipped
-		} else {
-			t.Error = err.Error()
-			t.State = TaskStateError
+		switch {
+		case errorx.IsOfType(err, ErrUnsupportedProfilingType):
+			m.State = TaskStateSkipped
+			m.SkipReason = SkipReasonUnsupportedProfilingType
+		case errorx.IsOfType(err, ErrClientNotConfigured):
+			m.State = TaskStateSkipped
+			m.SkipReason = SkipReasonClientNotConfigured
+		case t.ctx.Err() != nil:
+			m.State = TaskStateCancelled
+			m.Error = t.cancelReason
+		// The HTTP client of the fetcher times out at the same time as the context, so either of them may be
+		// the first to stop the fetch.
+		case fetchCtx.Err() == context.DeadlineExceeded || (t.timeout > 0 && time.Since(fetchStartedAt) >= t.timeout):
+			m.Error = fmt.Sprintf("timeout: no profile is received in %s", t.timeout)
+			m.State = TaskStateError
+		default:
+			m.Error = err.Error()
+			m.State = TaskStateError
+		}
+		return
+	}
+	// The fetched file is removed unless the result is saved, so that no file is left without a task pointing at it,
+	// e.g. a plaintext profile which failed to be encrypted.
+	saved := false
+	defer func() {
+		if saved {
+			return
+		}
+		if err := os.Remove(protoFilePath); err != nil && !os.IsNotExist(err) {
+			t.log().Warn("failed to remove profiling result", zap.String("path", protoFilePath), zap.Error(err))
 		}
-		t.taskGroup.db.Save(t.TaskModel)
+	}()
+	stat, err := os.Stat(protoFilePath)
+	if err != nil {
+		m.Error = err.Error()
+		m.State = TaskStateError
+		return
+	}
+	codec, err := t.results.compressFile(protoFilePath)
+	if err != nil {
+		m.Error = err.Error()
+		m.State = TaskStateError
+		return
+	}
+	keyID, err := t.results.encryptFile(protoFilePath)
+	if err != nil {
+		m.Error = err.Error()
+		m.State = TaskStateError
+		return
+	}
+	storedStat, err := os.Stat(protoFilePath)
+	if err != nil {
+		m.Error = err.Error()
+		m.State = TaskStateError
+		return
+	}
+	storageKey := resultStorageKey(t.ID, protoFilePath)
+	uploaded, err := t.results.uploadFile(protoFilePath, storageKey)
+	if err != nil {
+		m.Error = err.Error()
+		m.State = TaskStateError
 		return
 	}
-	t.FilePath = protoFilePath
-	t.State = TaskStateFinish
-	t.RawDataType = rawDataType
+	if uploaded {
+		m.StorageKey = storageKey
+	}
+	m.FilePath = protoFilePath
+	m.SizeBytes = stat.Size()
+	m.StoredSizeBytes = storedStat.Size()
+	m.Compressed = codec != ""
+	m.CompressionCodec = codec
+	m.EncryptionKeyID = keyID
+	m.State = TaskStateFinish
+	m.RawDataType = rawDataType
+	saved = true
+}
+
+// snapshot returns a copy of the task model, which is consistent even if the task is running.
+func (t *Task) snapshot() TaskModel {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return *t.TaskModel
+}
+
+// save replaces the task model and saves it. Once the task is started, the model is only changed by save, so that
+// it can be read by snapshot concurrently.
+func (t *Task) save(m TaskModel) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*t.TaskModel = m
 	t.taskGroup.db.Save(t.TaskModel)
 }
 
+// wait blocks until the task is stopped and its final state is saved.
+func (t *Task) wait() {
+	<-t.stopped
+}
+
 func (t *Task) stop() {
 	t.cancel()
 }
 
+// stopWithReason cancels the task like stop, and records why the task is cancelled as its error.
+func (t *Task) stopWithReason(reason string) {
+	t.cancelReason = reason
+	t.cancel()
+}
+
 // TaskGroup is the collection of tasks.
 type TaskGroup struct {
 	*TaskGroupModel
-	db *dbstore.DB
+	mu     sync.Mutex // Guards changes of TaskGroupModel once the task group is started
+	db     *dbstore.DB
+	done   chan struct{} // Closed when all tasks are stopped and the state of the task group is saved
+	logger *zap.Logger   // Tagged with the task group ID, or nil if nothing is logged
+	// The storage reserved for the results of the task group, which is released once it is stopped.
+	reservedBytes int64
+}
+
+// snapshot returns a copy of the task group model, which is consistent even if the task group is running.
+func (tg *TaskGroup) snapshot() TaskGroupModel {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	return *tg.TaskGroupModel
+}
+
+// setState changes the state of the task group and saves it.
+func (tg *TaskGroup) setState(state TaskState) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	tg.State = state
+	tg.db.Save(tg.TaskGroupModel)
+}
+
+// log returns the logger of the task group, which discards all logs if no logger is set.
+func (tg *TaskGroup) log() *zap.Logger {
+	if tg.logger == nil {
+		return zap.NewNop()
+	}
+	return tg.logger
 }
 
 // NewTaskGroup create a new profiling task group.
@@ -161,6 +528,7 @@ func NewTaskGroup(db *dbstore.DB, profileDurationSecs uint, stats model.RequestT
 			StartedAt:              time.Now().Unix(),
 			RequstedProfilingTypes: requestedProfilingTypes,
 		},
-		db: db,
+		db:   db,
+		done: make(chan struct{}),
 	}
 }
@@ -0,0 +1,91 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+// The time allowed for resetting the mutex profile fraction, which is independent of the task so that the fraction
+// is reset even if the task is cancelled or timed out.
+const mutexProfileFractionResetTimeout = 10 * time.Second
+
+// mutexProfileFractionSetter is implemented by fetchers of components which allow changing the mutex profile
+// fraction (see runtime.SetMutexProfileFraction) at runtime.
+type mutexProfileFractionSetter interface {
+	setMutexProfileFraction(ctx context.Context, ip string, port int, fraction int) error
+}
+
+func (f *tidbFetcher) setMutexProfileFraction(ctx context.Context, ip string, port int, fraction int) error {
+	client := f.client
+	if f.dedicated != nil {
+		client = client.WithStatusAPIHTTPClient(f.dedicated.Client, f.dedicated.scheme)
+	}
+	if ctx != nil {
+		client = client.WithContext(ctx)
+	}
+	if f.authorization != "" {
+		client = client.AddStatusAPIRequestHeader("Authorization", f.authorization)
+	}
+	form := url.Values{"mutex_profile_fraction": {strconv.Itoa(fraction)}}
+	_, err := client.WithEnforcedStatusAPIAddress(ip, port).SendPostFormRequest("/settings", form)
+	return err
+}
+
+// mutexProfileFractionSetterOf returns the setter of a fetcher, looking through the fetchers wrapping it,
+// or nil if the component does not support it.
+func mutexProfileFractionSetterOf(f profileFetcher) mutexProfileFractionSetter {
+	for f != nil {
+		if setter, ok := f.(mutexProfileFractionSetter); ok {
+			return setter
+		}
+		w, ok := f.(interface{ unwrap() profileFetcher })
+		if !ok {
+			return nil
+		}
+		f = w.unwrap()
+	}
+	return nil
+}
+
+// profileMutexWithFraction sets the mutex profile fraction of the target, waits for the profile duration so that
+// contentions are sampled, and then fetches the mutex profile. The mutex profile fraction is reset to 0, which is
+// the default of the Go runtime, after the profile is fetched, failed or cancelled, since the original fraction
+// cannot be read from the target. Targets which do not support changing the fraction are profiled as usual.
+func (t *Task) profileMutexWithFraction(ctx context.Context, fts *fetchers, fileNameWithoutExt string) (string, TaskRawDataType, error) {
+	var setter mutexProfileFractionSetter
+	if t.Target.Kind == model.NodeKindTiDB {
+		setter = mutexProfileFractionSetterOf(fts.tidb)
+	}
+	if setter == nil {
+		return profileAndWritePprof(ctx, t.timeout, fts, &t.Target, fileNameWithoutExt, t.profileDurationSecs(t.taskGroup.ProfileDurationSecs), t.ProfilingType, "")
+	}
+
+	if err := setter.setMutexProfileFraction(ctx, t.Target.IP, t.Target.Port, t.mutexProfileFraction); err != nil {
+		return "", "", fmt.Errorf("failed to set mutex profile fraction: %v", err)
+	}
+	defer func() {
+		resetCtx, cancel := context.WithTimeout(context.Background(), mutexProfileFractionResetTimeout)
+		defer cancel()
+		if err := setter.setMutexProfileFraction(resetCtx, t.Target.IP, t.Target.Port, 0); err != nil {
+			t.log().Warn("failed to reset mutex profile fraction", zap.Error(err))
+		}
+	}()
+
+	timer := time.NewTimer(time.Duration(t.profileDurationSecs(t.taskGroup.ProfileDurationSecs)) * time.Second)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return "", "", ctx.Err()
+	}
+	return profileAndWritePprof(ctx, t.timeout, fts, &t.Target, fileNameWithoutExt, t.profileDurationSecs(t.taskGroup.ProfileDurationSecs), t.ProfilingType, "")
+}
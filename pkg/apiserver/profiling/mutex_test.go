@@ -0,0 +1,123 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+type mockMutexFetcher struct {
+	mockFetcher
+	mu        sync.Mutex
+	fractions []int
+	setErr    error
+}
+
+func (f *mockMutexFetcher) setMutexProfileFraction(_ context.Context, _ string, _ int, fraction int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.setErr != nil {
+		return f.setErr
+	}
+	f.fractions = append(f.fractions, fraction)
+	return nil
+}
+
+func TestMutexProfileFraction(t *testing.T) {
+	tidbTarget := model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}
+	mutexReq := func(target model.RequestTargetNode) *StartRequest {
+		return &StartRequest{
+			Targets:                []model.RequestTargetNode{target},
+			DurationSecs:           1,
+			RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeMutex},
+			MutexProfileFraction:   5,
+		}
+	}
+
+	t.Run("restored after profiling", func(t *testing.T) {
+		s := newTestService(t)
+		f := &mockMutexFetcher{}
+		f.fetchFn = func(op *fetchOptions) ([]byte, error) {
+			// The fraction is set before fetching the profile, and not reset yet.
+			require.Equal(t, []int{5}, f.fractions)
+			return []byte("--- mutex:\ncycles/second=1\nsampling period=5\n"), nil
+		}
+		s.fetchers.tidb = f
+		tasks, _ := runTestGroup(t, s, mutexReq(tidbTarget))
+		require.Equal(t, TaskStateFinish, tasks[0].State)
+		require.Equal(t, []int{5, 0}, f.fractions)
+	})
+
+	t.Run("restored after the fetch is failed", func(t *testing.T) {
+		s := newTestService(t)
+		f := &mockMutexFetcher{}
+		f.fetchFn = func(op *fetchOptions) ([]byte, error) {
+			return nil, fmt.Errorf("connection reset")
+		}
+		s.fetchers.tidb = f
+		tasks, _ := runTestGroup(t, s, mutexReq(tidbTarget))
+		require.Equal(t, TaskStateError, tasks[0].State)
+		require.Contains(t, tasks[0].Error, "connection reset")
+		require.Equal(t, []int{5, 0}, f.fractions)
+	})
+
+	t.Run("restored after the task is cancelled", func(t *testing.T) {
+		s := newTestService(t)
+		f := &mockMutexFetcher{}
+		f.fetchFn = func(op *fetchOptions) ([]byte, error) {
+			t.Fatal("the profile should not be fetched after the task is cancelled")
+			return nil, nil
+		}
+		s.fetchers.tidb = f
+		req := mutexReq(tidbTarget)
+		req.DurationSecs = 30
+		taskGroup, err := s.startGroup(context.Background(), req)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			return len(f.fractions) == 1
+		}, time.Second, 10*time.Millisecond)
+		s.tasks.Range(func(_, task interface{}) bool {
+			task.(*Task).stop()
+			return true
+		})
+		<-taskGroup.done
+		s.wg.Wait()
+		require.Equal(t, []int{5, 0}, f.fractions)
+	})
+
+	t.Run("failed to set", func(t *testing.T) {
+		s := newTestService(t)
+		f := &mockMutexFetcher{setErr: fmt.Errorf("404 not found")}
+		f.fetchFn = func(op *fetchOptions) ([]byte, error) {
+			t.Fatal("the profile should not be fetched if the fraction is not set")
+			return nil, nil
+		}
+		s.fetchers.tidb = f
+		tasks, _ := runTestGroup(t, s, mutexReq(tidbTarget))
+		require.Equal(t, TaskStateError, tasks[0].State)
+		require.Contains(t, tasks[0].Error, "failed to set mutex profile fraction")
+		require.Empty(t, f.fractions)
+	})
+
+	t.Run("ignored by other components", func(t *testing.T) {
+		s := newTestService(t)
+		f := &mockMutexFetcher{}
+		s.fetchers.tidb = f
+		s.fetchers.pd = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+			return []byte("--- mutex:\n"), nil
+		}}
+		tasks, _ := runTestGroup(t, s, mutexReq(model.RequestTargetNode{Kind: model.NodeKindPD, DisplayName: "127.0.0.1:2379", IP: "127.0.0.1", Port: 2379}))
+		require.Equal(t, TaskStateFinish, tasks[0].State)
+		require.Empty(t, f.fractions)
+	})
+}
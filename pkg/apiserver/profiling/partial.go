@@ -0,0 +1,145 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/httpc"
+)
+
+var ErrTaskNotFinished = ErrNS.NewType("task_not_finished")
+
+// supportsPartialResult returns whether the profiles of a kind of component are received as a stream, so that the
+// bytes received so far can be read while a task is running.
+func supportsPartialResult(kind model.NodeKind) bool {
+	switch kind {
+	case model.NodeKindTiKV, model.NodeKindTiFlash, model.NodeKindPD, model.NodeKindPDTSO, model.NodeKindPDScheduling:
+		return true
+	default:
+		return false
+	}
+}
+
+// partialBuffer keeps the bytes of a profile received so far.
+type partialBuffer struct {
+	mu   sync.Mutex
+	data []byte
+	// Buffers which receive the same bytes, e.g. of the tasks sharing a fetch.
+	mirrors []*partialBuffer
+}
+
+func (b *partialBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	for _, m := range b.mirrors {
+		_, _ = m.Write(p)
+	}
+	return len(p), nil
+}
+
+// reset drops the bytes received by a previous attempt.
+func (b *partialBuffer) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = nil
+	for _, m := range b.mirrors {
+		m.reset()
+	}
+}
+
+// addMirror makes m receive the bytes received so far and the bytes received later.
+func (b *partialBuffer) addMirror(m *partialBuffer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m.reset()
+	_, _ = m.Write(b.data)
+	b.mirrors = append(b.mirrors, m)
+}
+
+func (b *partialBuffer) removeMirror(m *partialBuffer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.mirrors {
+		if b.mirrors[i] == m {
+			b.mirrors = append(b.mirrors[:i], b.mirrors[i+1:]...)
+			return
+		}
+	}
+}
+
+// bytes returns a copy of the bytes received so far.
+func (b *partialBuffer) bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte{}, b.data...)
+}
+
+// readBody reads the body of a response, which is also written to the partial buffer as it is received when the
+// buffer is given. Profiles are requested with `Accept-Encoding: gzip`, so that large text profiles are transferred
+// compressed, and the transfer encoding is decoded here. It is independent of the gzip of protobuf profiles
+// themselves, which is kept.
+func readBody(res *httpc.Response, partial *partialBuffer) ([]byte, error) {
+	defer res.Response.Body.Close()
+	var body io.Reader = res.Response.Body
+	if strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode gzip transfer encoding: %v", err)
+		}
+		defer zr.Close()
+		body = zr
+	}
+	if partial == nil {
+		return ioutil.ReadAll(body)
+	}
+	partial.reset()
+	return ioutil.ReadAll(io.TeeReader(body, partial))
+}
+
+// bufferingFetcher passes the partial buffer of a task to its fetches.
+type bufferingFetcher struct {
+	profileFetcher
+	partial *partialBuffer
+}
+
+func (f *bufferingFetcher) unwrap() profileFetcher {
+	return f.profileFetcher
+}
+
+func (f *bufferingFetcher) fetch(op *fetchOptions) ([]byte, error) {
+	bufferedOp := *op
+	bufferedOp.partial = f.partial
+	return f.profileFetcher.fetch(&bufferedOp)
+}
+
+// buffering returns fetchers which write the bytes received to partial. It must not be shared by concurrent tasks.
+func (fts *fetchers) buffering(partial *partialBuffer) *fetchers {
+	return fts.wrap(func(f profileFetcher) profileFetcher {
+		return &bufferingFetcher{profileFetcher: f, partial: partial}
+	})
+}
+
+// partialResult returns the bytes of the profile received so far by a running task.
+func (s *Service) partialResult(task *TaskModel) ([]byte, error) {
+	if task.State != TaskStateRunning {
+		return nil, ErrTaskNotFinished.New("task %d is in %s state", task.ID, taskStateLabels[task.State])
+	}
+	v, ok := s.tasks.Load(task.ID)
+	if !ok {
+		// The task is stopped after its state is read, and its result will be available soon.
+		return nil, ErrTaskNotFinished.New("task %d is stopping", task.ID)
+	}
+	t := v.(*Task)
+	if t.partial == nil {
+		return nil, ErrTaskNotFinished.New("task %d is running, and %s does not provide partial results", task.ID, task.Target.Kind)
+	}
+	return t.partial.bytes(), nil
+}
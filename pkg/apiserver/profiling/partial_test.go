@@ -0,0 +1,84 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+	"github.com/pingcap/tidb-dashboard/pkg/httpc"
+	"github.com/pingcap/tidb-dashboard/pkg/tikv"
+)
+
+func TestPartialResult(t *testing.T) {
+	release := make(chan struct{})
+	// The server responds the profile in two chunks, and the second one is held until released.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("first;"))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte("second"))
+	}))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	s := newTestService(t)
+	cfg := &config.Config{}
+	lc := fxtest.NewLifecycle(t)
+	s.fetchers.tikv = &tikvFetcher{client: tikv.NewTiKVClient(lc, httpc.NewHTTPClient(lc, cfg), cfg)}
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		<-release
+		return []byte("tidb"), nil
+	}}
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	taskGroup, err := s.startGroup(context.Background(), &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiKV, DisplayName: u.Host, IP: u.Hostname(), Port: port},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.NoError(t, err)
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Order("id ASC").Find(&tasks).Error)
+	require.Len(t, tasks, 2)
+
+	// The bytes received so far are read while the profile is being fetched.
+	require.Eventually(t, func() bool {
+		content, _, partial, err := s.resultRange(tasks[0].ID, 0, 0)
+		return err == nil && partial && string(content) == "first;"
+	}, 5*time.Second, 10*time.Millisecond)
+	content, _, partial, err := s.resultRange(tasks[0].ID, 2, 3)
+	require.NoError(t, err)
+	require.True(t, partial)
+	require.Equal(t, "rst", string(content))
+
+	// TiDB does not provide partial results.
+	_, _, _, err = s.resultRange(tasks[1].ID, 0, 0)
+	require.True(t, errorx.IsOfType(err, ErrTaskNotFinished))
+
+	close(release)
+	s.wg.Wait()
+	content, total, partial, err := s.resultRange(tasks[0].ID, 0, 0)
+	require.NoError(t, err)
+	require.False(t, partial)
+	require.Equal(t, "first;second", string(content))
+	require.Equal(t, int64(12), total)
+}
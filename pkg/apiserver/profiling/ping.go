@@ -0,0 +1,90 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+const (
+	pingTimeout     = 5 * time.Second
+	pingConcurrency = 16
+)
+
+type PingTargetsRequest struct {
+	Targets []model.RequestTargetNode `json:"targets"`
+	// Reject the request if any target is no longer present in the cluster topology, the same as StartRequest.
+	CheckTopology bool `json:"check_topology"`
+}
+
+type TargetReachability struct {
+	Target    model.RequestTargetNode `json:"target"`
+	Reachable bool                    `json:"reachable"`
+	Error     string                  `json:"error"`
+}
+
+// pingPath returns a cheap endpoint of the status API of a component, which is served on the same port as profiles.
+func (fts *fetchers) pingPath(kind model.NodeKind) string {
+	switch kind {
+	case model.NodeKindTiKV, model.NodeKindTiFlash:
+		return "/status"
+	default:
+		return fts.pprofPathPrefix(kind) + "/"
+	}
+}
+
+// fetcherOf returns the fetcher of targets of a component kind, or nil if it is not configured or supported.
+func (fts *fetchers) fetcherOf(kind model.NodeKind) profileFetcher {
+	switch kind {
+	case model.NodeKindTiKV:
+		return fts.tikv
+	case model.NodeKindTiFlash:
+		return fts.tiflash
+	case model.NodeKindTiDB:
+		return fts.tidb
+	case model.NodeKindPD:
+		return fts.pd
+	case model.NodeKindTiProxy:
+		return fts.tiproxy
+	case model.NodeKindTiCDC:
+		return fts.ticdc
+	case model.NodeKindPDTSO:
+		return fts.pdTSO
+	case model.NodeKindPDScheduling:
+		return fts.pdScheduling
+	default:
+		return nil
+	}
+}
+
+// pingTargets checks whether the status API of each target is reachable, so that unreachable targets can be
+// found before profiling. Targets are checked in parallel.
+func (s *Service) pingTargets(ctx context.Context, req *PingTargetsRequest) ([]TargetReachability, error) {
+	if req.CheckTopology {
+		if err := s.checkTargetsInTopology(ctx, req.Targets); err != nil {
+			return nil, err
+		}
+	}
+	results := make([]TargetReachability, len(req.Targets))
+	_ = forEachParallel(len(req.Targets), pingConcurrency, func(i int) error {
+		target := req.Targets[i]
+		results[i].Target = target
+		f := s.fetchers.fetcherOf(target.Kind)
+		if f == nil {
+			results[i].Error = ErrClientNotConfigured.New("no client is configured for %s", target.Kind).Error()
+			return nil
+		}
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		defer cancel()
+		if _, err := f.fetch(&fetchOptions{ctx: pingCtx, ip: target.IP, port: target.Port, path: s.fetchers.pingPath(target.Kind)}); err != nil {
+			results[i].Error = err.Error()
+			return nil
+		}
+		results[i].Reachable = true
+		return nil
+	})
+	return results, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/topo"
+)
+
+func TestPingTargets(t *testing.T) {
+	s := newTestService(t)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		require.Equal(t, "/debug/pprof/", op.path)
+		if op.ip == "10.0.0.2" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return []byte("<html></html>"), nil
+	}}
+	s.fetchers.tikv = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		require.Equal(t, "/status", op.path)
+		return nil, nil
+	}}
+	targets := []model.RequestTargetNode{
+		{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.1:4000", IP: "10.0.0.1", Port: 10080},
+		{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.2:4000", IP: "10.0.0.2", Port: 10080},
+		{Kind: model.NodeKindTiKV, DisplayName: "10.0.0.3:20160", IP: "10.0.0.3", Port: 20180},
+		{Kind: model.NodeKindPD, DisplayName: "10.0.0.4:2379", IP: "10.0.0.4", Port: 2379},
+	}
+
+	results, err := s.pingTargets(context.Background(), &PingTargetsRequest{Targets: targets})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	for i, result := range results {
+		require.Equal(t, targets[i], result.Target)
+	}
+	require.True(t, results[0].Reachable)
+	require.Empty(t, results[0].Error)
+	require.False(t, results[1].Reachable)
+	require.Contains(t, results[1].Error, "connection refused")
+	require.True(t, results[2].Reachable)
+	require.False(t, results[3].Reachable)
+	require.Contains(t, results[3].Error, "no client is configured")
+
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetTiDB", mock.Anything).Return([]topo.TiDBInfo{
+		{IP: "10.0.0.1", Port: 4000, StatusPort: 10080},
+	}, nil)
+	s.topoProvider = provider
+	_, err = s.pingTargets(context.Background(), &PingTargetsRequest{Targets: targets[:2], CheckTopology: true})
+	require.True(t, errorx.IsOfType(err, ErrTargetNotInTopology))
+}
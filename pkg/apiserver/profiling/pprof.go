@@ -3,24 +3,37 @@
 package profiling
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"strconv"
+	"time"
 
 	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
 )
 
 type pprofOptions struct {
+	ctx                context.Context
+	timeout            time.Duration // The time allowed for each request, which is not limited when it is 0
 	duration           uint
 	fileNameWithoutExt string
+	dir                string
 
 	target        *model.RequestTargetNode
 	fetcher       *profileFetcher
 	profilingType TaskProfilingType
+	customPath    string
+	pathPrefix    string
 }
 
 func fetchPprof(op *pprofOptions) (string, TaskRawDataType, error) {
-	fetcher := &fetcher{profileFetcher: op.fetcher, target: op.target}
+	if *op.fetcher == nil {
+		return "", "", ErrClientNotConfigured.New("no client is configured for %s", op.target.Kind)
+	}
+	fetcher := &fetcher{ctx: op.ctx, timeout: op.timeout, profileFetcher: op.fetcher, target: op.target, dir: op.dir, customPath: op.customPath, pathPrefix: op.pathPrefix}
 	tmpPath, rawDataType, err := fetcher.FetchAndWriteToFile(op.duration, op.fileNameWithoutExt, op.profilingType)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to fetch and write to temp file: %v", err)
@@ -30,8 +43,13 @@ func fetchPprof(op *pprofOptions) (string, TaskRawDataType, error) {
 }
 
 type fetcher struct {
+	ctx            context.Context
+	timeout        time.Duration // The time allowed for each request, which is not limited when it is 0
 	target         *model.RequestTargetNode
 	profileFetcher *profileFetcher
+	dir            string // The directory to write the profile, or the temporary directory of the OS if it is empty
+	customPath     string // The path to fetch for ProfilingTypeCustom
+	pathPrefix     string // The path under which pprof handlers are served, e.g. /debug/pprof
 }
 
 func (f *fetcher) FetchAndWriteToFile(duration uint, fileNameWithoutExt string, profilingType TaskProfilingType) (string, TaskRawDataType, error) {
@@ -41,24 +59,53 @@ func (f *fetcher) FetchAndWriteToFile(duration uint, fileNameWithoutExt string,
 	var url string
 	switch profilingType {
 	case ProfilingTypeCPU:
-		url = "/debug/pprof/profile?seconds=" + secs
+		url = f.pathPrefix + "/profile?seconds=" + secs
 		profilingRawDataType = RawDataTypeProtobuf
 		fileExtenstion = "*.proto"
 	case ProfilingTypeHeap:
-		url = "/debug/pprof/heap"
+		url = f.pathPrefix + "/heap"
 		profilingRawDataType = RawDataTypeProtobuf
 		fileExtenstion = "*.proto"
 	case ProfilingTypeGoroutine:
-		url = "/debug/pprof/goroutine?debug=1"
+		url = f.pathPrefix + "/goroutine?debug=1"
 		profilingRawDataType = RawDataTypeText
 		fileExtenstion = "*.txt"
 	case ProfilingTypeMutex:
-		url = "/debug/pprof/mutex?debug=1"
+		url = f.pathPrefix + "/mutex?debug=1"
 		profilingRawDataType = RawDataTypeText
 		fileExtenstion = "*.txt"
+	case ProfilingTypeHeapDiff:
+		url = f.pathPrefix + "/heap"
+		profilingRawDataType = RawDataTypeProtobuf
+		fileExtenstion = "*.proto"
+	case ProfilingTypeBlock:
+		url = f.pathPrefix + "/block"
+		profilingRawDataType = RawDataTypeProtobuf
+		fileExtenstion = "*.proto"
+	case ProfilingTypeGoroutineFull:
+		url = f.pathPrefix + "/goroutine?debug=2"
+		profilingRawDataType = RawDataTypeText
+		fileExtenstion = "*.txt"
+	case ProfilingTypeTrace:
+		url = f.pathPrefix + "/trace?seconds=" + secs
+		profilingRawDataType = RawDataTypeTrace
+		fileExtenstion = "*.trace"
+	case ProfilingTypeAllocs:
+		url = f.pathPrefix + "/allocs"
+		profilingRawDataType = RawDataTypeProtobuf
+		fileExtenstion = "*.proto"
+	case ProfilingTypeCustom:
+		url = f.customPath
+		profilingRawDataType = RawDataTypeRaw
+		fileExtenstion = "*.bin"
 	}
 
-	tmpfile, err := ioutil.TempFile("", fileNameWithoutExt+"_"+fileExtenstion)
+	if f.dir != "" {
+		if err := os.MkdirAll(f.dir, 0o700); err != nil {
+			return "", "", fmt.Errorf("failed to create directory to write profile: %v", err)
+		}
+	}
+	tmpfile, err := ioutil.TempFile(f.dir, fileNameWithoutExt+"_"+fileExtenstion)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to create tmpfile to write profile: %v", err)
 	}
@@ -67,10 +114,21 @@ func (f *fetcher) FetchAndWriteToFile(duration uint, fileNameWithoutExt string,
 		_ = tmpfile.Close()
 	}()
 
-	resp, err := (*f.profileFetcher).fetch(&fetchOptions{ip: f.target.IP, port: f.target.Port, path: url})
+	var resp []byte
+	if profilingType == ProfilingTypeHeapDiff {
+		resp, err = f.fetchHeapDiff(url, duration)
+	} else {
+		resp, err = (*f.profileFetcher).fetch(&fetchOptions{ctx: f.ctx, ip: f.target.IP, port: f.target.Port, path: url, timeout: f.timeout})
+	}
 	if err != nil {
 		return "", "", fmt.Errorf("failed to fetch profile with %v format: %v", fileExtenstion, err)
 	}
+	if profilingRawDataType == RawDataTypeProtobuf {
+		resp, err = unwrapNestedGzip(resp)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to decode profile: %v", err)
+		}
+	}
 
 	_, err = tmpfile.Write(resp)
 	if err != nil {
@@ -79,3 +137,40 @@ func (f *fetcher) FetchAndWriteToFile(duration uint, fileNameWithoutExt string,
 
 	return tmpfile.Name(), profilingRawDataType, nil
 }
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompressProfile returns the plain protobuf of a profile, for tools which cannot read gzipped profiles.
+// Profiles which are not gzipped are returned unchanged.
+func decompressProfile(data []byte) ([]byte, error) {
+	data, err := unwrapNestedGzip(data)
+	if err != nil || !bytes.HasPrefix(data, gzipMagic) {
+		return data, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(zr)
+}
+
+// unwrapNestedGzip removes redundant gzip layers of a protobuf profile, which happens when a component returns
+// a gzipped profile with `Content-Encoding: gzip` and the body is only decoded once by the transport.
+// The result is either a plain protobuf or gzipped once, both of which can be parsed as a pprof profile.
+func unwrapNestedGzip(data []byte) ([]byte, error) {
+	for bytes.HasPrefix(data, gzipMagic) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		inner, err := ioutil.ReadAll(zr)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.HasPrefix(inner, gzipMagic) {
+			break
+		}
+		data = inner
+	}
+	return data, nil
+}
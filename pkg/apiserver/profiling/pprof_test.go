@@ -0,0 +1,88 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+func gzipTestData(t *testing.T, data []byte) []byte {
+	buf := bytes.Buffer{}
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestUnwrapNestedGzip(t *testing.T) {
+	// Profiles written by pprof are already gzipped.
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	require.True(t, bytes.HasPrefix(content, gzipMagic))
+
+	data, err := unwrapNestedGzip(content)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	data, err = unwrapNestedGzip(gzipTestData(t, content))
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	data, err = unwrapNestedGzip(gzipTestData(t, gzipTestData(t, content)))
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	plain := []byte("not gzipped")
+	data, err = unwrapNestedGzip(plain)
+	require.NoError(t, err)
+	require.Equal(t, plain, data)
+
+	_, err = unwrapNestedGzip(append(gzipMagic, 0x00))
+	require.Error(t, err)
+}
+
+func TestDecompressProfile(t *testing.T) {
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+
+	data, err := decompressProfile(content)
+	require.NoError(t, err)
+	require.False(t, bytes.HasPrefix(data, gzipMagic))
+	p, err := profile.ParseUncompressed(data)
+	require.NoError(t, err)
+	flat, _ := flatByFunction(p, defaultSampleIndex(p))
+	require.Equal(t, map[string]int64{"main.work": 10000000}, flat)
+
+	// Profiles which are not gzipped are returned unchanged.
+	plain, err := decompressProfile(data)
+	require.NoError(t, err)
+	require.Equal(t, data, plain)
+}
+
+func TestFetchDoubleGzippedProfile(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return gzipTestData(t, content), nil
+	}}
+
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	data, err := ioutil.ReadFile(tasks[0].FilePath)
+	require.NoError(t, err)
+	p, err := parseProtobufProfile(data)
+	require.NoError(t, err)
+	flat, _ := flatByFunction(p, defaultSampleIndex(p))
+	require.Equal(t, int64(10000000), flat["main.work"])
+}
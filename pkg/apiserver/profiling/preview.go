@@ -0,0 +1,118 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+const previewTopN = 5
+
+// ProfilePreview is a lightweight summary of a profiling result for a quick look, without downloading it.
+type ProfilePreview struct {
+	TaskID        uint              `json:"task_id"`
+	ProfilingType TaskProfilingType `json:"profiling_type"`
+	RawDataType   TaskRawDataType   `json:"raw_data_type"`
+	SizeBytes     int64             `json:"size_bytes"`
+	// The profile duration, which is 0 for snapshot profiling types, e.g. heap.
+	DurationSecs uint `json:"duration_secs"`
+	// The sample type, the total value of samples and the top functions by the flat value, only for profiles in
+	// the protobuf format, e.g. CPU and heap.
+	SampleType string        `json:"sample_type,omitempty"`
+	Total      int64         `json:"total,omitempty"`
+	Functions  []TopFunction `json:"functions,omitempty"`
+	// The number of goroutines, only for goroutine dumps.
+	NumGoroutines int `json:"num_goroutines,omitempty"`
+	// The number of goroutines in each wait state, e.g. "running" or "chan receive", only for goroutine dumps with
+	// full stacks.
+	GoroutinesByState map[string]int `json:"goroutines_by_state,omitempty"`
+}
+
+// profilePreview summarizes a finished task. Only the result of the task is read, which is much cheaper than
+// rendering it, e.g. as a flame graph.
+func (s *Service) profilePreview(taskID uint) (*ProfilePreview, error) {
+	var task TaskModel
+	if err := s.params.LocalStore.Where("id = ?", taskID).Find(&task).Error; err != nil {
+		return nil, err
+	}
+	if task.ID == 0 {
+		return nil, rest.ErrNotFound.New("task %d does not exist", taskID)
+	}
+	if task.State != TaskStateFinish {
+		return nil, ErrTaskNotFinished.New("task %d is in %s state", taskID, taskStateLabels[task.State])
+	}
+	var taskGroup TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", task.TaskGroupID).First(&taskGroup).Error; err != nil {
+		return nil, err
+	}
+
+	preview := &ProfilePreview{
+		TaskID:        task.ID,
+		ProfilingType: task.ProfilingType,
+		RawDataType:   task.RawDataType,
+		SizeBytes:     task.SizeBytes,
+	}
+	if !task.ProfilingType.isSnapshot() {
+		preview.DurationSecs = task.profileDurationSecs(taskGroup.ProfileDurationSecs)
+	}
+	switch {
+	case task.RawDataType == RawDataTypeProtobuf:
+		top, err := topOfProfiles(s.results, []TaskModel{task}, previewTopN, 1)
+		if err != nil {
+			return nil, err
+		}
+		preview.SampleType = top.SampleType
+		preview.Total = top.Total
+		preview.Functions = top.Functions
+	case task.RawDataType == RawDataTypeText && task.ProfilingType == ProfilingTypeGoroutine:
+		content, err := s.results.readResult(&task)
+		if err != nil {
+			return nil, err
+		}
+		preview.NumGoroutines = goroutineProfileTotal(content)
+	case task.RawDataType == RawDataTypeText && task.ProfilingType == ProfilingTypeGoroutineFull:
+		content, err := s.results.readResult(&task)
+		if err != nil {
+			return nil, err
+		}
+		preview.GoroutinesByState = goroutinesByState(content)
+		for _, n := range preview.GoroutinesByState {
+			preview.NumGoroutines += n
+		}
+	}
+	return preview, nil
+}
+
+var (
+	goroutineProfileTotalRegexp = regexp.MustCompile(`^goroutine profile: total (\d+)`)
+	// Matches the header of each goroutine in a full dump, e.g. "goroutine 1 [chan receive, 5 minutes]:".
+	goroutineHeaderRegexp = regexp.MustCompile(`^goroutine \d+ \[([^,\]]+)`)
+)
+
+// goroutineProfileTotal returns the number of goroutines in the header of a goroutine profile with debug=1.
+func goroutineProfileTotal(content []byte) int {
+	m := goroutineProfileTotalRegexp.FindSubmatch(content)
+	if m == nil {
+		return 0
+	}
+	total, _ := strconv.Atoi(string(m[1]))
+	return total
+}
+
+// goroutinesByState counts the goroutines in each wait state of a goroutine dump with debug=2.
+func goroutinesByState(content []byte) map[string]int {
+	states := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if m := goroutineHeaderRegexp.FindSubmatch(scanner.Bytes()); m != nil {
+			states[string(m[1])]++
+		}
+	}
+	return states
+}
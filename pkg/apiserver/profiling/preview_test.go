@@ -0,0 +1,58 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+func TestProfilePreview(t *testing.T) {
+	s := newTestService(t)
+	flat := map[string]int64{"f1": 600, "f2": 500, "f3": 400, "f4": 300, "f5": 200, "f6": 100}
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		switch op.path {
+		case "/debug/pprof/goroutine?debug=2":
+			return []byte("goroutine 1 [running]:\nmain.main()\n\ngoroutine 7 [chan receive, 5 minutes]:\nmain.wait()\n\n" +
+				"goroutine 8 [chan receive]:\nmain.wait()\n"), nil
+		default:
+			return newTestCPUProfile(t, flat), nil
+		}
+	}}
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU, ProfilingTypeGoroutineFull},
+	})
+	require.Len(t, tasks, 2)
+
+	preview, err := s.profilePreview(tasks[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, ProfilingTypeCPU, preview.ProfilingType)
+	require.Equal(t, uint(1), preview.DurationSecs)
+	require.Equal(t, tasks[0].SizeBytes, preview.SizeBytes)
+	require.Equal(t, "cpu", preview.SampleType)
+	require.Equal(t, int64(2100), preview.Total)
+	require.Len(t, preview.Functions, previewTopN)
+	require.Equal(t, TopFunction{Function: "f1", Flat: 600, Share: 600.0 / 2100}, preview.Functions[0])
+	require.Equal(t, "f5", preview.Functions[4].Function)
+
+	preview, err = s.profilePreview(tasks[1].ID)
+	require.NoError(t, err)
+	require.Zero(t, preview.DurationSecs)
+	require.Equal(t, 3, preview.NumGoroutines)
+	require.Equal(t, map[string]int{"running": 1, "chan receive": 2}, preview.GoroutinesByState)
+
+	_, err = s.profilePreview(tasks[1].ID + 1)
+	require.True(t, errorx.IsOfType(err, rest.ErrNotFound))
+}
+
+func TestGoroutineProfileTotal(t *testing.T) {
+	require.Equal(t, 42, goroutineProfileTotal([]byte("goroutine profile: total 42\n10 @ 0x1 0x2\n")))
+	require.Zero(t, goroutineProfileTotal([]byte("unexpected")))
+}
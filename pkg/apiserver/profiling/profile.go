@@ -4,29 +4,77 @@ package profiling
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
 )
 
-func profileAndWritePprof(ctx context.Context, fts *fetchers, target *model.RequestTargetNode, fileNameWithoutExt string, profileDurationSecs uint, profilingType TaskProfilingType) (string, TaskRawDataType, error) {
+// supportsProfilingType returns whether a kind of component serves profiles of the profiling type. Tasks of
+// other profiling types are skipped.
+func supportsProfilingType(kind model.NodeKind, profilingType TaskProfilingType) bool {
+	switch kind {
+	case model.NodeKindTiKV, model.NodeKindTiFlash:
+		// TiKV and TiFlash only support CPU Profiling
+		return profilingType == ProfilingTypeCPU
+	default:
+		return true
+	}
+}
+
+type KindCapabilitiesResponse struct {
+	// The profiling types served by each kind of component which can be profiled. Tasks of other profiling types
+	// are skipped.
+	ProfilingTypes map[model.NodeKind]TaskProfilingTypeList `json:"profiling_types"`
+}
+
+// kindCapabilities returns the profiling types supported by each kind of component, by the same rules as tasks are
+// skipped, so that only supported profiling types are offered.
+func kindCapabilities() *KindCapabilitiesResponse {
+	allTypes := make(TaskProfilingTypeList, 0, len(profilingTypeMap))
+	for profilingType := range profilingTypeMap {
+		allTypes = append(allTypes, profilingType)
+	}
+	sort.Slice(allTypes, func(i, j int) bool { return allTypes[i] < allTypes[j] })
+
+	resp := &KindCapabilitiesResponse{ProfilingTypes: make(map[model.NodeKind]TaskProfilingTypeList, len(profilingTargetKinds))}
+	for _, kind := range profilingTargetKinds {
+		types := make(TaskProfilingTypeList, 0, len(allTypes))
+		for _, profilingType := range allTypes {
+			if supportsProfilingType(model.NodeKind(kind), profilingType) {
+				types = append(types, profilingType)
+			}
+		}
+		resp.ProfilingTypes[model.NodeKind(kind)] = types
+	}
+	return resp
+}
+
+func profileAndWritePprof(ctx context.Context, timeout time.Duration, fts *fetchers, target *model.RequestTargetNode, fileNameWithoutExt string, profileDurationSecs uint, profilingType TaskProfilingType, customPath string) (string, TaskRawDataType, error) {
+	op := &pprofOptions{ctx: ctx, timeout: timeout, duration: profileDurationSecs, fileNameWithoutExt: fileNameWithoutExt, dir: fts.resultDir, target: target, profilingType: profilingType, customPath: customPath, pathPrefix: fts.pprofPathPrefix(target.Kind)}
+	if !supportsProfilingType(target.Kind, profilingType) {
+		return "", "", ErrUnsupportedProfilingType.NewWithNoMessage()
+	}
 	switch target.Kind {
 	case model.NodeKindTiKV:
-		// TiKV only supports CPU Profiling
-		if profilingType != ProfilingTypeCPU {
-			return "", "", ErrUnsupportedProfilingType.NewWithNoMessage()
-		}
-		return fetchPprof(&pprofOptions{duration: profileDurationSecs, fileNameWithoutExt: fileNameWithoutExt, target: target, fetcher: &fts.tikv, profilingType: profilingType})
+		op.fetcher = &fts.tikv
 	case model.NodeKindTiFlash:
-		// TiFlash only supports CPU Profiling
-		if profilingType != ProfilingTypeCPU {
-			return "", "", ErrUnsupportedProfilingType.NewWithNoMessage()
-		}
-		return fetchPprof(&pprofOptions{duration: profileDurationSecs, fileNameWithoutExt: fileNameWithoutExt, target: target, fetcher: &fts.tiflash, profilingType: profilingType})
+		// CPU profiles of TiFlash are served by its embedded proxy on the status port.
+		op.fetcher = &fts.tiflash
 	case model.NodeKindTiDB:
-		return fetchPprof(&pprofOptions{duration: profileDurationSecs, fileNameWithoutExt: fileNameWithoutExt, target: target, fetcher: &fts.tidb, profilingType: profilingType})
+		op.fetcher = &fts.tidb
 	case model.NodeKindPD:
-		return fetchPprof(&pprofOptions{duration: profileDurationSecs, fileNameWithoutExt: fileNameWithoutExt, target: target, fetcher: &fts.pd, profilingType: profilingType})
+		op.fetcher = &fts.pd
+	case model.NodeKindTiProxy:
+		op.fetcher = &fts.tiproxy
+	case model.NodeKindTiCDC:
+		op.fetcher = &fts.ticdc
+	case model.NodeKindPDTSO:
+		op.fetcher = &fts.pdTSO
+	case model.NodeKindPDScheduling:
+		op.fetcher = &fts.pdScheduling
 	default:
 		return "", "", ErrUnsupportedProfilingTarget.New(target.String())
 	}
+	return fetchPprof(op)
 }
@@ -0,0 +1,26 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+)
+
+func TestKindCapabilities(t *testing.T) {
+	resp := kindCapabilities()
+	require.Len(t, resp.ProfilingTypes, len(profilingTargetKinds))
+	require.Equal(t, TaskProfilingTypeList{ProfilingTypeCPU}, resp.ProfilingTypes[model.NodeKindTiKV])
+	require.Contains(t, resp.ProfilingTypes[model.NodeKindTiDB], ProfilingTypeMutex)
+	require.Len(t, resp.ProfilingTypes[model.NodeKindTiDB], len(profilingTypeMap))
+
+	// Tasks of profiling types which are not listed are skipped.
+	target := &model.RequestTargetNode{Kind: model.NodeKindTiKV, IP: "127.0.0.1", Port: 20180}
+	_, _, err := profileAndWritePprof(context.Background(), 0, &fetchers{}, target, "mutex", 0, ProfilingTypeMutex, "")
+	require.True(t, errorx.IsOfType(err, ErrUnsupportedProfilingType))
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+// groupProgressInterval is how often the progress of a subscribed task group is checked. Events are only sent
+// when the progress is changed.
+const groupProgressInterval = 500 * time.Millisecond
+
+// GroupProgressEvent is the progress of a task group and its tasks at a time.
+type GroupProgressEvent struct {
+	TaskGroupID uint           `json:"task_group_id"`
+	State       TaskState      `json:"state"`
+	Tasks       []TaskProgress `json:"tasks"`
+}
+
+type TaskProgress struct {
+	ID       uint      `json:"id"`
+	State    TaskState `json:"state"`
+	Progress float64   `json:"progress"`
+}
+
+// groupProgress returns the current progress of a task group, or nil if the task group does not exist.
+func (s *Service) groupProgress(taskGroupID uint) (*GroupProgressEvent, error) {
+	var taskGroup TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", taskGroupID).Find(&taskGroup).Error; err != nil {
+		return nil, err
+	}
+	if taskGroup.ID == 0 {
+		return nil, nil
+	}
+	var tasks []TaskModel
+	if err := s.params.LocalStore.Where("task_group_id = ?", taskGroupID).Order("id ASC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	event := &GroupProgressEvent{TaskGroupID: taskGroup.ID, State: taskGroup.State, Tasks: make([]TaskProgress, 0, len(tasks))}
+	for i := range tasks {
+		event.Tasks = append(event.Tasks, TaskProgress{
+			ID:       tasks[i].ID,
+			State:    tasks[i].State,
+			Progress: estimateProgress(&tasks[i], taskGroup.ProfileDurationSecs, now),
+		})
+	}
+	return event, nil
+}
+
+// subscribeGroup returns a channel receiving the progress of a task group whenever any of its tasks changes its
+// state or progress, so that the progress does not need to be polled by clients. The current progress is always
+// received first. The channel is closed after the task group is stopped, or after the returned function is called
+// to unsubscribe.
+func (s *Service) subscribeGroup(taskGroupID uint) (<-chan GroupProgressEvent, func(), error) {
+	first, err := s.groupProgress(taskGroupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if first == nil {
+		return nil, nil, rest.ErrNotFound.New("task group %d does not exist", taskGroupID)
+	}
+
+	events := make(chan GroupProgressEvent)
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+	unsubscribe := func() {
+		stopOnce.Do(func() { close(stop) })
+	}
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(groupProgressInterval)
+		defer ticker.Stop()
+		event := first
+		var last *GroupProgressEvent
+		for {
+			if last == nil || !reflect.DeepEqual(event, last) {
+				select {
+				case events <- *event:
+				case <-stop:
+					return
+				}
+				last = event
+			}
+			if event.State != TaskStateRunning {
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			case <-s.lifecycleCtx.Done():
+				return
+			}
+			next, err := s.groupProgress(taskGroupID)
+			if err != nil {
+				log.Warn("failed to get the progress of profiling task group", zap.Uint("task_group_id", taskGroupID), zap.Error(err))
+				continue
+			}
+			if next == nil {
+				// The task group is deleted.
+				return
+			}
+			event = next
+		}
+	}()
+	return events, unsubscribe, nil
+}
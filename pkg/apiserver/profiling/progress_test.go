@@ -0,0 +1,124 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+// receiveEvent receives the next event of a subscription, which is false if the channel is closed.
+func receiveEvent(t *testing.T, events <-chan GroupProgressEvent) (GroupProgressEvent, bool) {
+	select {
+	case event, ok := <-events:
+		return event, ok
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "no event is received")
+		return GroupProgressEvent{}, false
+	}
+}
+
+func TestSubscribeGroup(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	release := make(chan struct{})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		<-release
+		return content, nil
+	}}
+	s.fetchers.pd = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	req := &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindPD, DisplayName: "127.0.0.1:2379", IP: "127.0.0.1", Port: 2379},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	}
+	taskGroup, err := s.startGroup(context.Background(), req)
+	require.NoError(t, err)
+
+	events, unsubscribe, err := s.subscribeGroup(taskGroup.ID)
+	require.NoError(t, err)
+	defer unsubscribe()
+	event, ok := receiveEvent(t, events)
+	require.True(t, ok)
+	require.Equal(t, taskGroup.ID, event.TaskGroupID)
+	require.Equal(t, TaskStateRunning, event.State)
+	require.Len(t, event.Tasks, 2)
+
+	// The TiDB task is held until the finished PD task is received.
+	received := []GroupProgressEvent{event}
+	released := false
+	for {
+		event, ok := receiveEvent(t, events)
+		if !ok {
+			break
+		}
+		received = append(received, event)
+		if !released && event.Tasks[1].State == TaskStateFinish {
+			require.Equal(t, TaskStateRunning, event.Tasks[0].State)
+			require.Equal(t, TaskStateRunning, event.State)
+			close(release)
+			released = true
+		}
+	}
+	require.True(t, released)
+	last := received[len(received)-1]
+	require.Equal(t, TaskStateFinish, last.State)
+	for _, task := range last.Tasks {
+		require.Equal(t, TaskStateFinish, task.State)
+		require.Equal(t, float64(1), task.Progress)
+	}
+	s.wg.Wait()
+
+	// The progress of a stopped task group is received once.
+	events, _, err = s.subscribeGroup(taskGroup.ID)
+	require.NoError(t, err)
+	event, ok = receiveEvent(t, events)
+	require.True(t, ok)
+	require.Equal(t, last, event)
+	_, ok = receiveEvent(t, events)
+	require.False(t, ok)
+
+	_, _, err = s.subscribeGroup(taskGroup.ID + 1)
+	require.True(t, errorx.IsOfType(err, rest.ErrNotFound))
+}
+
+func TestUnsubscribeGroup(t *testing.T) {
+	s := newTestService(t)
+	release := make(chan struct{})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		<-release
+		return newTestCPUProfile(t, map[string]int64{"main.work": 10000000}), nil
+	}}
+	taskGroup, err := s.startGroup(context.Background(), &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.NoError(t, err)
+	defer s.wg.Wait()
+	defer close(release)
+
+	events, unsubscribe, err := s.subscribeGroup(taskGroup.ID)
+	require.NoError(t, err)
+	_, ok := receiveEvent(t, events)
+	require.True(t, ok)
+
+	// The channel is closed once unsubscribed, even if the task group is still running.
+	unsubscribe()
+	unsubscribe()
+	for ok {
+		_, ok = receiveEvent(t, events)
+	}
+}
@@ -28,14 +28,20 @@ func convertProtobufToSVG(content []byte, task TaskModel) ([]byte, error) {
 }
 
 func convertProtobufToDot(content []byte, task TaskModel) ([]byte, error) {
-	args := []string{
-		"-dot",
+	return generatePprofReport(content, "-dot")
+}
+
+// generatePprofReport generates a report of a protobuf profile using the pprof driver, e.g. `-dot` or `-top`.
+func generatePprofReport(content []byte, reportArgs ...string) ([]byte, error) {
+	args := make([]string, 0, len(reportArgs)+6)
+	args = append(args, reportArgs...)
+	args = append(args,
 		// prevent printing stdout
 		"-output", "dummy",
 		"-seconds", strconv.Itoa(int(1)),
-	}
+	)
 	// the addr is required for driver. Pporf but not used here
-	// since we have fetched proto content and just want to convert it to dot
+	// since we have fetched proto content and just want to generate the report
 	address := ""
 	args = append(args, address)
 	f := &flagSet{
@@ -85,8 +91,7 @@ type writeCloser struct {
 }
 
 func (wc *writeCloser) Write(p []byte) (n int, err error) {
-	wc.data = make([]byte, len(p))
-	copy(wc.data, p)
+	wc.data = append(wc.data, p...)
 	return len(p), nil
 }
 
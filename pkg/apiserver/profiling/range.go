@@ -0,0 +1,39 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+// resultRange reads at most length bytes of the result of a finished task from the offset, along with the total
+// size of the result, so that a large result can be downloaded in parts. All bytes from the offset are read
+// when length is 0. For a running task whose target provides partial results, the bytes received so far are read
+// instead, and partial is true.
+func (s *Service) resultRange(taskID uint, offset int64, length int64) (content []byte, total int64, partial bool, err error) {
+	if offset < 0 || length < 0 {
+		return nil, 0, false, rest.ErrBadRequest.New("offset and length must not be negative")
+	}
+	var task TaskModel
+	if err := s.params.LocalStore.Where("id = ?", taskID).First(&task).Error; err != nil {
+		return nil, 0, false, err
+	}
+	if task.State == TaskStateFinish {
+		content, err = s.results.readResult(&task)
+	} else {
+		content, err = s.partialResult(&task)
+		partial = true
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+	total = int64(len(content))
+	if offset > total || (offset == total && total > 0) {
+		return nil, 0, false, rest.ErrBadRequest.New("offset %d is out of range, the result has %d bytes", offset, total)
+	}
+	end := total
+	if length > 0 && offset+length < total {
+		end = offset + length
+	}
+	return content[offset:end], total, partial, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+func TestResultRange(t *testing.T) {
+	s := newTestService(t)
+	blob := []byte("0123456789abcdefghij")
+	task := newTestFinishedTask(t, s, ProfilingTypeTrace, RawDataTypeTrace, blob)
+
+	content, total, _, err := s.resultRange(task.ID, 5, 10)
+	require.NoError(t, err)
+	require.Equal(t, []byte("56789abcde"), content)
+	require.Equal(t, int64(20), total)
+
+	// All bytes from the offset are read when the length is 0 or beyond the end.
+	content, _, _, err = s.resultRange(task.ID, 15, 0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("fghij"), content)
+	content, _, _, err = s.resultRange(task.ID, 15, 100)
+	require.NoError(t, err)
+	require.Equal(t, []byte("fghij"), content)
+
+	_, _, _, err = s.resultRange(task.ID, 20, 1)
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+	require.Contains(t, err.Error(), "offset 20 is out of range")
+	_, _, _, err = s.resultRange(task.ID, -1, 1)
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+	_, _, _, err = s.resultRange(task.ID, 0, -1)
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+
+	_, _, _, err = s.resultRange(task.ID+1, 0, 1)
+	require.Error(t, err)
+}
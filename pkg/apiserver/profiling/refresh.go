@@ -0,0 +1,200 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+// refreshTask profiles the target of a finished task again and replaces its result in place, keeping the task ID.
+// The task group is marked as running until the refresh is done. If the refresh fails, a previously finished
+// result is kept. Results registered as baselines are pinned and cannot be refreshed.
+func (s *Service) refreshTask(taskID uint) (*TaskModel, error) {
+	var previous TaskModel
+	if err := s.params.LocalStore.Where("id = ?", taskID).First(&previous).Error; err != nil {
+		return nil, err
+	}
+	if previous.State == TaskStateRunning {
+		return nil, ErrIgnoredRequest.New("task %d is still running", taskID)
+	}
+	baselines, err := s.baselinesOfTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(baselines) > 0 {
+		return nil, ErrGroupPinned.New("task %d is used by baselines %s, so its result cannot be replaced", taskID, strings.Join(baselines, ", "))
+	}
+	var groupModel TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", previous.TaskGroupID).First(&groupModel).Error; err != nil {
+		return nil, err
+	}
+	taskGroup, err := s.claimGroup(&groupModel, []TaskModel{previous})
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTask(s.lifecycleCtx, taskGroup, previous.Target, s.fetchers, s.results, previous.ProfilingType)
+	t.ID = previous.ID
+	t.Attempt = previous.Attempt + 1
+	t.CustomPath = previous.CustomPath
+	t.DurationSecs = previous.DurationSecs
+	t.captureBuildID = previous.BuildID != ""
+	t.timeout = s.fetchTimeout(previous.profileDurationSecs(groupModel.ProfileDurationSecs), 0)
+	if err := s.params.LocalStore.Save(t.TaskModel).Error; err != nil {
+		s.releaseGroup(taskGroup)
+		return nil, err
+	}
+	s.tasks.Store(t.ID, t)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		t.run()
+		s.tasks.Delete(t.ID)
+
+		switch {
+		case t.State == TaskStateFinish:
+			if err := s.results.removeResult(previous.FilePath, previous.StorageKey); err != nil {
+				log.Warn("failed to remove profiling result", zap.String("path", previous.FilePath), zap.Error(err))
+			}
+		case previous.State == TaskStateFinish:
+			t.log().Warn("failed to refresh profiling task, keep the previous result", zap.String("error", t.Error))
+			previous.Attempt = t.Attempt
+			s.params.LocalStore.Save(&previous)
+		}
+
+		s.releaseGroup(taskGroup)
+	}()
+
+	m := t.snapshot()
+	return &m, nil
+}
+
+// retryGroup profiles the targets of failed tasks in a stopped task group again, reusing their task IDs.
+// Finished tasks are left untouched. The task group is marked as running until all retried tasks are stopped.
+func (s *Service) retryGroup(taskGroupID uint) ([]TaskModel, error) {
+	var groupModel TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", taskGroupID).Find(&groupModel).Error; err != nil {
+		return nil, err
+	}
+	if groupModel.ID == 0 {
+		return nil, rest.ErrNotFound.New("task group %d does not exist", taskGroupID)
+	}
+	if groupModel.State == TaskStateRunning {
+		return nil, ErrGroupRunning.New("task group %d is still running", taskGroupID)
+	}
+	var failedTasks []TaskModel
+	if err := s.params.LocalStore.Where("task_group_id = ? AND state = ?", taskGroupID, TaskStateError).Order("id ASC").Find(&failedTasks).Error; err != nil {
+		return nil, err
+	}
+	if len(failedTasks) == 0 {
+		return nil, ErrIgnoredRequest.New("task group %d has no failed tasks", taskGroupID)
+	}
+
+	taskGroup, err := s.claimGroup(&groupModel, failedTasks)
+	if err != nil {
+		return nil, err
+	}
+
+	fts := s.fetchers.shared()
+	tasks := make([]*Task, 0, len(failedTasks))
+	for _, previous := range failedTasks {
+		t := NewTask(s.lifecycleCtx, taskGroup, previous.Target, fts, s.results, previous.ProfilingType)
+		t.ID = previous.ID
+		t.Attempt = previous.Attempt + 1
+		t.CustomPath = previous.CustomPath
+		t.DurationSecs = previous.DurationSecs
+		t.captureBuildID = previous.BuildID != ""
+		t.timeout = s.fetchTimeout(previous.profileDurationSecs(groupModel.ProfileDurationSecs), 0)
+		if err := s.params.LocalStore.Save(t.TaskModel).Error; err != nil {
+			// Tasks which are not saved are still failed, so the state of the task group is recomputed.
+			s.releaseGroup(taskGroup)
+			return nil, err
+		}
+		s.tasks.Store(t.ID, t)
+		tasks = append(tasks, t)
+	}
+
+	var wg sync.WaitGroup
+	for _, t := range tasks {
+		wg.Add(1)
+		s.wg.Add(1)
+		go func(t *Task) {
+			defer s.wg.Done()
+			defer wg.Done()
+			t.run()
+			s.tasks.Delete(t.ID)
+		}(t)
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		wg.Wait()
+		s.releaseGroup(taskGroup)
+	}()
+
+	result := make([]TaskModel, 0, len(tasks))
+	for _, t := range tasks {
+		result = append(result, t.snapshot())
+	}
+	return result, nil
+}
+
+// claimGroup marks a stopped task group as running to run some of its tasks again, e.g. to refresh or retry them.
+// The state is changed by a conditional update, so that only one of concurrent claims succeeds and the task group is
+// not deleted while the tasks are running. Like starting a task group, a group slot is acquired and the storage for
+// the results of the tasks is reserved. releaseGroup must be called once the tasks are stopped.
+func (s *Service) claimGroup(group *TaskGroupModel, tasks []TaskModel) (*TaskGroup, error) {
+	if err := s.acquireGroupSlot(); err != nil {
+		return nil, err
+	}
+	result := s.params.LocalStore.Model(&TaskGroupModel{}).
+		Where("id = ? AND state <> ?", group.ID, TaskStateRunning).
+		Update("state", TaskStateRunning)
+	if result.Error != nil {
+		s.releaseGroupSlot()
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		s.releaseGroupSlot()
+		return nil, ErrGroupRunning.New("task group %d is still running", group.ID)
+	}
+	reservedBytes, err := s.reserveTaskStorage(tasks, group.ProfileDurationSecs)
+	if err != nil {
+		if err := s.params.LocalStore.Model(&TaskGroupModel{}).Where("id = ?", group.ID).Update("state", group.State).Error; err != nil {
+			log.Warn("failed to restore task group state", zap.Uint("task_group_id", group.ID), zap.Error(err))
+		}
+		s.releaseGroupSlot()
+		return nil, err
+	}
+	group.State = TaskStateRunning
+	return &TaskGroup{TaskGroupModel: group, db: s.params.LocalStore, logger: s.groupLogger(group.ID), reservedBytes: reservedBytes}, nil
+}
+
+// releaseGroup stops running a task group claimed by claimGroup, whose state is recomputed from its tasks.
+func (s *Service) releaseGroup(taskGroup *TaskGroup) {
+	s.updateGroupState(taskGroup)
+	s.releaseGroupSlot()
+	s.releaseStorage(taskGroup.reservedBytes)
+}
+
+// updateGroupState recomputes and saves the state of a task group from the states of its tasks.
+func (s *Service) updateGroupState(taskGroup *TaskGroup) {
+	var tasks []TaskModel
+	if err := s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Find(&tasks).Error; err != nil {
+		taskGroup.log().Warn("failed to update task group state", zap.Error(err))
+		return
+	}
+	states := make([]TaskState, 0, len(tasks))
+	for _, task := range tasks {
+		states = append(states, task.State)
+	}
+	taskGroup.setState(taskGroupState(states))
+	taskGroup.log().Info("profiling task group stopped", zap.String("state", taskStateLabels[taskGroup.State]))
+}
@@ -0,0 +1,192 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+func TestRefreshErroredTask(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	var fetched int32
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if atomic.AddInt32(&fetched, 1) == 1 {
+			return nil, fmt.Errorf("connection reset")
+		}
+		return content, nil
+	}}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.Equal(t, TaskStateError, group.State)
+	require.Len(t, tasks, 1)
+	require.Equal(t, TaskStateError, tasks[0].State)
+
+	refreshing, err := s.refreshTask(tasks[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, tasks[0].ID, refreshing.ID)
+	s.wg.Wait()
+
+	var refreshed TaskModel
+	require.NoError(t, s.params.LocalStore.Where("id = ?", tasks[0].ID).First(&refreshed).Error)
+	require.Equal(t, TaskStateFinish, refreshed.State)
+	require.Empty(t, refreshed.Error)
+	require.Equal(t, RawDataTypeProtobuf, refreshed.RawDataType)
+	data, err := ioutil.ReadFile(refreshed.FilePath)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	var refreshedGroup TaskGroupModel
+	require.NoError(t, s.params.LocalStore.Where("id = ?", group.ID).First(&refreshedGroup).Error)
+	require.Equal(t, TaskStateFinish, refreshedGroup.State)
+
+	var count int64
+	require.NoError(t, s.params.LocalStore.Model(&TaskModel{}).Where("task_group_id = ?", group.ID).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+}
+
+func TestRefreshFailureKeepsFinishedResult(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	var fetched int32
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if atomic.AddInt32(&fetched, 1) == 1 {
+			return content, nil
+		}
+		return nil, fmt.Errorf("connection reset")
+	}}
+
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+
+	_, err := s.refreshTask(tasks[0].ID)
+	require.NoError(t, err)
+	s.wg.Wait()
+
+	var task TaskModel
+	require.NoError(t, s.params.LocalStore.Where("id = ?", tasks[0].ID).First(&task).Error)
+	expected := tasks[0]
+	expected.Attempt = 2
+	require.Equal(t, expected, task)
+	data, err := ioutil.ReadFile(task.FilePath)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+func TestRefreshIncreasesAttempt(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	release := make(chan struct{})
+	var fetched int32
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if atomic.AddInt32(&fetched, 1) == 1 {
+			return nil, fmt.Errorf("connection reset")
+		}
+		<-release
+		return content, nil
+	}}
+
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	first := tasks[0]
+	require.Equal(t, uint(1), first.Attempt)
+
+	_, err := s.refreshTask(first.ID)
+	require.NoError(t, err)
+
+	// While retrying, the progress restarts from the new StartedAt, along with a new attempt.
+	var retrying TaskModel
+	require.NoError(t, s.params.LocalStore.Where("id = ?", first.ID).First(&retrying).Error)
+	require.Equal(t, TaskStateRunning, retrying.State)
+	require.Equal(t, uint(2), retrying.Attempt)
+	require.GreaterOrEqual(t, retrying.StartedAt, first.StartedAt)
+
+	close(release)
+	s.wg.Wait()
+
+	var finished TaskModel
+	require.NoError(t, s.params.LocalStore.Where("id = ?", first.ID).First(&finished).Error)
+	require.Equal(t, TaskStateFinish, finished.State)
+	require.Equal(t, uint(2), finished.Attempt)
+	require.Equal(t, retrying.StartedAt, finished.StartedAt)
+}
+
+func TestRetryGroup(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	release := make(chan struct{})
+	var fetchedFailing, fetchedHealthy int32
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if op.ip == "127.0.0.2" {
+			if atomic.AddInt32(&fetchedFailing, 1) == 1 {
+				return nil, fmt.Errorf("no responder found")
+			}
+			<-release
+			return content, nil
+		}
+		atomic.AddInt32(&fetchedHealthy, 1)
+		return content, nil
+	}}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.Equal(t, TaskStatePartialFinish, group.State)
+	require.Len(t, tasks, 2)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, TaskStateError, tasks[1].State)
+
+	retrying, err := s.retryGroup(group.ID)
+	require.NoError(t, err)
+	require.Len(t, retrying, 1)
+	require.Equal(t, tasks[1].ID, retrying[0].ID)
+	require.Equal(t, uint(2), retrying[0].Attempt)
+
+	var runningGroup TaskGroupModel
+	require.NoError(t, s.params.LocalStore.Where("id = ?", group.ID).First(&runningGroup).Error)
+	require.Equal(t, TaskStateRunning, runningGroup.State)
+	_, err = s.retryGroup(group.ID)
+	require.True(t, errorx.IsOfType(err, ErrGroupRunning))
+
+	close(release)
+	s.wg.Wait()
+
+	var retried []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", group.ID).Order("id ASC").Find(&retried).Error)
+	require.Len(t, retried, 2)
+	require.Equal(t, tasks[0], retried[0])
+	require.Equal(t, TaskStateFinish, retried[1].State)
+	require.Empty(t, retried[1].Error)
+	require.Equal(t, int32(1), atomic.LoadInt32(&fetchedHealthy))
+	require.Equal(t, int32(2), atomic.LoadInt32(&fetchedFailing))
+
+	var retriedGroup TaskGroupModel
+	require.NoError(t, s.params.LocalStore.Where("id = ?", group.ID).First(&retriedGroup).Error)
+	require.Equal(t, TaskStateFinish, retriedGroup.State)
+
+	_, err = s.retryGroup(group.ID)
+	require.True(t, errorx.IsOfType(err, ErrIgnoredRequest))
+	_, err = s.retryGroup(group.ID + 1)
+	require.True(t, errorx.IsOfType(err, rest.ErrNotFound))
+}
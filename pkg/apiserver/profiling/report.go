@@ -0,0 +1,32 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// topReportOfTask generates the text report of the top N functions of a finished task, sorted by the flat value,
+// which is the same as the output of `go tool pprof -top`.
+func (s *Service) topReportOfTask(taskID uint, topN int) ([]byte, error) {
+	var task TaskModel
+	if err := s.params.LocalStore.Where("id = ? AND state = ?", taskID, TaskStateFinish).First(&task).Error; err != nil {
+		return nil, err
+	}
+	if task.RawDataType != RawDataTypeProtobuf {
+		return nil, ErrUnsupportedProfilingType.New("top report is not supported for %s profiles in %s format", task.ProfilingType, task.RawDataType)
+	}
+	if topN <= 0 {
+		topN = defaultTopN
+	}
+	content, err := s.results.readResult(&task)
+	if err != nil {
+		return nil, err
+	}
+	report, err := generatePprofReport(content, "-top", "-nodecount", strconv.Itoa(topN))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate top report: %v", err)
+	}
+	return report, nil
+}
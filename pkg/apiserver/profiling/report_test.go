@@ -0,0 +1,44 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopReportOfTask(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.hot": 70000000, "main.warm": 20000000, "main.cold": 10000000})
+	task := newTestFinishedTask(t, s, ProfilingTypeCPU, RawDataTypeProtobuf, content)
+
+	report, err := s.topReportOfTask(task.ID, 0)
+	require.NoError(t, err)
+	lines := strings.Split(string(report), "\n")
+	var functions []string
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strings.HasPrefix(fields[len(fields)-1], "main.") {
+			functions = append(functions, fields[len(fields)-1])
+		}
+	}
+	require.Equal(t, []string{"main.hot", "main.warm", "main.cold"}, functions)
+	require.Contains(t, string(report), "70.00%")
+
+	report, err = s.topReportOfTask(task.ID, 1)
+	require.NoError(t, err)
+	require.Contains(t, string(report), "main.hot")
+	require.NotContains(t, string(report), "main.warm")
+
+	textTask := newTestFinishedTask(t, s, ProfilingTypeGoroutine, RawDataTypeText, []byte("goroutine profile: total 1"))
+	_, err = s.topReportOfTask(textTask.ID, 0)
+	require.True(t, errorx.IsOfType(err, ErrUnsupportedProfilingType))
+
+	task.State = TaskStateError
+	require.NoError(t, s.params.LocalStore.Save(task).Error)
+	_, err = s.topReportOfTask(task.ID, 0)
+	require.Error(t, err)
+}
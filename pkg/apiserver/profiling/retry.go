@@ -0,0 +1,92 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultFetchMaxRetries   = 2
+	defaultFetchRetryBackoff = time.Second
+)
+
+// retryingFetcher fetches again with an exponential backoff when a fetch fails due to a connection error, e.g.
+// a dropped connection. Errors responded by the target, e.g. HTTP 4xx, are not retried.
+type retryingFetcher struct {
+	profileFetcher
+	maxRetries int
+	backoff    time.Duration // The backoff before the first retry, which is doubled for each following retry
+}
+
+func (f *retryingFetcher) unwrap() profileFetcher {
+	return f.profileFetcher
+}
+
+func (f *retryingFetcher) fetch(op *fetchOptions) ([]byte, error) {
+	ctx := op.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	backoff := f.backoff
+	for retries := 0; ; retries++ {
+		data, err := f.profileFetcher.fetch(op)
+		if err == nil || retries >= f.maxRetries || !isConnectionError(err) {
+			return data, err
+		}
+		logger := op.logger
+		if logger == nil {
+			logger = log.L()
+		}
+		logger.Warn("failed to fetch profile, retrying",
+			zap.String("ip", op.ip),
+			zap.Int("port", op.port),
+			zap.String("path", op.path),
+			zap.Int("retries", retries+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+		backoff *= 2
+	}
+}
+
+// isConnectionError returns whether the error is caused by the connection to the target, rather than responded
+// by the target or caused by a cancellation.
+func isConnectionError(err error) bool {
+	for err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			return true
+		}
+		// Errors of the component clients are wrapped by errorx, which does not support errors.Unwrap.
+		e, ok := err.(*errorx.Error)
+		if !ok {
+			return false
+		}
+		err = e.Cause()
+	}
+	return false
+}
+
+// retrying returns fetchers which retry fetches failed due to connection errors at most maxRetries times.
+func (fts *fetchers) retrying(maxRetries int, backoff time.Duration) *fetchers {
+	return fts.wrap(func(f profileFetcher) profileFetcher {
+		return &retryingFetcher{profileFetcher: f, maxRetries: maxRetries, backoff: backoff}
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+	"github.com/pingcap/tidb-dashboard/pkg/httpc"
+	"github.com/pingcap/tidb-dashboard/pkg/tikv"
+)
+
+func TestRetryFetch(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	cfg := &config.Config{}
+	lc := fxtest.NewLifecycle(t)
+	httpClient := httpc.NewHTTPClient(lc, cfg)
+	mockTransport := httpmock.NewMockTransport()
+	httpClient.Transport = mockTransport
+	var flakyRequests, notFoundRequests, completedDuringRetries int32
+	// The flaky TiKV drops the connection twice before responding.
+	mockTransport.RegisterResponder("GET", "http://127.0.0.1:20180/debug/pprof/profile", func(req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&flakyRequests, 1) <= 2 {
+			// The task keeps running with an incomplete progress during retries.
+			var task TaskModel
+			if err := s.params.LocalStore.Order("id DESC").First(&task).Error; err != nil ||
+				estimateProgress(&task, 1, time.Now().Unix()+10) >= 1 {
+				atomic.AddInt32(&completedDuringRetries, 1)
+			}
+			return nil, fmt.Errorf("connection reset by peer")
+		}
+		return httpmock.NewBytesResponse(http.StatusOK, content), nil
+	})
+	mockTransport.RegisterResponder("GET", "http://127.0.0.2:20180/debug/pprof/profile", func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&notFoundRequests, 1)
+		return httpmock.NewStringResponse(http.StatusNotFound, "not found"), nil
+	})
+	s.fetchers = (&fetchers{tikv: &tikvFetcher{client: tikv.NewTiKVClient(lc, httpClient, cfg)}}).retrying(2, 10*time.Millisecond)
+	core, logs := observer.New(zap.WarnLevel)
+	s.logger = zap.New(core)
+	require.NoError(t, lc.Start(context.Background()))
+	defer lc.RequireStop()
+
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, int32(3), atomic.LoadInt32(&flakyRequests))
+	require.Equal(t, int32(0), atomic.LoadInt32(&completedDuringRetries))
+	// Retries are logged with the task they are for.
+	retryLogs := logs.FilterMessage("failed to fetch profile, retrying").All()
+	require.Len(t, retryLogs, 2)
+	for _, entry := range retryLogs {
+		fields := entry.ContextMap()
+		require.EqualValues(t, tasks[0].ID, fields["task_id"])
+		require.EqualValues(t, tasks[0].TaskGroupID, fields["task_group_id"])
+	}
+
+	// Errors responded by the target are not retried.
+	tasks, _ = runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.2:20160", IP: "127.0.0.2", Port: 20180}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStateError, tasks[0].State)
+	require.Equal(t, int32(1), atomic.LoadInt32(&notFoundRequests))
+
+	// The task is failed after all retries are failed.
+	atomic.StoreInt32(&flakyRequests, -10)
+	tasks, _ = runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStateError, tasks[0].State)
+	require.Contains(t, tasks[0].Error, "connection reset by peer")
+	require.Equal(t, int32(-7), atomic.LoadInt32(&flakyRequests))
+}
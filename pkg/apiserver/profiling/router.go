@@ -6,9 +6,7 @@ import (
 	"archive/zip"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strconv"
 	"time"
@@ -21,19 +19,50 @@ import (
 	"github.com/pingcap/tidb-dashboard/util/rest"
 )
 
+// groupDownloadAutoDeleteAction is the action of tokens to download a task group and delete it afterwards.
+const groupDownloadAutoDeleteAction = "group_download_auto_delete"
+
 // Register register the handlers to the service.
 func RegisterRouter(r *gin.RouterGroup, auth *user.AuthService, s *Service) {
 	endpoint := r.Group("/profiling")
+	endpoint.GET("/targets", auth.MWAuthRequired(), s.getTargets)
+	endpoint.GET("/targets/resolve", auth.MWAuthRequired(), s.getResolvedTargets)
+	endpoint.GET("/targets/capabilities", auth.MWAuthRequired(), s.getKindCapabilities)
+	endpoint.POST("/targets/ping", auth.MWAuthRequired(), s.handlePingTargets)
 	endpoint.GET("/group/list", auth.MWAuthRequired(), s.getGroupList)
+	endpoint.GET("/group/paged_list", auth.MWAuthRequired(), s.getGroupPagedList)
 	endpoint.POST("/group/start", auth.MWAuthRequired(), s.handleStartGroup)
+	endpoint.POST("/group/estimate", auth.MWAuthRequired(), s.handleEstimateGroup)
 	endpoint.GET("/group/detail/:groupId", auth.MWAuthRequired(), s.getGroupDetail)
 	endpoint.POST("/group/cancel/:groupId", auth.MWAuthRequired(), s.handleCancelGroup)
-	endpoint.DELETE("/group/delete/:groupId", auth.MWAuthRequired(), s.deleteGroup)
+	endpoint.DELETE("/group/delete/:groupId", auth.MWAuthRequired(), auth.MWRequireWritePriv(), s.handleDeleteGroup)
+	endpoint.POST("/group/retry/:groupId", auth.MWAuthRequired(), s.handleRetryGroup)
+	endpoint.POST("/group/clone/:groupId", auth.MWAuthRequired(), s.handleCloneGroup)
+	endpoint.GET("/group/top/:groupId", auth.MWAuthRequired(), s.getGroupTop)
+	endpoint.GET("/group/events/:groupId", auth.MWAuthRequired(), s.getGroupEvents)
+	endpoint.GET("/group/comments/:groupId", auth.MWAuthRequired(), s.getGroupComments)
+	endpoint.POST("/group/comments/:groupId", auth.MWAuthRequired(), s.handleAddGroupComment)
 
 	endpoint.GET("/action_token", auth.MWAuthRequired(), s.getActionToken)
 	endpoint.GET("/group/download", s.downloadGroup)
+	endpoint.GET("/group/merged_download", s.downloadGroupMerged)
 	endpoint.GET("/single/download", s.downloadSingle)
 	endpoint.GET("/single/view", s.viewSingle)
+	endpoint.POST("/single/refresh/:taskId", auth.MWAuthRequired(), s.handleRefreshSingle)
+	endpoint.GET("/single/preview/:taskId", auth.MWAuthRequired(), s.getSinglePreview)
+	endpoint.POST("/single/data", auth.MWAuthRequired(), s.handleGetTasksData)
+
+	endpoint.POST("/campaign/start", auth.MWAuthRequired(), s.handleStartCampaign)
+	endpoint.GET("/campaign/detail/:campaignId", auth.MWAuthRequired(), s.getCampaignDetail)
+	endpoint.GET("/campaign/download", s.downloadCampaign)
+
+	endpoint.POST("/schedule/create", auth.MWAuthRequired(), auth.MWRequireWritePriv(), s.handleCreateSchedule)
+	endpoint.GET("/schedule/list", auth.MWAuthRequired(), s.getScheduleList)
+	endpoint.DELETE("/schedule/delete/:scheduleId", auth.MWAuthRequired(), auth.MWRequireWritePriv(), s.handleDeleteSchedule)
+
+	endpoint.GET("/baseline/list", auth.MWAuthRequired(), s.getBaselineList)
+	endpoint.POST("/baseline/register", auth.MWAuthRequired(), auth.MWRequireWritePriv(), s.handleRegisterBaseline)
+	endpoint.GET("/baseline/compare", auth.MWAuthRequired(), s.handleCompareToBaseline)
 
 	endpoint.GET("/config", auth.MWAuthRequired(), s.getDynamicConfig)
 	endpoint.PUT("/config", auth.MWAuthRequired(), auth.MWRequireWritePriv(), s.setDynamicConfig)
@@ -55,18 +84,28 @@ func (s *Service) handleStartGroup(c *gin.Context) {
 		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
 		return
 	}
-	if len(req.Targets) == 0 {
+	if len(req.Targets) == 0 && len(req.TiKVStoreIDs) == 0 {
 		rest.Error(c, rest.ErrBadRequest.New("Expect at least 1 target"))
 		return
 	}
 
-	if req.DurationSecs == 0 {
-		req.DurationSecs = config.DefaultProfilingAutoCollectionDurationSecs
+	if err := checkProfilingTypes(&req); err != nil {
+		rest.Error(c, err)
+		return
 	}
-	if req.DurationSecs > config.MaxProfilingAutoCollectionDurationSecs {
-		req.DurationSecs = config.MaxProfilingAutoCollectionDurationSecs
+	if err := s.validateDuration(&req); err != nil {
+		rest.Error(c, err)
+		return
 	}
+	s.respondStartRequest(c, req)
+}
 
+// respondStartRequest starts a task group by the loop handling profiling requests one at a time, and responds
+// with the started task group.
+func (s *Service) respondStartRequest(c *gin.Context, req StartRequest) {
+	if sessionUser := utils.GetSession(c); sessionUser != nil {
+		req.triggeredBy = sessionUser.DisplayName
+	}
 	session := &StartRequestSession{
 		req: req,
 		ch:  make(chan struct{}, 1),
@@ -77,23 +116,160 @@ func (s *Service) handleStartGroup(c *gin.Context) {
 		if session.err != nil {
 			rest.Error(c, session.err)
 		} else {
-			c.JSON(http.StatusOK, session.taskGroup.TaskGroupModel)
+			c.JSON(http.StatusOK, session.taskGroup.snapshot())
 		}
 	case <-time.After(Timeout):
 		rest.Error(c, ErrTimeout.NewWithNoMessage())
 	}
 }
 
-// @ID getProfilingGroups
-// @Summary List all profiling groups
-// @Description List all profiling groups
+// @ID estimateProfilingGroup
+// @Summary Estimate the size of a profiling group
+// @Description Estimate the total size of the profiles of a profiling request from recently finished tasks, without starting it
+// @Param req body StartRequest true "profiling request"
+// @Security JwtAuth
+// @Success 200 {object} EstimateGroupResponse
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/group/estimate [post]
+func (s *Service) handleEstimateGroup(c *gin.Context) {
+	var req StartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	if err := checkProfilingTypes(&req); err != nil {
+		rest.Error(c, err)
+		return
+	}
+	if err := s.validateDuration(&req); err != nil {
+		rest.Error(c, err)
+		return
+	}
+	resp, err := s.estimateGroup(&req)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary List profiling targets
+// @Description List all components in the cluster which can be profiled
+// @Param q query ListTargetsRequest false "Query"
+// @Security JwtAuth
+// @Success 200 {array} model.RequestTargetNode
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/targets [get]
+func (s *Service) getTargets(c *gin.Context) {
+	var req ListTargetsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	targets, err := s.listTargets(c.Request.Context(), req)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, targets)
+}
+
+// @Summary Resolve profiling targets
+// @Description Resolve all current components of the kinds to profiling targets, which can be used to start profiling
+// @Param q query ResolveTargetsRequest true "Query"
+// @Security JwtAuth
+// @Success 200 {array} model.RequestTargetNode
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/targets/resolve [get]
+func (s *Service) getResolvedTargets(c *gin.Context) {
+	var req ResolveTargetsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	targets, err := s.resolveTargets(c.Request.Context(), req)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, targets)
+}
+
+// @Summary Get profiling capabilities
+// @Description Get the profiling types supported by each kind of component
+// @Security JwtAuth
+// @Success 200 {object} KindCapabilitiesResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Router /profiling/targets/capabilities [get]
+func (s *Service) getKindCapabilities(c *gin.Context) {
+	c.JSON(http.StatusOK, kindCapabilities())
+}
+
+// @ID pingProfilingTargets
+// @Summary Check whether profiling targets are reachable
+// @Description Check whether the status API of each target is reachable before profiling
+// @Param req body PingTargetsRequest true "ping request"
+// @Security JwtAuth
+// @Success 200 {array} TargetReachability
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/targets/ping [post]
+func (s *Service) handlePingTargets(c *gin.Context) {
+	var req PingTargetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	if len(req.Targets) == 0 {
+		rest.Error(c, rest.ErrBadRequest.New("Expect at least 1 target"))
+		return
+	}
+	results, err := s.pingTargets(c.Request.Context(), &req)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary List profiling groups
+// @Description List the latest profiling groups, at most 1000 groups are returned. Use the paged list to get more.
 // @Security JwtAuth
 // @Success 200 {array} TaskGroupModel
 // @Failure 401 {object} rest.ErrorResponse
 // @Router /profiling/group/list [get]
 func (s *Service) getGroupList(c *gin.Context) {
-	var resp []TaskGroupModel
-	err := s.params.LocalStore.Order("id DESC").Find(&resp).Error
+	resp, err := s.listGroups(ListGroupsRequest{})
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp.Groups)
+}
+
+// @ID getProfilingGroupsPaged
+// @Summary List profiling groups by page
+// @Description List profiling groups ordered by ID descending, with the total number of groups
+// @Param q query ListGroupsRequest true "Query"
+// @Security JwtAuth
+// @Success 200 {object} ListGroupsResponse
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Router /profiling/group/paged_list [get]
+func (s *Service) getGroupPagedList(c *gin.Context) {
+	var req ListGroupsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	resp, err := s.listGroups(req)
 	if err != nil {
 		rest.Error(c, err)
 		return
@@ -104,13 +280,24 @@ func (s *Service) getGroupList(c *gin.Context) {
 type GroupDetailResponse struct {
 	ServerTime int64          `json:"server_time"`
 	TaskGroup  TaskGroupModel `json:"task_group_status"`
-	Tasks      []TaskModel    `json:"tasks_status"`
+	// Tasks of the task group, or nil if the tasks are not requested. Fields derived from the tasks are filled
+	// in either case.
+	Tasks    []TaskModel             `json:"tasks_status"`
+	Comments []TaskGroupCommentModel `json:"comments"`
+	// Profiling types that produced at least one result, which may be a subset of the requested types
+	// since some components do not support all profiling types.
+	ObservedProfilingTypes TaskProfilingTypeList `json:"observed_profiling_types"`
+	// Number of failed tasks by their distinct error messages, so that the failures of a partially finished
+	// task group can be understood without checking every task.
+	ErrorSummary map[string]int `json:"error_summary"`
 }
 
 // @ID getProfilingGroupDetail
 // @Summary List all tasks with a given group ID
 // @Description List all profiling tasks with a given group ID
 // @Param groupId path string true "group ID"
+// @Param include_tasks query bool false "whether to include the tasks, true by default"
+// @Param states query []int false "only include tasks in these states, all tasks by default"
 // @Security JwtAuth
 // @Success 200 {object} GroupDetailResponse
 // @Failure 400 {object} rest.ErrorResponse
@@ -122,35 +309,168 @@ func (s *Service) getGroupDetail(c *gin.Context) {
 		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
 		return
 	}
-	var taskGroup TaskGroupModel
-	err = s.params.LocalStore.Where("id = ?", taskGroupID).Find(&taskGroup).Error
+	includeTasks := c.Query("include_tasks") != "false"
+	var states []TaskState
+	for _, str := range c.QueryArray("states") {
+		state, err := strconv.Atoi(str)
+		if err != nil {
+			rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+			return
+		}
+		states = append(states, TaskState(state))
+	}
+	resp, err := s.groupDetail(uint(taskGroupID), includeTasks, states)
 	if err != nil {
 		rest.Error(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, resp)
+}
 
-	var tasks []TaskModel
-	err = s.params.LocalStore.Where("task_group_id = ?", taskGroupID).Find(&tasks).Error
+// @ID getProfilingGroupEvents
+// @Summary Subscribe to the progress of a task group
+// @Description Send the progress of a task group as server-sent events whenever it is changed, until the task group is stopped
+// @Produce text/event-stream
+// @Param groupId path string true "group ID"
+// @Security JwtAuth
+// @Success 200 {object} GroupProgressEvent
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
+// @Router /profiling/group/events/{groupId} [get]
+func (s *Service) getGroupEvents(c *gin.Context) {
+	taskGroupID, err := strconv.Atoi(c.Param("groupId"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	events, unsubscribe, err := s.subscribeGroup(uint(taskGroupID))
 	if err != nil {
 		rest.Error(c, err)
 		return
 	}
-
-	c.JSON(http.StatusOK, GroupDetailResponse{
-		ServerTime: time.Now().Unix(), // Used to estimate task progress
-		TaskGroup:  taskGroup,
-		Tasks:      tasks,
+	defer unsubscribe()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent("progress", event)
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
 	})
 }
 
+// @ID getProfilingGroupTop
+// @Summary Get top functions of a task group
+// @Description Aggregate all finished profiles of a profiling type in a task group and list the top functions by flat value
+// @Param groupId path string true "group ID"
+// @Param profiling_type query string false "profiling type, cpu by default"
+// @Param limit query int false "number of functions to return"
+// @Security JwtAuth
+// @Success 200 {object} GroupTopResponse
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
+// @Router /profiling/group/top/{groupId} [get]
+func (s *Service) getGroupTop(c *gin.Context) {
+	taskGroupID, err := strconv.Atoi(c.Param("groupId"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	profilingType := TaskProfilingType(c.DefaultQuery("profiling_type", string(ProfilingTypeCPU)))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTopN)))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	resp, err := s.topOfGroup(uint(taskGroupID), profilingType, limit)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+type AddGroupCommentRequest struct {
+	Content string `json:"content"`
+}
+
+// @ID addProfilingGroupComment
+// @Summary Add a comment to a task group
+// @Description Add a comment to a profiling task group. Comments cannot be modified once added.
+// @Param groupId path string true "group ID"
+// @Param req body AddGroupCommentRequest true "comment request"
+// @Security JwtAuth
+// @Success 200 {object} TaskGroupCommentModel
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
+// @Router /profiling/group/comments/{groupId} [post]
+func (s *Service) handleAddGroupComment(c *gin.Context) {
+	taskGroupID, err := strconv.Atoi(c.Param("groupId"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	var req AddGroupCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	if req.Content == "" {
+		rest.Error(c, rest.ErrBadRequest.New("Expect a non-empty comment"))
+		return
+	}
+	author := ""
+	if session := utils.GetSession(c); session != nil {
+		author = session.DisplayName
+	}
+	comment, err := s.addGroupComment(uint(taskGroupID), author, req.Content)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, comment)
+}
+
+// @ID getProfilingGroupComments
+// @Summary List comments of a task group
+// @Description List comments of a profiling task group in the order they are added
+// @Param groupId path string true "group ID"
+// @Security JwtAuth
+// @Success 200 {array} TaskGroupCommentModel
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Router /profiling/group/comments/{groupId} [get]
+func (s *Service) getGroupComments(c *gin.Context) {
+	taskGroupID, err := strconv.Atoi(c.Param("groupId"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	comments, err := s.listGroupComments(uint(taskGroupID))
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, comments)
+}
+
 // @ID cancelProfilingGroup
 // @Summary Cancel all tasks with a given group ID
-// @Description Cancel all profling tasks with a given group ID
+// @Description Cancel all running profling tasks with a given group ID. Results of finished tasks are kept.
 // @Param groupId path string true "group ID"
 // @Security JwtAuth
 // @Success 200 {object} rest.EmptyResponse
 // @Failure 400 {object} rest.ErrorResponse
 // @Failure 401 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
 // @Router /profiling/group/cancel/{groupId} [post]
 func (s *Service) handleCancelGroup(c *gin.Context) {
 	taskGroupID, err := strconv.Atoi(c.Param("groupId"))
@@ -175,11 +495,19 @@ func (s *Service) handleCancelGroup(c *gin.Context) {
 // @Success 200 {string} string
 // @Failure 400 {object} rest.ErrorResponse
 // @Failure 401 {object} rest.ErrorResponse
+// @Failure 403 {object} rest.ErrorResponse
 // @Failure 500 {object} rest.ErrorResponse
 // @Router /profiling/action_token [get]
 func (s *Service) getActionToken(c *gin.Context) {
 	id := c.Query("id")
-	action := c.Query("action") // group_download, single_download, single_view
+	action := c.Query("action") // group_download, group_download_auto_delete, single_download, single_view, campaign_download
+	if action == groupDownloadAutoDeleteAction {
+		// The task group is deleted after it is downloaded, which requires the same privilege as deleting it.
+		if sessionUser := utils.GetSession(c); sessionUser == nil || !sessionUser.IsWriteable {
+			rest.Error(c, rest.ErrForbidden.NewWithNoMessage())
+			return
+		}
+	}
 	token, err := utils.NewJWTString("profiling/"+action, id)
 	if err != nil {
 		rest.Error(c, err)
@@ -193,6 +521,9 @@ func (s *Service) getActionToken(c *gin.Context) {
 // @Description Download all finished profiling results of a task group
 // @Produce application/x-gzip
 // @Param token query string true "download token"
+// @Param auto_delete query boolean false "delete the task group after the results are fully exported, which is not allowed with filters. The token must be issued for group_download_auto_delete"
+// @Param q query ExportFilter false "only download results matching the filter"
+// @Param merge_cpu query boolean false "also download the finished CPU profiles merged into a single profile"
 // @Security JwtAuth
 // @Failure 400 {object} rest.ErrorResponse
 // @Failure 401 {object} rest.ErrorResponse
@@ -200,7 +531,13 @@ func (s *Service) getActionToken(c *gin.Context) {
 // @Router /profiling/group/download [get]
 func (s *Service) downloadGroup(c *gin.Context) {
 	token := c.Query("token")
-	str, err := utils.ParseJWTString("profiling/group_download", token)
+	autoDelete := c.Query("auto_delete") == "true"
+	action := "group_download"
+	if autoDelete {
+		// Only tokens issued to users with the write privilege allow deleting the task group.
+		action = groupDownloadAutoDeleteAction
+	}
+	str, err := utils.ParseJWTString("profiling/"+action, token)
 	if err != nil {
 		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
 		return
@@ -210,37 +547,76 @@ func (s *Service) downloadGroup(c *gin.Context) {
 		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
 		return
 	}
-	var tasks []TaskModel
-	err = s.params.LocalStore.Where("task_group_id = ? AND state = ?", taskGroupID, TaskStateFinish).Find(&tasks).Error
+	var filter ExportFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	if autoDelete && !filter.isEmpty() {
+		// Results filtered out would be deleted without being exported.
+		rest.Error(c, rest.ErrBadRequest.New("auto_delete cannot be used with filters"))
+		return
+	}
+	files, err := s.groupExportFiles(uint(taskGroupID), filter)
 	if err != nil {
 		rest.Error(c, err)
 		return
 	}
-
-	filePathes := make([]string, len(tasks))
-	for i, task := range tasks {
-		filePathes[i] = task.FilePath
+	if c.Query("merge_cpu") == "true" {
+		merged, err := s.groupMergedCPUFile(uint(taskGroupID), filter)
+		if err != nil {
+			rest.Error(c, err)
+			return
+		}
+		if merged != nil {
+			files = append(files, *merged)
+		}
 	}
 
 	fileName := fmt.Sprintf("profiling_%s.zip", time.Now().Format("2006-01-02_15-04-05"))
 	c.Writer.Header().Set("Content-type", "application/octet-stream")
 	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
-	zw := zip.NewWriter(c.Writer)
-	defer func() {
-		_ = zw.Close()
-	}()
-
-	err = writeZipFromFiles(zw, filePathes, true)
+	err = s.exportGroup(c.Writer, uint(taskGroupID), files, autoDelete)
 	if err != nil {
 		rest.Error(c, err)
 		return
 	}
+}
 
-	err = zipREADME(zw)
+// @ID downloadProfilingGroupMerged
+// @Summary Download the merged result of a task group
+// @Description Download the finished profiling results of a profiling type across all targets of a task group merged into a single profile. Samples are labeled with their source instance and component.
+// @Produce application/x-gzip
+// @Param token query string true "download token of the task group"
+// @Param profiling_type query string false "profiling type, cpu by default"
+// @Security JwtAuth
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/group/merged_download [get]
+func (s *Service) downloadGroupMerged(c *gin.Context) {
+	token := c.Query("token")
+	str, err := utils.ParseJWTString("profiling/group_download", token)
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	taskGroupID, err := strconv.Atoi(str)
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	profilingType := TaskProfilingType(c.DefaultQuery("profiling_type", string(ProfilingTypeCPU)))
+	data, err := s.mergeGroup(uint(taskGroupID), profilingType)
 	if err != nil {
 		rest.Error(c, err)
 		return
 	}
+
+	fileName := fmt.Sprintf("profiling_group_%d_merged_%s.proto", taskGroupID, profilingType)
+	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	c.Data(http.StatusOK, "application/octet-stream", data)
 }
 
 // @ID downloadProfilingSingle
@@ -272,6 +648,12 @@ func (s *Service) downloadSingle(c *gin.Context) {
 		rest.Error(c, err)
 		return
 	}
+	var taskGroup TaskGroupModel
+	err = s.params.LocalStore.Where("id = ?", task.TaskGroupID).First(&taskGroup).Error
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
 
 	fileName := fmt.Sprintf("profiling_%s.zip", time.Now().Format("2006-01-02_15-04-05"))
 	c.Writer.Header().Set("Content-type", "application/octet-stream")
@@ -281,7 +663,7 @@ func (s *Service) downloadSingle(c *gin.Context) {
 		_ = zw.Close()
 	}()
 
-	err = writeZipFromFiles(zw, []string{task.FilePath}, true)
+	err = writeZipFromFiles(zw, s.results, []exportFile{newExportFile(taskGroup.StartedAt, task)}, true)
 	if err != nil {
 		rest.Error(c, err)
 		return
@@ -294,9 +676,40 @@ func (s *Service) downloadSingle(c *gin.Context) {
 	}
 }
 
-func writeZipFromFiles(zw *zip.Writer, files []string, compress bool) error {
+// exportFileTimeLayout is the layout of the capture time in exported file names.
+const exportFileTimeLayout = "2006-01-02_15-04-05"
+
+type exportFile struct {
+	name       string // The file name in the exported archive
+	path       string
+	content    []byte // The content computed on read, e.g. a merged profile, which is exported instead of the result file
+	storageKey string // The key of the file in the result store, empty for local files
+	keyID      string // The encryption key ID of the file, empty for plaintext files
+	// The codec which the file is compressed with, or empty if it is not compressed
+	compressionCodec string
+}
+
+func newExportFile(taskGroupStartedAt int64, task TaskModel) exportFile {
+	return exportFile{
+		name:             exportFileName(taskGroupStartedAt, task),
+		path:             task.FilePath,
+		storageKey:       task.StorageKey,
+		keyID:            task.EncryptionKeyID,
+		compressionCodec: task.compressionCodec(),
+	}
+}
+
+// exportFileName returns the file name of an exported profiling result, in the format of
+// `{capture time}_{profiling type}_{kind}_{address}.{ext}`. The capture time of the task group is included,
+// so that files exported from different task groups never overwrite each other when extracted together.
+func exportFileName(taskGroupStartedAt int64, task TaskModel) string {
+	capturedAt := time.Unix(taskGroupStartedAt, 0).UTC().Format(exportFileTimeLayout)
+	return fmt.Sprintf("%s_%s_%s%s", capturedAt, task.ProfilingType, task.Target.FileName(), filepath.Ext(task.FilePath))
+}
+
+func writeZipFromFiles(zw *zip.Writer, results *resultFiles, files []exportFile, compress bool) error {
 	for _, file := range files {
-		err := writeZipFromFile(zw, file, compress)
+		err := writeZipFromFile(zw, results, file, compress)
 		if err != nil {
 			return err
 		}
@@ -304,8 +717,8 @@ func writeZipFromFiles(zw *zip.Writer, files []string, compress bool) error {
 	return nil
 }
 
-func writeZipFromFile(zw *zip.Writer, file string, compress bool) error {
-	f, err := os.Open(filepath.Clean(file))
+func writeZipFromFile(zw *zip.Writer, results *resultFiles, file exportFile, compress bool) error {
+	f, err := results.openResult(file)
 	if err != nil {
 		return err
 	}
@@ -313,17 +726,12 @@ func writeZipFromFile(zw *zip.Writer, file string, compress bool) error {
 		_ = f.Close()
 	}()
 
-	fileInfo, err := f.Stat()
-	if err != nil {
-		return err
-	}
-
 	zipMethod := zip.Store // no compress
 	if compress {
 		zipMethod = zip.Deflate // compress
 	}
 	zipFile, err := zw.CreateHeader(&zip.FileHeader{
-		Name:     fileInfo.Name(),
+		Name:     file.name,
 		Method:   zipMethod,
 		Modified: time.Now(),
 	})
@@ -343,7 +751,11 @@ func zipREADME(zw *zip.Writer) error {
 	const downloadREADME = `
 To review the CPU profiling or heap profiling result interactively:
 
-$ go tool pprof --http=0.0.0.0:1234 cpu_xxx.proto
+$ go tool pprof --http=0.0.0.0:1234 xxx_cpu_xxx.proto
+
+To review the execution trace result:
+
+$ go tool trace xxx_trace_xxx.trace
 `
 	zipFile, err := zw.CreateHeader(&zip.FileHeader{
 		Name:     "README.md",
@@ -367,13 +779,26 @@ const (
 	ViewOutputTypeProtobuf ViewOutputType = "protobuf"
 	ViewOutputTypeGraph    ViewOutputType = "graph"
 	ViewOutputTypeText     ViewOutputType = "text"
+	// ViewOutputTypeFlameGraph renders a protobuf profile as an SVG flame graph.
+	ViewOutputTypeFlameGraph ViewOutputType = "flamegraph"
+	// ViewOutputTypeTop reports the top functions of a protobuf profile as text, like `go tool pprof -top`.
+	ViewOutputTypeTop ViewOutputType = "top"
+	// ViewOutputTypeRaw outputs a byte range of the stored result as it is, so that large results can be
+	// downloaded in parts and resumed over flaky connections. The bytes received so far are output for a running
+	// task whose target provides partial results, along with the X-Partial-Result header.
+	ViewOutputTypeRaw ViewOutputType = "raw"
 )
 
 // @ID viewProfilingSingle
 // @Summary View the result of a task
-// @Description View the finished profiling result of a task
+// @Description View the finished profiling result of a task, or the bytes received so far by a running task with the raw output type
 // @Produce html
 // @Param token query string true "download token"
+// @Param output_type query string false "output type, e.g. graph, flamegraph, top, raw, protobuf or text"
+// @Param top_n query int false "number of functions in the top report, 30 by default"
+// @Param offset query int false "offset of the raw output in bytes"
+// @Param length query int false "maximum length of the raw output in bytes, all bytes from the offset by default"
+// @Param decompress query bool false "output a gzipped protobuf profile as plain protobuf"
 // @Security JwtAuth
 // @Failure 400 {object} rest.ErrorResponse
 // @Failure 401 {object} rest.ErrorResponse
@@ -392,33 +817,97 @@ func (s *Service) viewSingle(c *gin.Context) {
 		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
 		return
 	}
-	task := TaskModel{}
-	err = s.params.LocalStore.Where("id = ? AND state = ?", taskID, TaskStateFinish).First(&task).Error
-	if err != nil {
-		rest.Error(c, err)
+	if outputType == string(ViewOutputTypeFlameGraph) {
+		svgContent, err := s.flameGraphOfTask(uint(taskID))
+		if err != nil {
+			rest.Error(c, err)
+			return
+		}
+		c.Data(http.StatusOK, "image/svg+xml", svgContent)
 		return
 	}
-
-	content, err := ioutil.ReadFile(task.FilePath)
-	if err != nil {
-		rest.Error(c, err)
+	if outputType == string(ViewOutputTypeTop) {
+		topN, err := strconv.Atoi(c.DefaultQuery("top_n", "0"))
+		if err != nil {
+			rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+			return
+		}
+		report, err := s.topReportOfTask(uint(taskID), topN)
+		if err != nil {
+			rest.Error(c, err)
+			return
+		}
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", report)
 		return
 	}
 
-	// set default content-type for legacy profiling content.
-	contentType := "image/svg+xml"
-
-	if task.RawDataType == RawDataTypeProtobuf {
-		switch outputType {
-		case string(ViewOutputTypeGraph):
-			svgContent, err := convertProtobufToSVG(content, task)
-			if err != nil {
+	if outputType == string(ViewOutputTypeRaw) {
+		offset, err := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+		if err != nil {
+			rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+			return
+		}
+		length, err := strconv.ParseInt(c.DefaultQuery("length", "0"), 10, 64)
+		if err != nil {
+			rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+			return
+		}
+		content, total, partial, err := s.resultRange(uint(taskID), offset, length)
+		if err != nil {
+			rest.Error(c, err)
+			return
+		}
+		if partial {
+			// The total size of a partial result is not known until the task is finished.
+			c.Header("X-Partial-Result", "true")
+		}
+		if len(content) == 0 {
+			c.Data(http.StatusOK, "application/octet-stream", content)
+			return
+		}
+		if partial {
+			c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/*", offset, offset+int64(len(content))-1))
+		} else {
+			c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(content))-1, total))
+		}
+		c.Data(http.StatusPartialContent, "application/octet-stream", content)
+		return
+	}
+
+	task := TaskModel{}
+	err = s.params.LocalStore.Where("id = ? AND state = ?", taskID, TaskStateFinish).First(&task).Error
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+
+	content, err := s.results.readResult(&task)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+
+	// set default content-type for legacy profiling content.
+	contentType := "image/svg+xml"
+
+	if task.RawDataType == RawDataTypeProtobuf {
+		switch outputType {
+		case string(ViewOutputTypeGraph):
+			svgContent, err := convertProtobufToSVG(content, task)
+			if err != nil {
 				rest.Error(c, err)
 				return
 			}
 			content = svgContent
 			contentType = "image/svg+xml"
 		case string(ViewOutputTypeProtobuf):
+			if c.Query("decompress") == "true" {
+				content, err = decompressProfile(content)
+				if err != nil {
+					rest.Error(c, err)
+					return
+				}
+			}
 			contentType = "application/protobuf"
 		default:
 			// Will not handle converting protobuf to other formats except flamegraph and graph
@@ -427,49 +916,480 @@ func (s *Service) viewSingle(c *gin.Context) {
 		}
 	} else if task.RawDataType == RawDataTypeText {
 		switch outputType {
-		case string(ViewOutputTypeText):
-			contentType = "text/plain"
+		case "", string(ViewOutputTypeText):
+			contentType = "text/plain; charset=utf-8"
 		default:
 			// Will not handle converting text to other formats
 			rest.Error(c, rest.ErrBadRequest.New("Cannot output text as %s", outputType))
 			return
 		}
+	} else if task.RawDataType == RawDataTypeTrace {
+		// Execution traces can only be opened by `go tool trace` after being downloaded
+		rest.Error(c, rest.ErrBadRequest.New("Cannot view trace, download it and open it with `go tool trace`"))
+		return
+	} else if task.RawDataType == RawDataTypeRaw {
+		rest.Error(c, rest.ErrBadRequest.New("Cannot view the result of a custom profile, download it instead"))
+		return
 	}
 	c.Data(http.StatusOK, contentType, content)
 }
 
 // @ID deleteProfilingGroup
 // @Summary Delete all tasks with a given group ID
-// @Description Delete all finished profiling tasks with a given group ID
+// @Description Delete all profiling tasks and results with a given group ID. A running group must be cancelled first. Baselines registered from its results are deleted with it.
 // @Param groupId path string true "group ID"
 // @Security JwtAuth
 // @Success 200 {object} rest.EmptyResponse
 // @Failure 400 {object} rest.ErrorResponse
 // @Failure 401 {object} rest.ErrorResponse
+// @Failure 403 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
 // @Failure 500 {object} rest.ErrorResponse
 // @Router /profiling/group/delete/{groupId} [delete]
-func (s *Service) deleteGroup(c *gin.Context) {
+func (s *Service) handleDeleteGroup(c *gin.Context) {
 	taskGroupID, err := strconv.Atoi(c.Param("groupId"))
 	if err != nil {
 		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
 		return
 	}
-	if err := s.cancelGroup(uint(taskGroupID)); err != nil {
+	if err := s.deleteGroup(uint(taskGroupID)); err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rest.EmptyResponse{})
+}
+
+// @ID refreshProfilingSingle
+// @Summary Profile a single task again
+// @Description Profile the target of a stopped task again and replace its result in place. The task ID does not change. A result registered as a baseline cannot be refreshed.
+// @Param taskId path string true "task ID"
+// @Security JwtAuth
+// @Success 200 {object} TaskModel
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/single/refresh/{taskId} [post]
+func (s *Service) handleRefreshSingle(c *gin.Context) {
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	task, err := s.refreshTask(uint(taskID))
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// @ID getProfilingSinglePreview
+// @Summary Preview a single profiling result
+// @Description Get a lightweight summary of a finished task, e.g. the top functions of a CPU profile
+// @Param taskId path string true "task ID"
+// @Security JwtAuth
+// @Success 200 {object} ProfilePreview
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/single/preview/{taskId} [get]
+func (s *Service) getSinglePreview(c *gin.Context) {
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	preview, err := s.profilePreview(uint(taskID))
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// @ID getProfilingTasksData
+// @Summary Get the results of tasks
+// @Description Get multiple tasks with their profiling results in the requested order. Tasks which are not finished are returned without data.
+// @Param req body TasksDataRequest true "task IDs"
+// @Security JwtAuth
+// @Success 200 {array} TaskWithData
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/single/data [post]
+func (s *Service) handleGetTasksData(c *gin.Context) {
+	var req TasksDataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	tasks, err := s.tasksData(req)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+// @ID retryProfilingGroup
+// @Summary Profile failed tasks of a group again
+// @Description Profile the targets of failed tasks in a stopped group again. Finished tasks are kept.
+// @Param groupId path string true "group ID"
+// @Security JwtAuth
+// @Success 200 {array} TaskModel
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/group/retry/{groupId} [post]
+func (s *Service) handleRetryGroup(c *gin.Context) {
+	taskGroupID, err := strconv.Atoi(c.Param("groupId"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	tasks, err := s.retryGroup(uint(taskGroupID))
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+// @ID cloneProfilingGroup
+// @Summary Profile the targets of a group again
+// @Description Start a new group profiling the same targets with the same profiling types as a stopped group. The original group is kept.
+// @Param groupId path string true "group ID"
+// @Param req body CloneGroupRequest true "clone request"
+// @Security JwtAuth
+// @Success 200 {object} TaskGroupModel "task group"
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/group/clone/{groupId} [post]
+func (s *Service) handleCloneGroup(c *gin.Context) {
+	taskGroupID, err := strconv.Atoi(c.Param("groupId"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	var req CloneGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	startReq, err := s.cloneGroupRequest(uint(taskGroupID), req)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	if err := s.validateDuration(startReq); err != nil {
+		rest.Error(c, err)
+		return
+	}
+	s.respondStartRequest(c, *startReq)
+}
+
+// @ID startProfilingCampaign
+// @Summary Start a profiling campaign
+// @Description Run the same profiling request several times with a gap. Each iteration is a task group.
+// @Param req body StartCampaignRequest true "profiling campaign request"
+// @Security JwtAuth
+// @Success 200 {object} CampaignModel
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/campaign/start [post]
+func (s *Service) handleStartCampaign(c *gin.Context) {
+	var req StartCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	if len(req.Targets) == 0 && len(req.TiKVStoreIDs) == 0 {
+		rest.Error(c, rest.ErrBadRequest.New("Expect at least 1 target"))
+		return
+	}
+	if req.Iterations == 0 {
+		rest.Error(c, rest.ErrBadRequest.New("Expect at least 1 iteration"))
+		return
+	}
+
+	if err := checkProfilingTypes(&req.StartRequest); err != nil {
+		rest.Error(c, err)
+		return
+	}
+	if err := s.validateDuration(&req.StartRequest); err != nil {
 		rest.Error(c, err)
 		return
 	}
 
-	if err = s.params.LocalStore.Where("task_group_id = ?", taskGroupID).Delete(&TaskModel{}).Error; err != nil {
+	campaign, err := s.startCampaign(s.lifecycleCtx, &req)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, campaign)
+}
+
+type CampaignDetailResponse struct {
+	Campaign   CampaignModel    `json:"campaign"`
+	TaskGroups []TaskGroupModel `json:"task_groups"`
+}
+
+// @ID getProfilingCampaignDetail
+// @Summary Get a profiling campaign with all of its task groups
+// @Param campaignId path string true "campaign ID"
+// @Security JwtAuth
+// @Success 200 {object} CampaignDetailResponse
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/campaign/detail/{campaignId} [get]
+func (s *Service) getCampaignDetail(c *gin.Context) {
+	campaignID, err := strconv.Atoi(c.Param("campaignId"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	var resp CampaignDetailResponse
+	if err := s.params.LocalStore.Where("id = ?", campaignID).First(&resp.Campaign).Error; err != nil {
+		rest.Error(c, err)
+		return
+	}
+	if err := s.params.LocalStore.Where("campaign_id = ?", campaignID).Order("id ASC").Find(&resp.TaskGroups).Error; err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @ID downloadProfilingCampaign
+// @Summary Download the merged results of a campaign
+// @Description Download the profiling results of each target merged across all iterations of a campaign
+// @Produce application/x-gzip
+// @Param token query string true "download token"
+// @Param profiling_type query string false "profiling type, cpu by default"
+// @Security JwtAuth
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/campaign/download [get]
+func (s *Service) downloadCampaign(c *gin.Context) {
+	token := c.Query("token")
+	str, err := utils.ParseJWTString("profiling/campaign_download", token)
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	campaignID, err := strconv.Atoi(str)
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	profilingType := TaskProfilingType(c.DefaultQuery("profiling_type", string(ProfilingTypeCPU)))
+	merged, err := s.mergeCampaign(uint(campaignID), profilingType)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+
+	fileName := fmt.Sprintf("profiling_campaign_%d_%s.zip", campaignID, time.Now().Format("2006-01-02_15-04-05"))
+	c.Writer.Header().Set("Content-type", "application/octet-stream")
+	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	zw := zip.NewWriter(c.Writer)
+	defer func() {
+		_ = zw.Close()
+	}()
+	for _, p := range merged {
+		zipFile, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     fmt.Sprintf("merged_%s_%s.proto", p.ProfilingType, p.Target.FileName()),
+			Method:   zip.Store, // already gzipped
+			Modified: time.Now(),
+		})
+		if err != nil {
+			rest.Error(c, err)
+			return
+		}
+		if _, err := zipFile.Write(p.Data); err != nil {
+			rest.Error(c, err)
+			return
+		}
+	}
+	if err := zipREADME(zw); err != nil {
+		rest.Error(c, err)
+		return
+	}
+}
+
+// @ID createProfilingSchedule
+// @Summary Create a profiling schedule
+// @Description Profile with the same request every interval. Each run is a task group linked by the schedule ID.
+// @Param req body CreateScheduleRequest true "schedule request"
+// @Security JwtAuth
+// @Success 200 {object} ScheduleModel
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 403 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/schedule/create [post]
+func (s *Service) handleCreateSchedule(c *gin.Context) {
+	var req CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	if len(req.Targets) == 0 && len(req.TiKVStoreIDs) == 0 {
+		rest.Error(c, rest.ErrBadRequest.New("Expect at least 1 target"))
+		return
+	}
+
+	if err := checkProfilingTypes(&req.StartRequest); err != nil {
 		rest.Error(c, err)
 		return
 	}
-	if err = s.params.LocalStore.Where("id = ?", taskGroupID).Delete(&TaskGroupModel{}).Error; err != nil {
+	if err := s.validateDuration(&req.StartRequest); err != nil {
+		rest.Error(c, err)
+		return
+	}
+
+	schedule, err := s.createSchedule(&req)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// @ID getProfilingScheduleList
+// @Summary List profiling schedules
+// @Security JwtAuth
+// @Success 200 {array} ScheduleModel
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/schedule/list [get]
+func (s *Service) getScheduleList(c *gin.Context) {
+	schedules, err := s.listSchedules()
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+// @ID deleteProfilingSchedule
+// @Summary Delete a profiling schedule
+// @Description Stop running a profiling schedule. Task groups already started by the schedule are kept.
+// @Param scheduleId path string true "schedule ID"
+// @Security JwtAuth
+// @Success 200 {object} rest.EmptyResponse
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 403 {object} rest.ErrorResponse
+// @Failure 404 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/schedule/delete/{scheduleId} [delete]
+func (s *Service) handleDeleteSchedule(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	if err := s.deleteSchedule(uint(scheduleID)); err != nil {
 		rest.Error(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, rest.EmptyResponse{})
 }
 
+type RegisterBaselineRequest struct {
+	Name   string `json:"name"`
+	TaskID uint   `json:"task_id"`
+}
+
+// @ID registerProfilingBaseline
+// @Summary Register a baseline
+// @Description Register a finished profiling result as a named baseline. An existing baseline with the same name is replaced.
+// @Param req body RegisterBaselineRequest true "baseline request"
+// @Security JwtAuth
+// @Success 200 {object} BaselineModel
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 403 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/baseline/register [post]
+func (s *Service) handleRegisterBaseline(c *gin.Context) {
+	var req RegisterBaselineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	if req.Name == "" {
+		rest.Error(c, rest.ErrBadRequest.New("Expect a baseline name"))
+		return
+	}
+	baseline, err := s.registerBaseline(req.Name, req.TaskID)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, baseline)
+}
+
+// @ID getProfilingBaselines
+// @Summary List all baselines
+// @Description List all registered profiling baselines
+// @Security JwtAuth
+// @Success 200 {array} BaselineModel
+// @Failure 401 {object} rest.ErrorResponse
+// @Router /profiling/baseline/list [get]
+func (s *Service) getBaselineList(c *gin.Context) {
+	var resp []BaselineModel
+	err := s.params.LocalStore.Omit("data").Order("created_at DESC").Find(&resp).Error
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @ID compareProfilingToBaseline
+// @Summary Compare a task with a baseline
+// @Description Compare the profiling result of a task with a baseline and report functions regressed more than the threshold
+// @Param task_id query string true "task ID"
+// @Param baseline query string true "baseline name"
+// @Param threshold query number false "regression threshold in share of total samples, e.g. 0.05"
+// @Security JwtAuth
+// @Success 200 {object} BaselineCompareResult
+// @Failure 400 {object} rest.ErrorResponse
+// @Failure 401 {object} rest.ErrorResponse
+// @Failure 500 {object} rest.ErrorResponse
+// @Router /profiling/baseline/compare [get]
+func (s *Service) handleCompareToBaseline(c *gin.Context) {
+	taskID, err := strconv.Atoi(c.Query("task_id"))
+	if err != nil {
+		rest.Error(c, rest.ErrBadRequest.NewWithNoMessage())
+		return
+	}
+	threshold := DefaultRegressionThreshold
+	if v := c.Query("threshold"); v != "" {
+		threshold, err = strconv.ParseFloat(v, 64)
+		if err != nil || threshold < 0 {
+			rest.Error(c, rest.ErrBadRequest.New("Invalid threshold %s", v))
+			return
+		}
+	}
+	result, err := s.compareToBaseline(uint(taskID), c.Query("baseline"), threshold)
+	if err != nil {
+		rest.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 // @Summary Get Profiling Dynamic Config
 // @Success 200 {object} config.ProfilingConfig
 // @Router /profiling/config [get]
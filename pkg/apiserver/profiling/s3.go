@@ -0,0 +1,176 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+)
+
+const (
+	s3DefaultRegion   = "us-east-1"
+	s3RequestTimeout  = 5 * time.Minute
+	s3SigningAlgoName = "AWS4-HMAC-SHA256"
+)
+
+// s3Store stores profiling results in an S3 compatible object storage. Objects are addressed in the path style,
+// i.e. endpoint/bucket/key, which is supported by both AWS S3 and most self-hosted implementations like MinIO.
+// Requests are signed with AWS Signature Version 4.
+type s3Store struct {
+	endpoint        *url.URL
+	region          string
+	bucket          string
+	prefix          string
+	accessKeyID     string
+	secretAccessKey string
+	client          *http.Client
+
+	now func() time.Time
+}
+
+var _ resultStore = (*s3Store)(nil)
+
+func newS3Store(cfg *config.ProfilingS3Config) (*s3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, ErrResultStoreFailed.New("endpoint and bucket of the profiling S3 storage must be configured")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, ErrResultStoreFailed.New("invalid endpoint of the profiling S3 storage: %s", cfg.Endpoint)
+	}
+	region := cfg.Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	return &s3Store{
+		endpoint:        endpoint,
+		region:          region,
+		bucket:          cfg.Bucket,
+		prefix:          cfg.Prefix,
+		accessKeyID:     cfg.AccessKeyID,
+		secretAccessKey: cfg.SecretAccessKey,
+		client:          &http.Client{Timeout: s3RequestTimeout},
+		now:             time.Now,
+	}, nil
+}
+
+func (s *s3Store) put(key string, content []byte) error {
+	_, err := s.do(http.MethodPut, key, content)
+	return err
+}
+
+func (s *s3Store) get(key string) ([]byte, error) {
+	return s.do(http.MethodGet, key, nil)
+}
+
+func (s *s3Store) remove(key string) error {
+	_, err := s.do(http.MethodDelete, key, nil)
+	return err
+}
+
+// objectPath returns the escaped path of an object, which is also the canonical URI to sign.
+func (s *s3Store) objectPath(key string) string {
+	segments := strings.Split(s.bucket+"/"+s.prefix+key, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+	return s.endpoint.EscapedPath() + "/" + strings.Join(segments, "/")
+}
+
+func (s *s3Store) do(method string, key string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, s.endpoint.Scheme+"://"+s.endpoint.Host+s.objectPath(key), bytes.NewReader(body))
+	if err != nil {
+		return nil, ErrResultStoreFailed.Wrap(err, "failed to create request for profiling result %s", key)
+	}
+	s.sign(req, body)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, ErrResultStoreFailed.Wrap(err, "failed to %s profiling result %s", method, key)
+	}
+	defer resp.Body.Close() // #nosec
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ErrResultStoreFailed.Wrap(err, "failed to %s profiling result %s", method, key)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, ErrResultStoreFailed.New("failed to %s profiling result %s, status %d: %s", method, key, resp.StatusCode, data)
+	}
+	return data, nil
+}
+
+// sign signs the request with AWS Signature Version 4, see
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html.
+func (s *s3Store) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"", // No query string
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		s3SigningAlgoName,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgoName, s.accessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape escapes a path segment as required by the signature, where only unreserved characters are kept.
+func s3Escape(segment string) string {
+	var b strings.Builder
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+const scheduleCheckInterval = 10 * time.Second
+
+// ScheduleModel profiles with the same request every interval, e.g. to collect CPU profiles continuously.
+// Each run is a task group linked by its ScheduleID.
+type ScheduleModel struct {
+	ID           uint            `json:"id" gorm:"primary_key"`
+	Request      ScheduleRequest `json:"request" gorm:"type:text"`
+	IntervalSecs uint            `json:"interval_secs"`
+	CreatedAt    int64           `json:"created_at"`
+	// The time when the last run is triggered, or 0 if the schedule has not been run yet.
+	LastRunAt int64 `json:"last_run_at"`
+	// Why the last run failed to start, or empty if it is started.
+	Error string `json:"error" gorm:"type:text"`
+}
+
+func (ScheduleModel) TableName() string {
+	return "profiling_schedules"
+}
+
+// ScheduleRequest is the profiling request of each run of a schedule, which is persisted as JSON.
+type ScheduleRequest struct {
+	StartRequest
+}
+
+func (r *ScheduleRequest) Scan(src interface{}) error {
+	return json.Unmarshal([]byte(src.(string)), r)
+}
+
+func (r ScheduleRequest) Value() (driver.Value, error) {
+	val, err := json.Marshal(r)
+	return string(val), err
+}
+
+type CreateScheduleRequest struct {
+	StartRequest
+	// The interval between the starts of two runs, which must not be shorter than the profile duration.
+	IntervalSecs uint `json:"interval_secs"`
+}
+
+// createSchedule persists a schedule, which is run by the schedule loop from the next check on.
+// The request is expected to be validated and normalized.
+func (s *Service) createSchedule(req *CreateScheduleRequest) (*ScheduleModel, error) {
+	if req.IntervalSecs == 0 || req.IntervalSecs < req.DurationSecs {
+		return nil, rest.ErrBadRequest.New("interval %d seconds must be positive and not shorter than the profile duration", req.IntervalSecs)
+	}
+	schedule := &ScheduleModel{
+		Request:      ScheduleRequest{StartRequest: req.StartRequest},
+		IntervalSecs: req.IntervalSecs,
+		CreatedAt:    time.Now().Unix(),
+	}
+	if err := s.params.LocalStore.Create(schedule).Error; err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *Service) listSchedules() ([]ScheduleModel, error) {
+	schedules := make([]ScheduleModel, 0)
+	if err := s.params.LocalStore.Order("id ASC").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// deleteSchedule stops running a schedule. Task groups already started by the schedule are kept.
+func (s *Service) deleteSchedule(scheduleID uint) error {
+	result := s.params.LocalStore.Where("id = ?", scheduleID).Delete(&ScheduleModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return rest.ErrNotFound.New("schedule %d does not exist", scheduleID)
+	}
+	return nil
+}
+
+func (s *Service) scheduleLoop(ctx context.Context) {
+	ticker := time.NewTicker(scheduleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.runDueSchedules(ctx, time.Now()); err != nil {
+				log.Warn("failed to run profiling schedules", zap.Error(err))
+			}
+		}
+	}
+}
+
+// runDueSchedules starts a task group for each schedule whose interval has elapsed since its last run. A schedule
+// is not run again while its last task group is still running. The number of started task groups is returned.
+func (s *Service) runDueSchedules(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.listSchedules()
+	if err != nil {
+		return 0, err
+	}
+	started := 0
+	for i := range schedules {
+		schedule := &schedules[i]
+		if schedule.LastRunAt != 0 && now.Unix() < schedule.LastRunAt+int64(schedule.IntervalSecs) {
+			continue
+		}
+		var running int64
+		err := s.params.LocalStore.Model(&TaskGroupModel{}).
+			Where("schedule_id = ? AND state = ?", schedule.ID, TaskStateRunning).
+			Count(&running).Error
+		if err != nil {
+			return started, err
+		}
+		if running > 0 {
+			continue
+		}
+
+		req := schedule.Request.StartRequest
+		req.scheduleID = schedule.ID
+		schedule.LastRunAt = now.Unix()
+		schedule.Error = ""
+		if _, err := s.exclusiveExecute(ctx, &req); err != nil {
+			log.Warn("failed to start scheduled profiling", zap.Uint("schedule", schedule.ID), zap.Error(err))
+			schedule.Error = err.Error()
+		} else {
+			started++
+		}
+		// Only the run state is updated, so that a schedule deleted meanwhile is not recreated.
+		err = s.params.LocalStore.Model(schedule).
+			Updates(map[string]interface{}{"last_run_at": schedule.LastRunAt, "error": schedule.Error}).Error
+		if err != nil {
+			return started, err
+		}
+	}
+	return started, nil
+}
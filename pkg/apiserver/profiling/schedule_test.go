@@ -0,0 +1,94 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+func TestSchedule(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+
+	schedule, err := s.createSchedule(&CreateScheduleRequest{
+		StartRequest: StartRequest{
+			Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+			DurationSecs:           10,
+			RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+		},
+		IntervalSecs: 60,
+	})
+	require.NoError(t, err)
+
+	// The schedule is run at the first check, and then every interval.
+	now := time.Unix(1600000000, 0)
+	for _, step := range []struct {
+		elapsed time.Duration
+		started int
+	}{
+		{0, 1},
+		{30 * time.Second, 0},
+		{60 * time.Second, 1},
+		{90 * time.Second, 0},
+		{150 * time.Second, 1},
+	} {
+		started, err := s.runDueSchedules(context.Background(), now.Add(step.elapsed))
+		require.NoError(t, err)
+		require.Equal(t, step.started, started, "elapsed %v", step.elapsed)
+		s.wg.Wait()
+	}
+
+	var groups []TaskGroupModel
+	require.NoError(t, s.params.LocalStore.Where("schedule_id = ?", schedule.ID).Order("id ASC").Find(&groups).Error)
+	require.Len(t, groups, 3)
+	for _, group := range groups {
+		require.Equal(t, TaskStateFinish, group.State)
+		require.Equal(t, uint(10), group.ProfileDurationSecs)
+		detail, err := s.groupDetail(group.ID, true, nil)
+		require.NoError(t, err)
+		require.Len(t, detail.Tasks, 1)
+	}
+
+	schedules, err := s.listSchedules()
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	require.Equal(t, now.Add(150*time.Second).Unix(), schedules[0].LastRunAt)
+	require.Equal(t, TaskProfilingTypeList{ProfilingTypeCPU}, schedules[0].Request.RequstedProfilingTypes)
+
+	// Deleted schedules are no longer run, while their task groups are kept.
+	require.NoError(t, s.deleteSchedule(schedule.ID))
+	started, err := s.runDueSchedules(context.Background(), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 0, started)
+	require.NoError(t, s.params.LocalStore.Where("schedule_id = ?", schedule.ID).Find(&groups).Error)
+	require.Len(t, groups, 3)
+
+	err = s.deleteSchedule(schedule.ID)
+	require.True(t, errorx.IsOfType(err, rest.ErrNotFound))
+}
+
+func TestScheduleInterval(t *testing.T) {
+	s := newTestService(t)
+	req := StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		DurationSecs:           30,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	}
+	_, err := s.createSchedule(&CreateScheduleRequest{StartRequest: req, IntervalSecs: 0})
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+	_, err = s.createSchedule(&CreateScheduleRequest{StartRequest: req, IntervalSecs: 20})
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+	_, err = s.createSchedule(&CreateScheduleRequest{StartRequest: req, IntervalSecs: 30})
+	require.NoError(t, err)
+}
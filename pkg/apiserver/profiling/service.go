@@ -4,24 +4,42 @@ package profiling
 
 import (
 	"context"
+	"errors"
+	"math/rand"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ReneKroon/ttlcache/v2"
 	"github.com/joomcode/errorx"
 	"github.com/ozonru/etcd/v3/clientv3"
 	"github.com/pingcap/log"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
 	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
 	"github.com/pingcap/tidb-dashboard/pkg/config"
 	"github.com/pingcap/tidb-dashboard/pkg/dbstore"
 	"github.com/pingcap/tidb-dashboard/pkg/httpc"
 	"github.com/pingcap/tidb-dashboard/pkg/pd"
+	"github.com/pingcap/tidb-dashboard/util/client/pdclient"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+	"github.com/pingcap/tidb-dashboard/util/topo"
+	"github.com/pingcap/tidb-dashboard/util/topo/pdtopo"
 )
 
 const (
 	Timeout = 5 * time.Second
+
+	customPprofPathPrefix = "/debug/pprof/"
+
+	defaultRequestTimeoutSlack = 30 * time.Second
 )
 
 var (
@@ -30,12 +48,65 @@ var (
 	ErrTimeout                    = ErrNS.NewType("timeout")
 	ErrUnsupportedProfilingType   = ErrNS.NewType("unsupported_profiling_type")
 	ErrUnsupportedProfilingTarget = ErrNS.NewType("unsupported_profiling_target")
+	ErrGroupNotRunning            = ErrNS.NewType("group_not_running")
+	ErrGroupRunning               = ErrNS.NewType("group_running")
+	ErrClientNotConfigured        = ErrNS.NewType("client_not_configured")
+	ErrTooManyRunningGroups       = ErrNS.NewType("too_many_running_groups")
 )
 
 type StartRequest struct {
-	Targets                []model.RequestTargetNode `json:"targets"`
-	DurationSecs           uint                      `json:"duration_secs"`
-	RequstedProfilingTypes TaskProfilingTypeList     `json:"requsted_profiling_types"`
+	Targets []model.RequestTargetNode `json:"targets"`
+	// Duration of profiles in seconds, which must be greater than 0 and not exceed the configured maximum.
+	DurationSecs           uint                  `json:"duration_secs"`
+	RequstedProfilingTypes TaskProfilingTypeList `json:"requsted_profiling_types"`
+	// Profiling types of targets of a component kind, which override RequstedProfilingTypes for these targets.
+	// Without RequstedProfilingTypes, every kind of targets must have profiling types here.
+	ProfilingTypesByKind map[model.NodeKind]TaskProfilingTypeList `json:"profiling_types_by_kind"`
+	// Durations of profiling types, which override DurationSecs for tasks of these types, e.g. a shorter trace
+	// along with a longer CPU profile. Durations are ignored by snapshot profiling types, e.g. heap.
+	DurationSecsByType map[TaskProfilingType]uint `json:"duration_secs_by_type"`
+	// Durations which override DurationSecs with a task per duration for each target, e.g. both a 10 seconds and a
+	// 30 seconds CPU profile of a target to compare. They are ignored by snapshot profiling types, ProfilingTypeCustom
+	// and profiling types in DurationSecsByType, which still capture a single task.
+	DurationSecsList []uint `json:"duration_secs_list"`
+	// Reject the request if any target is no longer present in the cluster topology.
+	CheckTopology bool `json:"check_topology"`
+	// Only profile the leader among the PD targets.
+	LeaderOnly bool `json:"leader_only"`
+	// Record the build ID of each target along with its profiling results, on a best-effort basis.
+	CaptureBuildID bool `json:"capture_build_id"`
+	// Run the task group again once if all of its tasks are failed, e.g. due to a transient cluster-wide failure.
+	RetryOnTotalFailure bool `json:"retry_on_total_failure"`
+	// TiKV stores to profile in addition to Targets, which are resolved to addresses by the cluster topology.
+	TiKVStoreIDs []uint64 `json:"tikv_store_ids"`
+	// Seconds allowed for fetching each profile beyond the profile duration, after which the task is failed.
+	// The configured default is used when it is 0.
+	RequestTimeoutSecs uint `json:"request_timeout_secs"`
+	// The maximum number of tasks profiling at the same time, to avoid disturbing a large cluster.
+	// All tasks are run at once when it is 0.
+	MaxConcurrency uint `json:"max_concurrency"`
+	// The maximum delay in milliseconds between launching two tasks, so that targets are not profiled at exactly
+	// the same time, which smooths the load of the cluster. The delay is randomized between half of it and itself.
+	// All tasks are launched at once when it is 0.
+	StaggerMs uint `json:"stagger_ms"`
+	// The mutex profile fraction set on TiDB targets while profiling mutex contentions, which are sampled during
+	// the profile duration. The fraction is reset to 0 afterwards. It is not changed when it is 0.
+	MutexProfileFraction int `json:"mutex_profile_fraction"`
+	// A free-text note about why the task group is captured.
+	Note string `json:"note"`
+	// Labels of the task group, by which task groups can be filtered when listing.
+	Labels map[string]string `json:"labels"`
+	// The pprof handler to fetch for ProfilingTypeCustom, e.g. /debug/pprof/custom, which must be under
+	// /debug/pprof/ of the status port of targets. It may contain a query.
+	CustomPprofPath string `json:"custom_pprof_path"`
+	// Seconds passed to the custom pprof handler as the seconds query, which is not passed when it is 0.
+	CustomPprofSeconds uint `json:"custom_pprof_seconds"`
+
+	campaignID uint
+	scheduleID uint
+	retryOf    uint
+	// The user who starts the task group, which is taken from the session rather than the request body.
+	triggeredBy string
 }
 
 type StartRequestSession struct {
@@ -47,12 +118,19 @@ type StartRequestSession struct {
 
 type ServiceParams struct {
 	fx.In
+	Config        *config.Config
 	ConfigManager *config.DynamicConfigManager
 	LocalStore    *dbstore.DB
 
-	HTTPClient *httpc.Client
-	EtcdClient *clientv3.Client
-	PDClient   *pd.Client
+	HTTPClient  *httpc.Client
+	EtcdClient  *clientv3.Client
+	PDClient    *pd.Client
+	PDAPIClient *pdclient.APIClient
+
+	// Registerer is where the profiling metrics are registered, or nil if the metrics are not exported.
+	Registerer prometheus.Registerer `optional:"true"`
+	// Logger is where the progress of task groups and tasks is logged, or nil to use the global logger.
+	Logger *zap.Logger `optional:"true"`
 }
 
 type Service struct {
@@ -62,34 +140,100 @@ type Service struct {
 	wg            sync.WaitGroup
 	sessionCh     chan *StartRequestSession
 	lastTaskGroup *TaskGroup
+	runningGroups int32 // The number of running task groups started by startGroup, which is accessed atomically
+	storageMu     sync.Mutex
+	reservedBytes int64 // The storage reserved for running task groups, which is guarded by storageMu
 	tasks         sync.Map
 	fetchers      *fetchers
+	results       *resultFiles
+	topoProvider  topo.TopologyProvider
+	targetsCache  *targetsCache // Targets are not cached if it is nil
+	metrics       *metrics
+	logger        *zap.Logger // Nothing is logged by task groups if it is nil
+
+	flameGraphCache *ttlcache.Cache
 }
 
 var newService = fx.Provide(func(lc fx.Lifecycle, p ServiceParams, fts *fetchers) (*Service, error) {
 	if err := autoMigrate(p.LocalStore); err != nil {
 		return nil, err
 	}
-	s := &Service{params: p, fetchers: fts}
+	results, err := newResultFiles(p.Config)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMetrics(p.Registerer)
+	if err != nil {
+		return nil, err
+	}
+	logger := p.Logger
+	if logger == nil {
+		logger = log.L()
+	}
+	s := &Service{params: p, fetchers: fts, results: results, metrics: m, logger: logger, targetsCache: newTargetsCache(p.Config), flameGraphCache: newFlameGraphCache()}
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			s.lifecycleCtx = ctx
-			s.wg.Add(1)
+			pdAPIClient := p.PDAPIClient.Clone()
+			pdAPIClient.SetDefaultBaseURL(p.Config.PDEndPoint)
+			s.topoProvider = pdtopo.NewTopologyProviderFromPD(p.EtcdClient, pdAPIClient)
+			if err := s.markInterruptedGroups(); err != nil {
+				return err
+			}
+			s.wg.Add(3)
 			go func() {
 				defer s.wg.Done()
 				s.serviceLoop(ctx)
 			}()
+			go func() {
+				defer s.wg.Done()
+				s.janitorLoop(ctx)
+			}()
+			go func() {
+				defer s.wg.Done()
+				s.scheduleLoop(ctx)
+			}()
 			return nil
 		},
-		OnStop: func(context.Context) error {
-			s.wg.Wait()
-			return nil
+		OnStop: func(ctx context.Context) error {
+			s.shutdown(ctx)
+			return s.flameGraphCache.Close()
 		},
 	})
 
 	return s, nil
 })
 
+// shutdownTimeout is the time allowed for running tasks to save their states once they are cancelled on shutdown.
+const shutdownTimeout = 10 * time.Second
+
+// errCancelledByShutdown is the error of tasks which are cancelled because the service is stopped.
+const errCancelledByShutdown = "cancelled by shutdown"
+
+// shutdown cancels the running tasks and waits until they are stopped, so that nothing is written to the local store
+// after it is closed. It gives up after shutdownTimeout or once ctx is done, and tasks which are still running then
+// are marked as interrupted by markInterruptedGroups on the next start.
+func (s *Service) shutdown(ctx context.Context) {
+	s.tasks.Range(func(_, task interface{}) bool {
+		task.(*Task).stopWithReason(errCancelledByShutdown)
+		return true
+	})
+	stopped := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(stopped)
+	}()
+	timer := time.NewTimer(shutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+	case <-timer.C:
+		log.Warn("profiling tasks are not stopped in time on shutdown", zap.Duration("timeout", shutdownTimeout))
+	case <-ctx.Done():
+		log.Warn("profiling tasks are not stopped before shutdown is aborted", zap.Error(ctx.Err()))
+	}
+}
+
 func (s *Service) serviceLoop(ctx context.Context) {
 	cfgCh := s.params.ConfigManager.NewPushChannel()
 	s.sessionCh = make(chan *StartRequestSession, 1000)
@@ -143,8 +287,32 @@ func (s *Service) handleRequest(ctx context.Context, session *StartRequestSessio
 }
 
 func (s *Service) exclusiveExecute(ctx context.Context, req *StartRequest) (*TaskGroup, error) {
+	if len(req.TiKVStoreIDs) > 0 {
+		storeTargets, err := s.tiKVStoreTargets(ctx, req.TiKVStoreIDs)
+		if err != nil {
+			return nil, err
+		}
+		resolvedReq := *req
+		resolvedReq.Targets = append(append([]model.RequestTargetNode{}, req.Targets...), storeTargets...)
+		resolvedReq.TiKVStoreIDs = nil
+		req = &resolvedReq
+	}
+	if req.CheckTopology {
+		if err := s.checkTargetsInTopology(ctx, req.Targets); err != nil {
+			return nil, err
+		}
+	}
+	if req.LeaderOnly {
+		targets, err := s.leaderTargets(ctx, req.Targets)
+		if err != nil {
+			return nil, err
+		}
+		filteredReq := *req
+		filteredReq.Targets = targets
+		req = &filteredReq
+	}
 	if s.lastTaskGroup != nil {
-		if err := s.cancelGroup(s.lastTaskGroup.ID); err != nil {
+		if err := s.stopGroup(s.lastTaskGroup.ID); err != nil {
 			return nil, ErrIgnoredRequest.New("failed to cancel last task group: id = %d", s.lastTaskGroup.ID)
 		}
 		time.Sleep(500 * time.Millisecond)
@@ -153,93 +321,660 @@ func (s *Service) exclusiveExecute(ctx context.Context, req *StartRequest) (*Tas
 }
 
 func (s *Service) startGroup(ctx context.Context, req *StartRequest) (*TaskGroup, error) {
+	if targets := uniqueTargets(req.Targets); len(targets) != len(req.Targets) {
+		dedupedReq := *req
+		dedupedReq.Targets = targets
+		req = &dedupedReq
+	}
+	// Profiling types are checked before the task group is created, so that no useless task group is saved.
+	if err := checkProfilingTypes(req); err != nil {
+		return nil, err
+	}
+	if err := checkLabels(req.Labels); err != nil {
+		return nil, err
+	}
+	if err := s.acquireGroupSlot(); err != nil {
+		return nil, err
+	}
+	started := false
+	defer func() {
+		if !started {
+			s.releaseGroupSlot()
+		}
+	}()
+	reservedBytes, err := s.reserveStorage(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if !started {
+			s.releaseStorage(reservedBytes)
+		}
+	}()
 	taskGroup := NewTaskGroup(s.params.LocalStore, req.DurationSecs, model.NewRequestTargetStatisticsFromArray(&req.Targets), req.RequstedProfilingTypes)
-	if err := s.params.LocalStore.Create(taskGroup.TaskGroupModel).Error; err != nil {
+	taskGroup.CampaignID = req.campaignID
+	taskGroup.ScheduleID = req.scheduleID
+	taskGroup.RetryOf = req.retryOf
+	taskGroup.Note = req.Note
+	taskGroup.TriggeredBy = req.triggeredBy
+	taskGroup.Labels = req.Labels
+	taskGroup.reservedBytes = reservedBytes
+	err = s.params.LocalStore.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(taskGroup.TaskGroupModel).Error; err != nil {
+			return err
+		}
+		return saveGroupLabels(tx, taskGroup.ID, req.Labels)
+	})
+	if err != nil {
 		log.Warn("failed to start task group", zap.Error(err))
 		return nil, err
 	}
+	taskGroup.logger = s.groupLogger(taskGroup.ID)
 
+	fts := s.fetchers.shared()
 	tasks := make([]*Task, 0, len(req.Targets))
 	for _, target := range req.Targets {
 		profileTypeList := req.RequstedProfilingTypes
+		if types, ok := req.ProfilingTypesByKind[target.Kind]; ok {
+			profileTypeList = types
+		}
 		for _, profilingType := range profileTypeList {
-			// profilingTypeMap checks the validation of requestedProfilingType.
-			_, valid := profilingTypeMap[profilingType]
-			if !valid {
-				return nil, ErrUnsupportedProfilingType.NewWithNoMessage()
+			for _, durationSecs := range req.durationSecsListOf(profilingType) {
+				t := NewTask(ctx, taskGroup, target, fts, s.results, profilingType)
+				t.captureBuildID = req.CaptureBuildID
+				t.DurationSecs = durationSecs
+				t.timeout = s.fetchTimeout(t.DurationSecs, req.RequestTimeoutSecs)
+				t.mutexProfileFraction = req.MutexProfileFraction
+				if profilingType == ProfilingTypeCustom {
+					// The path is validated along with the profiling types.
+					t.CustomPath, _ = customPprofPath(req)
+					if req.CustomPprofSeconds > t.DurationSecs {
+						t.timeout = s.fetchTimeout(req.CustomPprofSeconds, req.RequestTimeoutSecs)
+					}
+				}
+				t.metrics = s.metrics
+				if req.MaxConcurrency > 0 || req.StaggerMs > 0 {
+					// The task may wait for a slot or its launch before profiling, so it is not started until then.
+					t.StartedAt = 0
+					if req.CheckTopology {
+						// The target may be removed from the cluster while the task is waiting, which is checked again.
+						t.checkTopology = s.checkTargetInTopology
+					}
+				}
+				s.params.LocalStore.Create(t.TaskModel)
+				s.tasks.Store(t.ID, t)
+				tasks = append(tasks, t)
 			}
-
-			t := NewTask(ctx, taskGroup, target, s.fetchers, profilingType)
-			s.params.LocalStore.Create(t.TaskModel)
-			s.tasks.Store(t.ID, t)
-			tasks = append(tasks, t)
 		}
 	}
 
+	started = true
+	s.metrics.taskGroupStarted()
+	taskGroup.log().Info("profiling task group started",
+		zap.Int("targets", len(req.Targets)),
+		zap.Int("tasks", len(tasks)),
+		zap.Uint("duration_secs", req.DurationSecs),
+		zap.Uint("retry_of", req.retryOf))
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
 		var wg sync.WaitGroup
+		var sem chan struct{}
+		if req.MaxConcurrency > 0 {
+			sem = make(chan struct{}, req.MaxConcurrency)
+		}
 		for i := 0; i < len(tasks); i++ {
+			if i > 0 && req.StaggerMs > 0 {
+				staggerLaunch(ctx, req.StaggerMs)
+			}
 			wg.Add(1)
 			go func(idx int) {
 				defer wg.Done()
+				if sem != nil {
+					sem <- struct{}{}
+					defer func() { <-sem }()
+				}
+				if sem != nil || req.StaggerMs > 0 {
+					// The task starts profiling only now, so the progress is estimated from here.
+					m := tasks[idx].snapshot()
+					m.StartedAt = time.Now().Unix()
+					tasks[idx].save(m)
+				}
 				tasks[idx].run()
 				s.tasks.Delete(tasks[idx].ID)
 			}(i)
 		}
 		wg.Wait()
-		errorTasks := 0
-		finishedTasks := 0
+		states := make([]TaskState, 0, len(tasks))
 		for _, task := range tasks {
-			if task.State == TaskStateError {
-				errorTasks++
-			} else if task.State == TaskStateFinish {
-				finishedTasks++
-			}
+			states = append(states, task.State)
 		}
-		if errorTasks > 0 {
-			taskGroup.State = TaskStateError
-			if finishedTasks > 0 {
-				taskGroup.State = TaskStatePartialFinish
+		taskGroup.setState(taskGroupState(states))
+		close(taskGroup.done)
+		s.releaseGroupSlot()
+		s.releaseStorage(taskGroup.reservedBytes)
+		s.metrics.taskGroupStopped()
+		taskGroup.log().Info("profiling task group stopped", zap.String("state", taskStateLabels[taskGroup.State]))
+
+		if taskGroup.State == TaskStateError && req.RetryOnTotalFailure && ctx.Err() == nil {
+			retryReq := *req
+			retryReq.RetryOnTotalFailure = false
+			retryReq.retryOf = taskGroup.ID
+			if _, err := s.startGroup(ctx, &retryReq); err != nil {
+				taskGroup.log().Warn("failed to retry task group", zap.Error(err))
 			}
-		} else {
-			taskGroup.State = TaskStateFinish
 		}
-		s.params.LocalStore.Save(taskGroup.TaskGroupModel)
 	}()
 
 	return taskGroup, nil
 }
 
+const defaultMaxRunningGroups = 32
+
+// maxRunningGroups returns the maximum number of running task groups, or 0 if it is not limited.
+func (s *Service) maxRunningGroups() int {
+	if s.params.Config == nil || s.params.Config.ProfilingMaxRunningGroups == 0 {
+		return defaultMaxRunningGroups
+	}
+	if s.params.Config.ProfilingMaxRunningGroups < 0 {
+		return 0
+	}
+	return s.params.Config.ProfilingMaxRunningGroups
+}
+
+// acquireGroupSlot counts a task group to be started, or returns an error if too many task groups are running,
+// so that starting task groups repeatedly does not exhaust connections and memory.
+func (s *Service) acquireGroupSlot() error {
+	n := atomic.AddInt32(&s.runningGroups, 1)
+	if max := s.maxRunningGroups(); max > 0 && int(n) > max {
+		atomic.AddInt32(&s.runningGroups, -1)
+		return ErrTooManyRunningGroups.New("too many active task groups, at most %d task groups can run at the same time", max)
+	}
+	return nil
+}
+
+// releaseGroupSlot stops counting a task group which is stopped or not started.
+func (s *Service) releaseGroupSlot() {
+	atomic.AddInt32(&s.runningGroups, -1)
+}
+
+// groupLogger returns the logger of a task group, which tags all logs with the task group ID.
+func (s *Service) groupLogger(taskGroupID uint) *zap.Logger {
+	if s.logger == nil {
+		return nil
+	}
+	return s.logger.With(zap.Uint("task_group_id", taskGroupID))
+}
+
+// staggerLaunch waits for a random delay between half of staggerMs and staggerMs before launching the next task.
+// It returns early when the context is done, so that the remaining tasks are launched and cancelled at once.
+func staggerLaunch(ctx context.Context, staggerMs uint) {
+	delay := time.Duration(staggerMs/2+uint(rand.Int63n(int64(staggerMs-staggerMs/2)+1))) * time.Millisecond
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// checkProfilingTypes rejects a profiling request without any profiling type, which would create a task group
+// without tasks, or with unknown profiling types. Without RequstedProfilingTypes, every kind of targets must be in
+// ProfilingTypesByKind, otherwise targets of the unmapped kind would silently get no task.
+func checkProfilingTypes(req *StartRequest) error {
+	if len(req.RequstedProfilingTypes) == 0 && len(req.ProfilingTypesByKind) == 0 {
+		return rest.ErrBadRequest.New("Expect at least 1 profiling type")
+	}
+	if len(req.RequstedProfilingTypes) == 0 {
+		for _, target := range req.Targets {
+			if _, ok := req.ProfilingTypesByKind[target.Kind]; !ok {
+				return rest.ErrBadRequest.New("no profiling type for targets of kind %q", target.Kind)
+			}
+		}
+		// TiKV stores may be not resolved to targets yet.
+		if _, ok := req.ProfilingTypesByKind[model.NodeKindTiKV]; !ok && len(req.TiKVStoreIDs) > 0 {
+			return rest.ErrBadRequest.New("no profiling type for targets of kind %q", model.NodeKindTiKV)
+		}
+	}
+	check := func(types TaskProfilingTypeList) error {
+		for _, profilingType := range types {
+			if _, ok := profilingTypeMap[profilingType]; !ok {
+				return rest.ErrBadRequest.New("unknown profiling type %q", profilingType)
+			}
+		}
+		return nil
+	}
+	if err := check(req.RequstedProfilingTypes); err != nil {
+		return err
+	}
+	for _, types := range req.ProfilingTypesByKind {
+		if err := check(types); err != nil {
+			return err
+		}
+	}
+	if requestsProfilingType(req, ProfilingTypeCustom) {
+		if _, err := customPprofPath(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func requestsProfilingType(req *StartRequest, profilingType TaskProfilingType) bool {
+	for _, t := range req.RequstedProfilingTypes {
+		if t == profilingType {
+			return true
+		}
+	}
+	for _, types := range req.ProfilingTypesByKind {
+		for _, t := range types {
+			if t == profilingType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// customPprofPath validates the custom pprof path of a request and returns the path to fetch with the seconds
+// query. The path is only allowed under /debug/pprof/, so that it cannot be used to request other APIs or hosts.
+func customPprofPath(req *StartRequest) (string, error) {
+	u, err := url.Parse(req.CustomPprofPath)
+	if err != nil {
+		return "", rest.ErrBadRequest.New("invalid custom pprof path %q", req.CustomPprofPath)
+	}
+	if u.Scheme != "" || u.Host != "" || u.User != nil || u.Opaque != "" || u.Fragment != "" ||
+		!strings.HasPrefix(u.Path, customPprofPathPrefix) || u.Path == customPprofPathPrefix || path.Clean(u.Path) != u.Path {
+		return "", rest.ErrBadRequest.New("invalid custom pprof path %q, expect a path under %s", req.CustomPprofPath, customPprofPathPrefix)
+	}
+	query := u.Query()
+	if req.CustomPprofSeconds > 0 {
+		query.Set("seconds", strconv.Itoa(int(req.CustomPprofSeconds)))
+	}
+	u.RawQuery = query.Encode()
+	return u.RequestURI(), nil
+}
+
+// validateDuration rejects a profiling request if the duration is 0 or exceeds the configured maximum, since a task
+// keeps running for the whole duration.
+func (s *Service) validateDuration(req *StartRequest) error {
+	maxDurationSecs := uint(config.MaxProfilingAutoCollectionDurationSecs)
+	if s.params.Config != nil && s.params.Config.ProfilingMaxDurationSecs > 0 {
+		maxDurationSecs = s.params.Config.ProfilingMaxDurationSecs
+	}
+	if req.DurationSecs == 0 {
+		return rest.ErrBadRequest.New("duration_secs must be greater than 0")
+	}
+	if req.DurationSecs > maxDurationSecs {
+		return rest.ErrBadRequest.New("duration_secs %d exceeds the maximum %d", req.DurationSecs, maxDurationSecs)
+	}
+	for profilingType, durationSecs := range req.DurationSecsByType {
+		if _, ok := profilingTypeMap[profilingType]; !ok {
+			return rest.ErrBadRequest.New("unknown profiling type %q", profilingType)
+		}
+		if durationSecs == 0 {
+			return rest.ErrBadRequest.New("duration of %s must be greater than 0", profilingType)
+		}
+		if durationSecs > maxDurationSecs {
+			return rest.ErrBadRequest.New("duration of %s %d exceeds the maximum %d", profilingType, durationSecs, maxDurationSecs)
+		}
+	}
+	seen := make(map[uint]struct{}, len(req.DurationSecsList))
+	for _, durationSecs := range req.DurationSecsList {
+		if durationSecs == 0 {
+			return rest.ErrBadRequest.New("durations in duration_secs_list must be greater than 0")
+		}
+		if durationSecs > maxDurationSecs {
+			return rest.ErrBadRequest.New("duration %d in duration_secs_list exceeds the maximum %d", durationSecs, maxDurationSecs)
+		}
+		if _, ok := seen[durationSecs]; ok {
+			return rest.ErrBadRequest.New("duration %d is duplicated in duration_secs_list", durationSecs)
+		}
+		seen[durationSecs] = struct{}{}
+	}
+	return nil
+}
+
+// durationSecsListOf returns the profile durations of tasks of a profiling type, each of which is captured by a
+// task for each target.
+func (req *StartRequest) durationSecsListOf(profilingType TaskProfilingType) []uint {
+	if _, ok := req.DurationSecsByType[profilingType]; ok || len(req.DurationSecsList) == 0 ||
+		profilingType.isSnapshot() || profilingType == ProfilingTypeCustom {
+		return []uint{req.durationSecsOf(profilingType)}
+	}
+	return req.DurationSecsList
+}
+
+// durationSecsOf returns the profile duration of tasks of a profiling type.
+func (req *StartRequest) durationSecsOf(profilingType TaskProfilingType) uint {
+	if durationSecs, ok := req.DurationSecsByType[profilingType]; ok {
+		return durationSecs
+	}
+	return req.DurationSecs
+}
+
+// fetchTimeout returns the time allowed for fetching a profile of the given duration, including the slack for
+// connecting to the target and transferring the profile.
+func (s *Service) fetchTimeout(durationSecs uint, slackSecs uint) time.Duration {
+	slack := time.Duration(slackSecs) * time.Second
+	if slack == 0 && s.params.Config != nil {
+		slack = s.params.Config.ProfilingRequestTimeoutSlack
+	}
+	if slack == 0 {
+		slack = defaultRequestTimeoutSlack
+	}
+	return time.Duration(durationSecs)*time.Second + slack
+}
+
+// taskGroupState summarizes the state of a task group from the states of its stopped tasks.
+func taskGroupState(taskStates []TaskState) TaskState {
+	errorTasks := 0
+	finishedTasks := 0
+	cancelledTasks := 0
+	for _, state := range taskStates {
+		switch state {
+		case TaskStateError:
+			errorTasks++
+		case TaskStateFinish:
+			finishedTasks++
+		case TaskStateCancelled:
+			cancelledTasks++
+		}
+	}
+	if cancelledTasks > 0 {
+		if finishedTasks > 0 {
+			return TaskStatePartialFinish
+		}
+		return TaskStateCancelled
+	}
+	if errorTasks > 0 {
+		if finishedTasks > 0 {
+			return TaskStatePartialFinish
+		}
+		return TaskStateError
+	}
+	return TaskStateFinish
+}
+
+// cancelGroup cancels the running tasks of a task group. Results of finished tasks are kept.
+// Cancelling a cancelled task group again is a no-op, while cancelling a stopped task group is an error.
 func (s *Service) cancelGroup(taskGroupID uint) error {
+	var taskGroup TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", taskGroupID).First(&taskGroup).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return rest.ErrNotFound.New("task group %d does not exist", taskGroupID)
+		}
+		return err
+	}
+	if taskGroup.State != TaskStateRunning {
+		var cancelledTasks int64
+		if err := s.params.LocalStore.Model(&TaskModel{}).Where("task_group_id = ? AND state = ?", taskGroupID, TaskStateCancelled).Count(&cancelledTasks).Error; err != nil {
+			return err
+		}
+		if cancelledTasks > 0 {
+			return nil
+		}
+		return ErrGroupNotRunning.New("task group %d is not running", taskGroupID)
+	}
+	return s.stopGroup(taskGroupID)
+}
+
+// stopGroup stops the running tasks of a task group and waits for them to stop.
+func (s *Service) stopGroup(taskGroupID uint) error {
 	var tasks []TaskModel
 	if err := s.params.LocalStore.Where("task_group_id = ? AND state = ?", taskGroupID, TaskStateRunning).Find(&tasks).Error; err != nil {
 		log.Warn("failed to cancel task group", zap.Error(err))
 		return err
 	}
 
+	running := make([]*Task, 0, len(tasks))
 	for _, task := range tasks {
 		if task, ok := s.tasks.Load(task.ID); ok {
 			t := task.(*Task)
 			t.stop()
+			running = append(running, t)
 		}
 	}
+	for _, t := range running {
+		t.wait()
+	}
 
-	// wait for tasks stop
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
-	for {
-		var runningTasks []TaskModel
-		if err := s.params.LocalStore.Where("task_group_id = ? AND state = ?", taskGroupID, TaskStateRunning).Find(&runningTasks).Error; err != nil {
-			log.Warn("failed to cancel task group", zap.Error(err))
+	return nil
+}
+
+// deleteGroup deletes a stopped task group with all of its tasks and profiling results in a transaction.
+// A running task group must be cancelled before being deleted. Baselines registered from its results are deleted
+// with it.
+func (s *Service) deleteGroup(taskGroupID uint) error {
+	return s.removeGroup(taskGroupID, false)
+}
+
+// evictGroup deletes a stopped task group like deleteGroup to free storage, e.g. by the janitor, unless any of its
+// results is pinned by a baseline.
+func (s *Service) evictGroup(taskGroupID uint) error {
+	return s.removeGroup(taskGroupID, true)
+}
+
+func (s *Service) removeGroup(taskGroupID uint, keepPinned bool) error {
+	var taskGroup TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", taskGroupID).Find(&taskGroup).Error; err != nil {
+		return err
+	}
+	if taskGroup.ID == 0 {
+		return rest.ErrNotFound.New("task group %d does not exist", taskGroupID)
+	}
+	if taskGroup.State == TaskStateRunning {
+		return ErrGroupRunning.New("task group %d is still running, cancel it before deleting", taskGroupID)
+	}
+
+	var tasks []TaskModel
+	err := s.params.LocalStore.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_group_id = ?", taskGroupID).Find(&tasks).Error; err != nil {
 			return err
 		}
-		if len(runningTasks) == 0 {
-			break
+		taskIDs := make([]uint, 0, len(tasks))
+		for _, task := range tasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		var baselines []string
+		if err := tx.Model(&BaselineModel{}).Where("task_id IN ?", taskIDs).Order("name ASC").Pluck("name", &baselines).Error; err != nil {
+			return err
+		}
+		if len(baselines) > 0 {
+			if keepPinned {
+				return ErrGroupPinned.New("task group %d is used by baselines %s", taskGroupID, strings.Join(baselines, ", "))
+			}
+			if err := tx.Where("task_id IN ?", taskIDs).Delete(&BaselineModel{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("task_group_id = ?", taskGroupID).Delete(&TaskModel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_group_id = ?", taskGroupID).Delete(&TaskGroupCommentModel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_group_id = ?", taskGroupID).Delete(&TaskGroupLabelModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", taskGroupID).Delete(&TaskGroupModel{}).Error
+	})
+	if err != nil {
+		return err
+	}
+	// Files are removed after the transaction is committed, so that results are never lost if it is rolled back.
+	for _, task := range tasks {
+		if err := s.results.removeResult(task.FilePath, task.StorageKey); err != nil {
+			log.Warn("failed to remove profiling result", zap.String("path", task.FilePath), zap.Error(err))
 		}
-		<-ticker.C
 	}
-
 	return nil
 }
+
+const (
+	// defaultGroupListLimit is the page size used when a list request does not specify one.
+	defaultGroupListLimit = 1000
+	maxGroupListLimit     = 1000
+)
+
+type ListGroupsRequest struct {
+	Limit  int `json:"limit" form:"limit"`
+	Offset int `json:"offset" form:"offset"`
+	// Only list task groups in these states if not empty.
+	States []TaskState `json:"states" form:"states"`
+	// Only list task groups with tasks of these profiling types if not empty.
+	ProfilingTypes []TaskProfilingType `json:"profiling_types" form:"profiling_types"`
+	// Only list task groups with all of these labels if not empty, each in the form of "key:value".
+	Labels []string `json:"labels" form:"labels"`
+	// Only list task groups started by this user if not empty.
+	TriggeredBy string `json:"triggered_by" form:"triggered_by"`
+}
+
+type ListGroupsResponse struct {
+	Total  int64            `json:"total"`
+	Groups []TaskGroupModel `json:"groups"`
+}
+
+// listGroups returns a page of task groups matching the filters ordered by ID descending, i.e. the latest first,
+// with the total number of matched task groups.
+func (s *Service) listGroups(req ListGroupsRequest) (*ListGroupsResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = defaultGroupListLimit
+	}
+	if req.Limit > maxGroupListLimit {
+		req.Limit = maxGroupListLimit
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+	labelKeys := make([]string, 0, len(req.Labels))
+	labelValues := make([]string, 0, len(req.Labels))
+	for _, filter := range req.Labels {
+		key, value, err := parseLabelFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		labelKeys = append(labelKeys, key)
+		labelValues = append(labelValues, value)
+	}
+
+	query := func() *gorm.DB {
+		db := s.params.LocalStore.Model(&TaskGroupModel{})
+		if len(req.States) > 0 {
+			db = db.Where("state IN ?", req.States)
+		}
+		if req.TriggeredBy != "" {
+			db = db.Where("triggered_by = ?", req.TriggeredBy)
+		}
+		if len(req.ProfilingTypes) > 0 {
+			taskGroupIDs := s.params.LocalStore.Model(&TaskModel{}).Select("task_group_id").Where("profiling_type IN ?", req.ProfilingTypes)
+			db = db.Where("id IN (?)", taskGroupIDs)
+		}
+		for i := range labelKeys {
+			taskGroupIDs := s.params.LocalStore.Model(&TaskGroupLabelModel{}).Select("task_group_id").Where("key = ? AND value = ?", labelKeys[i], labelValues[i])
+			db = db.Where("id IN (?)", taskGroupIDs)
+		}
+		return db
+	}
+
+	resp := &ListGroupsResponse{Groups: make([]TaskGroupModel, 0)}
+	if err := query().Count(&resp.Total).Error; err != nil {
+		return nil, err
+	}
+	if err := query().Order("id DESC").Limit(req.Limit).Offset(req.Offset).Find(&resp.Groups).Error; err != nil {
+		return nil, err
+	}
+	if err := s.fillGroupLabels(resp.Groups); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// groupDetail returns a task group with its comments. The tasks are only loaded when includeTasks is true, which
+// saves querying the tasks when only the task group itself is needed. Only tasks in the given states are loaded
+// if states is not empty. The fields derived from the tasks always cover all tasks of the task group.
+func (s *Service) groupDetail(taskGroupID uint, includeTasks bool, states []TaskState) (*GroupDetailResponse, error) {
+	var taskGroup TaskGroupModel
+	if err := s.params.LocalStore.Where("id = ?", taskGroupID).Find(&taskGroup).Error; err != nil {
+		return nil, err
+	}
+	comments, err := s.listGroupComments(taskGroupID)
+	if err != nil {
+		return nil, err
+	}
+	groups := []TaskGroupModel{taskGroup}
+	if err := s.fillGroupLabels(groups); err != nil {
+		return nil, err
+	}
+	taskGroup = groups[0]
+
+	now := time.Now().Unix()
+	resp := &GroupDetailResponse{
+		ServerTime: now, // Used to estimate task progress
+		TaskGroup:  taskGroup,
+		Comments:   comments,
+	}
+	if !includeTasks || len(states) > 0 {
+		// The loaded tasks do not cover all tasks, so the derived fields are aggregated by the database instead.
+		if resp.ObservedProfilingTypes, err = s.groupObservedProfilingTypes(taskGroupID); err != nil {
+			return nil, err
+		}
+		if resp.ErrorSummary, err = s.groupErrorSummary(taskGroupID); err != nil {
+			return nil, err
+		}
+	}
+	if !includeTasks {
+		return resp, nil
+	}
+
+	query := s.params.LocalStore.Where("task_group_id = ?", taskGroupID)
+	if len(states) > 0 {
+		query = query.Where("state IN ?", states)
+	}
+	var tasks []TaskModel
+	if err := query.Order("id ASC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i].Progress = estimateProgress(&tasks[i], taskGroup.ProfileDurationSecs, now)
+	}
+	resp.Tasks = tasks
+	if len(states) == 0 {
+		resp.ObservedProfilingTypes = observedProfilingTypes(tasks)
+		resp.ErrorSummary = errorSummary(tasks)
+	}
+	return resp, nil
+}
+
+// groupObservedProfilingTypes is the same as observedProfilingTypes of all tasks of a task group, without loading
+// the tasks.
+func (s *Service) groupObservedProfilingTypes(taskGroupID uint) (TaskProfilingTypeList, error) {
+	types := make(TaskProfilingTypeList, 0)
+	err := s.params.LocalStore.Model(&TaskModel{}).
+		Where("task_group_id = ? AND state = ?", taskGroupID, TaskStateFinish).
+		Group("profiling_type").
+		Order("MIN(id) ASC").
+		Pluck("profiling_type", &types).Error
+	return types, err
+}
+
+// groupErrorSummary is the same as errorSummary of all tasks of a task group, without loading the tasks.
+func (s *Service) groupErrorSummary(taskGroupID uint) (map[string]int, error) {
+	var counts []struct {
+		Error string
+		Count int
+	}
+	err := s.params.LocalStore.Model(&TaskModel{}).
+		Select("error, COUNT(*) AS count").
+		Where("task_group_id = ? AND state = ?", taskGroupID, TaskStateError).
+		Group("error").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	summary := make(map[string]int, len(counts))
+	for _, c := range counts {
+		summary[c.Error] = c.Count
+	}
+	return summary, nil
+}
@@ -0,0 +1,1588 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/pprof/profile"
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/utils"
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+	"github.com/pingcap/tidb-dashboard/pkg/dbstore"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+func newTestService(t *testing.T) *Service {
+	gormDB, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "dashboard.sqlite.db")), &gorm.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = utils.CloseTiDBConnection(gormDB)
+	})
+	db := &dbstore.DB{DB: gormDB}
+	require.NoError(t, autoMigrate(db))
+	flameGraphCache := newFlameGraphCache()
+	t.Cleanup(func() {
+		_ = flameGraphCache.Close()
+	})
+	return &Service{
+		params:       ServiceParams{LocalStore: db},
+		lifecycleCtx: context.Background(),
+		fetchers:     &fetchers{},
+
+		flameGraphCache: flameGraphCache,
+	}
+}
+
+type mockFetcher struct {
+	fetchFn func(op *fetchOptions) ([]byte, error)
+}
+
+func (f *mockFetcher) fetch(op *fetchOptions) ([]byte, error) {
+	return f.fetchFn(op)
+}
+
+// runTestGroup starts a task group and waits for all of its tasks to finish.
+func runTestGroup(t *testing.T, s *Service, req *StartRequest) ([]TaskModel, *TaskGroupModel) {
+	taskGroup, err := s.startGroup(context.Background(), req)
+	require.NoError(t, err)
+	s.wg.Wait()
+
+	var group TaskGroupModel
+	require.NoError(t, s.params.LocalStore.Where("id = ?", taskGroup.ID).First(&group).Error)
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Order("id ASC").Find(&tasks).Error)
+	return tasks, &group
+}
+
+// newTestCPUProfile builds a CPU profile whose samples are the flat values of the given functions.
+func newTestCPUProfile(t *testing.T, flat map[string]int64) []byte {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "samples", Unit: "count"},
+			{Type: "cpu", Unit: "nanoseconds"},
+		},
+		PeriodType: &profile.ValueType{Type: "cpu", Unit: "nanoseconds"},
+		Period:     10000000,
+	}
+	return writeTestProfile(t, p, flat, func(v int64) []int64 {
+		return []int64{v / p.Period, v}
+	})
+}
+
+// newTestHeapProfile builds a heap profile whose samples are the in-use objects allocated by the given functions.
+func newTestHeapProfile(t *testing.T, inuseObjects map[string]int64) []byte {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "alloc_objects", Unit: "count"},
+			{Type: "alloc_space", Unit: "bytes"},
+			{Type: "inuse_objects", Unit: "count"},
+			{Type: "inuse_space", Unit: "bytes"},
+		},
+		PeriodType: &profile.ValueType{Type: "space", Unit: "bytes"},
+		Period:     512 * 1024,
+	}
+	return writeTestProfile(t, p, inuseObjects, func(v int64) []int64 {
+		return []int64{v, v * 64, v, v * 64}
+	})
+}
+
+func writeTestProfile(t *testing.T, p *profile.Profile, flat map[string]int64, values func(int64) []int64) []byte {
+	names := make([]string, 0, len(flat))
+	for name := range flat {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		fn := &profile.Function{ID: uint64(i + 1), Name: name, SystemName: name}
+		loc := &profile.Location{ID: uint64(i + 1), Line: []profile.Line{{Function: fn}}}
+		p.Function = append(p.Function, fn)
+		p.Location = append(p.Location, loc)
+		p.Sample = append(p.Sample, &profile.Sample{
+			Location: []*profile.Location{loc},
+			Value:    values(flat[name]),
+		})
+	}
+	buf := bytes.Buffer{}
+	require.NoError(t, p.Write(&buf))
+	return buf.Bytes()
+}
+
+// newTestFinishedTask persists a finished task whose profiling result is the given content.
+func newTestFinishedTask(t *testing.T, s *Service, profilingType TaskProfilingType, rawDataType TaskRawDataType, content []byte) *TaskModel {
+	f, err := ioutil.TempFile(t.TempDir(), "profile")
+	require.NoError(t, err)
+	_, err = f.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	task := &TaskModel{
+		State:         TaskStateFinish,
+		Target:        model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:10080", IP: "127.0.0.1", Port: 10080},
+		FilePath:      f.Name(),
+		RawDataType:   rawDataType,
+		ProfilingType: profilingType,
+	}
+	require.NoError(t, s.params.LocalStore.Create(task).Error)
+	return task
+}
+
+func TestObservedProfilingTypes(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.tikv = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	tikvTarget := model.RequestTargetNode{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180}
+
+	// TiKV only supports CPU profiling, so the heap profiling tasks are skipped.
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{tikvTarget},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap, ProfilingTypeCPU},
+	})
+	require.Len(t, tasks, 2)
+	require.Equal(t, TaskStateSkipped, tasks[0].State)
+	require.Equal(t, SkipReasonUnsupportedProfilingType, tasks[0].SkipReason)
+	require.Equal(t, TaskStateFinish, tasks[1].State)
+	require.Equal(t, SkipReason(""), tasks[1].SkipReason)
+	require.Equal(t, TaskProfilingTypeList{ProfilingTypeCPU}, observedProfilingTypes(tasks))
+
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	tasks, _ = runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			tikvTarget,
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap, ProfilingTypeCPU},
+	})
+	require.Len(t, tasks, 4)
+	require.Equal(t, TaskProfilingTypeList{ProfilingTypeCPU, ProfilingTypeHeap}, observedProfilingTypes(tasks))
+
+	require.Empty(t, observedProfilingTypes(nil))
+}
+
+func TestErrorSummary(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if op.ip == "127.0.0.1" {
+			return content, nil
+		}
+		return nil, fmt.Errorf("no responder found")
+	}}
+	s.fetchers.pd = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return nil, fmt.Errorf("connection refused")
+	}}
+
+	// TiKV only supports CPU profiling, and there is no TiKV client, so all TiKV tasks are skipped.
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.3:4000", IP: "127.0.0.3", Port: 10080},
+			{Kind: model.NodeKindPD, DisplayName: "127.0.0.1:2379", IP: "127.0.0.1", Port: 2379},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap, ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStatePartialFinish, group.State)
+	require.Len(t, tasks, 10)
+	require.Equal(t, map[string]int{
+		"failed to fetch and write to temp file: failed to fetch profile with *.proto format: no responder found": 4,
+		"failed to fetch and write to temp file: failed to fetch profile with *.proto format: connection refused": 2,
+	}, errorSummary(tasks))
+
+	require.Empty(t, errorSummary(nil))
+
+	// Only the failed tasks are listed, while the fields derived from the tasks still cover all tasks.
+	resp, err := s.groupDetail(group.ID, true, []TaskState{TaskStateError})
+	require.NoError(t, err)
+	require.Equal(t, TaskStatePartialFinish, resp.TaskGroup.State)
+	require.Len(t, resp.Tasks, 6)
+	for _, task := range resp.Tasks {
+		require.Equal(t, TaskStateError, task.State)
+	}
+	require.Equal(t, errorSummary(tasks), resp.ErrorSummary)
+	require.Equal(t, observedProfilingTypes(tasks), resp.ObservedProfilingTypes)
+	require.NotEmpty(t, resp.ObservedProfilingTypes)
+
+	resp, err = s.groupDetail(group.ID, true, []TaskState{TaskStateError, TaskStateSkipped})
+	require.NoError(t, err)
+	require.Len(t, resp.Tasks, 8)
+
+	// The derived fields are still filled without the tasks.
+	resp, err = s.groupDetail(group.ID, false, nil)
+	require.NoError(t, err)
+	require.Nil(t, resp.Tasks)
+	require.Equal(t, errorSummary(tasks), resp.ErrorSummary)
+	require.Equal(t, observedProfilingTypes(tasks), resp.ObservedProfilingTypes)
+}
+
+func TestGroupDetail(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	_, err := s.addGroupComment(group.ID, "alice", "slow queries at 10:00")
+	require.NoError(t, err)
+
+	resp, err := s.groupDetail(group.ID, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, group.ID, resp.TaskGroup.ID)
+	require.Equal(t, TaskStateFinish, resp.TaskGroup.State)
+	require.Len(t, resp.Comments, 1)
+	require.Len(t, resp.Tasks, 2)
+	require.Equal(t, tasks[0].ID, resp.Tasks[0].ID)
+	require.Equal(t, TaskProfilingTypeList{ProfilingTypeCPU}, resp.ObservedProfilingTypes)
+	require.Empty(t, resp.ErrorSummary)
+
+	resp, err = s.groupDetail(group.ID, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, *group, resp.TaskGroup)
+	require.Len(t, resp.Comments, 1)
+	require.Nil(t, resp.Tasks)
+	require.Equal(t, TaskProfilingTypeList{ProfilingTypeCPU}, resp.ObservedProfilingTypes)
+	require.Empty(t, resp.ErrorSummary)
+	require.NotNil(t, resp.ErrorSummary)
+}
+
+func TestTaskSnapshotWhileRunning(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		close(started)
+		<-release
+		return content, nil
+	}}
+
+	taskGroup, err := s.startGroup(context.Background(), &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.NoError(t, err)
+	<-started
+	var task *Task
+	s.tasks.Range(func(_, value interface{}) bool {
+		task = value.(*Task)
+		return false
+	})
+	require.NotNil(t, task)
+	require.Equal(t, TaskStateRunning, task.snapshot().State)
+	require.Equal(t, TaskStateRunning, taskGroup.snapshot().State)
+
+	// Snapshots are taken while the task is stopping, which must not race with the task.
+	snapshots := make(chan TaskModel, 1)
+	go func() {
+		defer close(snapshots)
+		for {
+			select {
+			case <-task.stopped:
+				snapshots <- task.snapshot()
+				return
+			default:
+				if m := task.snapshot(); m.State == TaskStateFinish && m.FilePath == "" {
+					// A finished task always has a result, or the snapshot is inconsistent.
+					snapshots <- m
+					return
+				}
+			}
+		}
+	}()
+	close(release)
+	task.wait()
+	m := <-snapshots
+	require.Equal(t, TaskStateFinish, m.State)
+	require.NotEmpty(t, m.FilePath)
+
+	<-taskGroup.done
+	require.Equal(t, TaskStateFinish, taskGroup.snapshot().State)
+	s.wg.Wait()
+}
+
+func TestCancelGroupWaitsForTasks(t *testing.T) {
+	s := newTestService(t)
+	started := make(chan struct{})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		close(started)
+		<-op.ctx.Done()
+		return nil, op.ctx.Err()
+	}}
+
+	taskGroup, err := s.startGroup(context.Background(), &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.NoError(t, err)
+	<-started
+	require.NoError(t, s.cancelGroup(taskGroup.ID))
+
+	// Tasks are saved as cancelled once the task group is cancelled, without waiting for the task group.
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Find(&tasks).Error)
+	require.Len(t, tasks, 1)
+	require.Equal(t, TaskStateCancelled, tasks[0].State)
+	s.wg.Wait()
+}
+
+func TestCancelGroup(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	started := make(chan struct{})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if op.ip == "127.0.0.1" {
+			return content, nil
+		}
+		close(started)
+		<-op.ctx.Done()
+		return nil, op.ctx.Err()
+	}}
+
+	taskGroup, err := s.startGroup(context.Background(), &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.NoError(t, err)
+	<-started
+	// The first task is finished before the task group is cancelled, otherwise it may be cancelled too.
+	require.Eventually(t, func() bool {
+		var finished int64
+		err := s.params.LocalStore.Model(&TaskModel{}).Where("task_group_id = ? AND state = ?", taskGroup.ID, TaskStateFinish).Count(&finished).Error
+		return err == nil && finished == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.cancelGroup(taskGroup.ID))
+	s.wg.Wait()
+
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Order("id ASC").Find(&tasks).Error)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, TaskStateCancelled, tasks[1].State)
+	var group TaskGroupModel
+	require.NoError(t, s.params.LocalStore.Where("id = ?", taskGroup.ID).First(&group).Error)
+	require.Equal(t, TaskStatePartialFinish, group.State)
+
+	// Results of finished tasks are still available.
+	data, err := ioutil.ReadFile(tasks[0].FilePath)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	// Cancelling again is a no-op.
+	require.NoError(t, s.cancelGroup(taskGroup.ID))
+
+	_, finishedGroup := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.Equal(t, TaskStateFinish, finishedGroup.State)
+	err = s.cancelGroup(finishedGroup.ID)
+	require.True(t, errorx.IsOfType(err, ErrGroupNotRunning))
+
+	err = s.cancelGroup(finishedGroup.ID + 100)
+	require.True(t, errorx.IsOfType(err, rest.ErrNotFound))
+}
+
+func TestShutdownCancelsRunningTasks(t *testing.T) {
+	s := newTestService(t)
+	started := make(chan struct{})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		close(started)
+		<-op.ctx.Done()
+		return nil, op.ctx.Err()
+	}}
+
+	taskGroup, err := s.startGroup(context.Background(), &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		DurationSecs:           30,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.NoError(t, err)
+	<-started
+	s.shutdown(context.Background())
+
+	// Everything is saved once shutdown returns.
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Find(&tasks).Error)
+	require.Len(t, tasks, 1)
+	require.Equal(t, TaskStateCancelled, tasks[0].State)
+	require.Equal(t, errCancelledByShutdown, tasks[0].Error)
+	var group TaskGroupModel
+	require.NoError(t, s.params.LocalStore.Where("id = ?", taskGroup.ID).First(&group).Error)
+	require.Equal(t, TaskStateCancelled, group.State)
+}
+
+func TestShutdownIsBounded(t *testing.T) {
+	s := newTestService(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		close(started)
+		// The fetch is not stopped by the cancellation.
+		<-release
+		return nil, op.ctx.Err()
+	}}
+
+	_, err := s.startGroup(context.Background(), &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.NoError(t, err)
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	s.shutdown(ctx)
+	require.Less(t, time.Since(begin), shutdownTimeout)
+
+	close(release)
+	s.wg.Wait()
+}
+
+func TestTaskGroupState(t *testing.T) {
+	require.Equal(t, TaskStateFinish, taskGroupState([]TaskState{TaskStateFinish, TaskStateSkipped}))
+	require.Equal(t, TaskStatePartialFinish, taskGroupState([]TaskState{TaskStateFinish, TaskStateError}))
+	require.Equal(t, TaskStateError, taskGroupState([]TaskState{TaskStateError, TaskStateSkipped}))
+	require.Equal(t, TaskStatePartialFinish, taskGroupState([]TaskState{TaskStateFinish, TaskStateCancelled}))
+	require.Equal(t, TaskStateCancelled, taskGroupState([]TaskState{TaskStateError, TaskStateCancelled}))
+}
+
+func TestHeapProfiling(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	fetchHeap := &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		// Heap profiles are snapshots, so that the duration is not passed.
+		require.Equal(t, "/debug/pprof/heap", op.path)
+		return content, nil
+	}}
+	s.fetchers.tidb = fetchHeap
+	s.fetchers.pd = fetchHeap
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindPD, DisplayName: "127.0.0.1:2379", IP: "127.0.0.1", Port: 2379},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		DurationSecs:           30,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Len(t, tasks, 3)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, RawDataTypeProtobuf, tasks[0].RawDataType)
+	require.Equal(t, TaskStateFinish, tasks[1].State)
+	require.Equal(t, TaskStateSkipped, tasks[2].State)
+	require.Equal(t, SkipReasonUnsupportedProfilingType, tasks[2].SkipReason)
+	for _, task := range tasks {
+		require.Equal(t, float64(1), estimateProgress(&task, group.ProfileDurationSecs, task.StartedAt))
+	}
+}
+
+func TestEstimateProgress(t *testing.T) {
+	heap := &TaskModel{State: TaskStateRunning, ProfilingType: ProfilingTypeHeap, StartedAt: 100}
+	require.Equal(t, float64(0), estimateProgress(heap, 30, 115))
+	heap.State = TaskStateFinish
+	require.Equal(t, float64(1), estimateProgress(heap, 30, 101))
+
+	cpu := &TaskModel{State: TaskStateRunning, ProfilingType: ProfilingTypeCPU, StartedAt: 100}
+	require.Equal(t, float64(0), estimateProgress(cpu, 30, 100))
+	require.Equal(t, 0.5, estimateProgress(cpu, 30, 115))
+	require.Equal(t, 0.99, estimateProgress(cpu, 30, 200))
+	cpu.State = TaskStateError
+	require.Equal(t, float64(1), estimateProgress(cpu, 30, 115))
+
+	queued := &TaskModel{State: TaskStateRunning, ProfilingType: ProfilingTypeCPU}
+	require.Equal(t, float64(0), estimateProgress(queued, 30, 115))
+}
+
+func TestProgressIncreasesMonotonically(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		time.Sleep(2100 * time.Millisecond)
+		return content, nil
+	}}
+
+	// The second task waits for the first one, so its progress must not move before it starts.
+	const durationSecs = 2
+	taskGroup, err := s.startGroup(context.Background(), &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+		},
+		DurationSecs:           durationSecs,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+		MaxConcurrency:         1,
+	})
+	require.NoError(t, err)
+
+	lastProgress := make(map[uint]float64)
+	observeProgress := func() {
+		// The task group is saved in the background, so it is not read here to avoid data races.
+		var tasks []TaskModel
+		require.NoError(t, s.params.LocalStore.Find(&tasks).Error)
+		now := time.Now().Unix()
+		for i := range tasks {
+			progress := estimateProgress(&tasks[i], durationSecs, now)
+			require.GreaterOrEqual(t, progress, lastProgress[tasks[i].ID])
+			if tasks[i].State == TaskStateRunning {
+				require.LessOrEqual(t, progress, 0.99)
+			}
+			lastProgress[tasks[i].ID] = progress
+		}
+	}
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for done := false; !done; {
+		select {
+		case <-taskGroup.done:
+			done = true
+		case <-ticker.C:
+			observeProgress()
+		}
+	}
+	s.wg.Wait()
+	observeProgress()
+
+	require.Len(t, lastProgress, 2)
+	for _, progress := range lastProgress {
+		require.Equal(t, float64(1), progress)
+	}
+}
+
+func TestBlockProfiling(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"runtime.chanrecv1": 10000000})
+	fetchBlock := &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		require.Equal(t, "/debug/pprof/block", op.path)
+		return content, nil
+	}}
+	s.fetchers.tidb = fetchBlock
+	s.fetchers.pd = fetchBlock
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindPD, DisplayName: "127.0.0.1:2379", IP: "127.0.0.1", Port: 2379},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeBlock},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Len(t, tasks, 3)
+	for _, task := range tasks[:2] {
+		require.Equal(t, TaskStateFinish, task.State)
+		require.Equal(t, RawDataTypeProtobuf, task.RawDataType)
+		data, err := ioutil.ReadFile(task.FilePath)
+		require.NoError(t, err)
+		_, err = parseProtobufProfile(data)
+		require.NoError(t, err)
+	}
+	// TiKV does not support block profiling.
+	require.Equal(t, TaskStateSkipped, tasks[2].State)
+	require.Equal(t, SkipReasonUnsupportedProfilingType, tasks[2].SkipReason)
+}
+
+func TestRetryOnTotalFailure(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	var fetched int32
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		// All tasks of the first attempt are failed.
+		if atomic.AddInt32(&fetched, 1) <= 2 {
+			return nil, fmt.Errorf("pd leader is changing")
+		}
+		return content, nil
+	}}
+	req := &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+		RetryOnTotalFailure:    true,
+	}
+
+	_, first := runTestGroup(t, s, req)
+	require.Equal(t, TaskStateError, first.State)
+	require.Zero(t, first.RetryOf)
+
+	var retry TaskGroupModel
+	require.NoError(t, s.params.LocalStore.Where("retry_of = ?", first.ID).First(&retry).Error)
+	require.Equal(t, TaskStateFinish, retry.State)
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", retry.ID).Find(&tasks).Error)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		require.Equal(t, TaskStateFinish, task.State)
+	}
+
+	// A retry is not retried again.
+	atomic.StoreInt32(&fetched, 0)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		atomic.AddInt32(&fetched, 1)
+		return nil, fmt.Errorf("pd leader is changing")
+	}}
+	runTestGroup(t, s, req)
+	require.Equal(t, int32(4), atomic.LoadInt32(&fetched))
+	var count int64
+	require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Count(&count).Error)
+	require.Equal(t, int64(4), count)
+}
+
+func TestDeleteGroup(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+	})
+	_, err := s.addGroupComment(group.ID, "root", "looks good")
+	require.NoError(t, err)
+
+	require.NoError(t, s.deleteGroup(group.ID))
+	var count int64
+	require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Where("id = ?", group.ID).Count(&count).Error)
+	require.Zero(t, count)
+	require.NoError(t, s.params.LocalStore.Model(&TaskModel{}).Where("task_group_id = ?", group.ID).Count(&count).Error)
+	require.Zero(t, count)
+	require.NoError(t, s.params.LocalStore.Model(&TaskGroupCommentModel{}).Where("task_group_id = ?", group.ID).Count(&count).Error)
+	require.Zero(t, count)
+	_, err = os.Stat(tasks[0].FilePath)
+	require.True(t, os.IsNotExist(err))
+
+	err = s.deleteGroup(group.ID)
+	require.True(t, errorx.IsOfType(err, rest.ErrNotFound))
+
+	running := &TaskGroupModel{State: TaskStateRunning}
+	require.NoError(t, s.params.LocalStore.Create(running).Error)
+	err = s.deleteGroup(running.ID)
+	require.True(t, errorx.IsOfType(err, ErrGroupRunning))
+	require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Where("id = ?", running.ID).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+}
+
+func TestDeleteExpiredGroups(t *testing.T) {
+	s := newTestService(t)
+	s.params.Config = &config.Config{ProfilingRetention: time.Hour}
+	now := time.Now()
+	newGroup := func(state TaskState, startedAt time.Time) uint {
+		group := &TaskGroupModel{State: state, StartedAt: startedAt.Unix()}
+		require.NoError(t, s.params.LocalStore.Create(group).Error)
+		task := newTestFinishedTask(t, s, ProfilingTypeCPU, RawDataTypeProtobuf, []byte("profile"))
+		task.TaskGroupID = group.ID
+		require.NoError(t, s.params.LocalStore.Save(task).Error)
+		return group.ID
+	}
+	expiredIDs := make([]uint, 0, janitorBatchSize+1)
+	for i := 0; i < janitorBatchSize+1; i++ {
+		expiredIDs = append(expiredIDs, newGroup(TaskStateFinish, now.Add(-2*time.Hour)))
+	}
+	runningID := newGroup(TaskStateRunning, now.Add(-2*time.Hour))
+	recentID := newGroup(TaskStateFinish, now.Add(-time.Minute))
+
+	deleted, err := s.deleteExpiredGroups(now)
+	require.NoError(t, err)
+	require.Equal(t, len(expiredIDs), deleted)
+
+	var remaining []uint
+	require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Order("id ASC").Pluck("id", &remaining).Error)
+	require.Equal(t, []uint{runningID, recentID}, remaining)
+	var count int64
+	require.NoError(t, s.params.LocalStore.Model(&TaskModel{}).Count(&count).Error)
+	require.Equal(t, int64(2), count)
+
+	// Task groups are kept forever when the retention is not set.
+	oldID := newGroup(TaskStateFinish, now.Add(-365*24*time.Hour))
+	for _, cfg := range []*config.Config{nil, {}, {ProfilingRetention: -time.Hour}} {
+		s.params.Config = cfg
+		require.Zero(t, s.retention())
+		deleted, err = s.deleteExpiredGroups(now)
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+	}
+	require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Where("id = ?", oldID).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+
+	// Task groups are kept for 7 days by default.
+	s.params.Config = config.Default()
+	require.Equal(t, 7*24*time.Hour, s.retention())
+}
+
+func TestMarkInterruptedGroups(t *testing.T) {
+	s := newTestService(t)
+	newGroup := func(groupState TaskState, taskStates ...TaskState) uint {
+		group := &TaskGroupModel{State: groupState}
+		require.NoError(t, s.params.LocalStore.Create(group).Error)
+		for _, state := range taskStates {
+			require.NoError(t, s.params.LocalStore.Create(&TaskModel{TaskGroupID: group.ID, State: state}).Error)
+		}
+		return group.ID
+	}
+	interruptedID := newGroup(TaskStateRunning, TaskStateRunning, TaskStateRunning)
+	partialID := newGroup(TaskStateRunning, TaskStateFinish, TaskStateRunning)
+	stoppedID := newGroup(TaskStateFinish, TaskStateFinish)
+
+	require.NoError(t, s.markInterruptedGroups())
+
+	groupState := func(id uint) TaskState {
+		var group TaskGroupModel
+		require.NoError(t, s.params.LocalStore.Where("id = ?", id).First(&group).Error)
+		return group.State
+	}
+	require.Equal(t, TaskStateError, groupState(interruptedID))
+	require.Equal(t, TaskStatePartialFinish, groupState(partialID))
+	require.Equal(t, TaskStateFinish, groupState(stoppedID))
+
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", partialID).Order("id ASC").Find(&tasks).Error)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Empty(t, tasks[0].Error)
+	require.Equal(t, TaskStateError, tasks[1].State)
+	require.Equal(t, errInterruptedByRestart, tasks[1].Error)
+
+	var count int64
+	require.NoError(t, s.params.LocalStore.Model(&TaskModel{}).Where("state = ?", TaskStateRunning).Count(&count).Error)
+	require.Zero(t, count)
+}
+
+func TestStorageBudget(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	size := int64(len(content))
+	s.params.Config = &config.Config{ProfilingStorageBudget: size * 5 / 2}
+	newRequest := func(numTargets int) *StartRequest {
+		req := &StartRequest{DurationSecs: 1, RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU}}
+		for i := 0; i < numTargets; i++ {
+			req.Targets = append(req.Targets, model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080 + i})
+		}
+		return req
+	}
+	remainingGroups := func() []uint {
+		var ids []uint
+		require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Order("id ASC").Pluck("id", &ids).Error)
+		return ids
+	}
+
+	groupIDs := make([]uint, 0, 3)
+	for i := 0; i < 3; i++ {
+		tasks, group := runTestGroup(t, s, newRequest(1))
+		require.Equal(t, TaskStateFinish, group.State)
+		// The profile is already gzipped, so it is stored as it is.
+		require.Equal(t, size, tasks[0].StoredSizeBytes)
+		groupIDs = append(groupIDs, group.ID)
+	}
+	// The oldest task group is deleted to make room for the third one.
+	require.Equal(t, groupIDs[1:], remainingGroups())
+
+	// Nothing is deleted if there is still no room after deleting all stopped task groups.
+	_, err := s.startGroup(context.Background(), newRequest(3))
+	require.True(t, errorx.IsOfType(err, ErrStorageBudgetExceeded))
+	require.Equal(t, groupIDs[1:], remainingGroups())
+
+	// Storage reserved for the task groups is released once they are stopped or not started.
+	require.Zero(t, s.reservedBytes)
+}
+
+func TestStorageBudgetConcurrentReservations(t *testing.T) {
+	s := newTestService(t)
+	s.params.Config = &config.Config{ProfilingStorageBudget: 100}
+
+	// Concurrent reservations are counted together, even if no results are saved yet.
+	var reserved int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.reserveBytes(30); err == nil {
+				atomic.AddInt32(&reserved, 1)
+			} else {
+				require.True(t, errorx.IsOfType(err, ErrStorageBudgetExceeded))
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(3), reserved)
+	require.Equal(t, int64(90), s.reservedBytes)
+
+	// The storage can be reserved again once it is released.
+	s.releaseStorage(30)
+	bytes, err := s.reserveBytes(30)
+	require.NoError(t, err)
+	require.Equal(t, int64(30), bytes)
+}
+
+func TestMaxRunningGroups(t *testing.T) {
+	s := newTestService(t)
+	s.params.Config = &config.Config{ProfilingMaxRunningGroups: 2}
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	release := make(chan struct{})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		<-release
+		return content, nil
+	}}
+	req := &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := s.startGroup(context.Background(), req)
+		require.NoError(t, err)
+	}
+	_, err := s.startGroup(context.Background(), req)
+	require.True(t, errorx.IsOfType(err, ErrTooManyRunningGroups))
+	var count int64
+	require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Count(&count).Error)
+	require.Equal(t, int64(2), count)
+
+	// Task groups can be started again once the running ones are stopped.
+	close(release)
+	s.wg.Wait()
+	_, group := runTestGroup(t, s, req)
+	require.Equal(t, TaskStateFinish, group.State)
+
+	s.params.Config = nil
+	require.Equal(t, defaultMaxRunningGroups, s.maxRunningGroups())
+}
+
+func TestListGroups(t *testing.T) {
+	s := newTestService(t)
+	ids := make([]uint, 0, 50)
+	for i := 0; i < 50; i++ {
+		group := &TaskGroupModel{State: TaskStateFinish}
+		require.NoError(t, s.params.LocalStore.Create(group).Error)
+		ids = append(ids, group.ID)
+	}
+
+	resp, err := s.listGroups(ListGroupsRequest{Limit: 20, Offset: 20})
+	require.NoError(t, err)
+	require.Equal(t, int64(50), resp.Total)
+	pageIDs := make([]uint, 0, len(resp.Groups))
+	for _, group := range resp.Groups {
+		pageIDs = append(pageIDs, group.ID)
+	}
+	expected := make([]uint, 0, 20)
+	for i := 29; i >= 10; i-- {
+		expected = append(expected, ids[i])
+	}
+	require.Equal(t, expected, pageIDs)
+
+	resp, err = s.listGroups(ListGroupsRequest{Limit: 20, Offset: 40})
+	require.NoError(t, err)
+	require.Len(t, resp.Groups, 10)
+	require.Equal(t, ids[0], resp.Groups[9].ID)
+
+	resp, err = s.listGroups(ListGroupsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Groups, 50)
+	require.Equal(t, ids[49], resp.Groups[0].ID)
+}
+
+func TestSkipTargetsWithoutClient(t *testing.T) {
+	s := newTestService(t)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return newTestCPUProfile(t, map[string]int64{"main.work": 10000000}), nil
+	}}
+
+	// There is no TiFlash client, so that profiles of TiFlash are skipped.
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiFlash, DisplayName: "127.0.0.1:3930", IP: "127.0.0.1", Port: 20292},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Len(t, tasks, 2)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, TaskStateSkipped, tasks[1].State)
+	require.Equal(t, SkipReasonClientNotConfigured, tasks[1].SkipReason)
+	require.Empty(t, tasks[1].Error)
+}
+
+func TestGroupStartedAt(t *testing.T) {
+	s := newTestService(t)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return []byte("goroutine profile: total 1"), nil
+	}}
+	before := time.Now().Unix()
+	_, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeGoroutine},
+	})
+	// The start time is persisted when the task group is created, and kept after it is finished.
+	require.GreaterOrEqual(t, group.StartedAt, before)
+	require.LessOrEqual(t, group.StartedAt, time.Now().Unix())
+
+	resp, err := s.listGroups(ListGroupsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Groups, 1)
+	require.Equal(t, group.StartedAt, resp.Groups[0].StartedAt)
+}
+
+func TestTaskFinishedAt(t *testing.T) {
+	s := newTestService(t)
+	fetching := make(chan struct{})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		close(fetching)
+		// The profile is returned once the profile duration is elapsed, like a real CPU profile.
+		time.Sleep(time.Second)
+		return newTestCPUProfile(t, map[string]int64{"main.work": 100}), nil
+	}}
+	s.fetchers.tikv = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return nil, fmt.Errorf("connection refused")
+	}}
+	taskGroup, err := s.startGroup(context.Background(), &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.NoError(t, err)
+	<-fetching
+	var running TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ? AND kind = ?", taskGroup.ID, model.NodeKindTiDB).First(&running).Error)
+	require.Equal(t, TaskStateRunning, running.State)
+	require.NotZero(t, running.StartedAt)
+	require.Zero(t, running.FinishedAt)
+	s.wg.Wait()
+
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Order("id ASC").Find(&tasks).Error)
+	require.Len(t, tasks, 2)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.GreaterOrEqual(t, tasks[0].FinishedAt, tasks[0].StartedAt)
+	// The timestamps are in seconds, so the elapsed profile duration of 1 second is measured as 1 or 2 seconds.
+	require.InDelta(t, 1.5, float64(tasks[0].FinishedAt-tasks[0].StartedAt), 0.5)
+	// Failed tasks record when they failed.
+	require.Equal(t, TaskStateError, tasks[1].State)
+	require.GreaterOrEqual(t, tasks[1].FinishedAt, tasks[1].StartedAt)
+	require.NotZero(t, tasks[1].FinishedAt)
+}
+
+func TestGroupTriggeredBy(t *testing.T) {
+	s := newTestService(t)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return []byte("goroutine profile: total 1"), nil
+	}}
+	startGroup := func(triggeredBy string) uint {
+		_, group := runTestGroup(t, s, &StartRequest{
+			Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+			RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeGoroutine},
+			triggeredBy:            triggeredBy,
+		})
+		return group.ID
+	}
+	alice := startGroup("alice")
+	bob := startGroup("bob")
+	auto := startGroup("")
+
+	detail, err := s.groupDetail(alice, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, "alice", detail.TaskGroup.TriggeredBy)
+
+	resp, err := s.listGroups(ListGroupsRequest{TriggeredBy: "bob"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), resp.Total)
+	require.Equal(t, bob, resp.Groups[0].ID)
+	require.Equal(t, "bob", resp.Groups[0].TriggeredBy)
+
+	resp, err = s.listGroups(ListGroupsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), resp.Total)
+	require.Equal(t, auto, resp.Groups[0].ID)
+	require.Empty(t, resp.Groups[0].TriggeredBy)
+}
+
+func TestListGroupsWithFilters(t *testing.T) {
+	s := newTestService(t)
+	newGroup := func(state TaskState, profilingTypes ...TaskProfilingType) uint {
+		group := &TaskGroupModel{State: state}
+		require.NoError(t, s.params.LocalStore.Create(group).Error)
+		for _, profilingType := range profilingTypes {
+			require.NoError(t, s.params.LocalStore.Create(&TaskModel{TaskGroupID: group.ID, State: state, ProfilingType: profilingType}).Error)
+		}
+		return group.ID
+	}
+	failedCPU := newGroup(TaskStateError, ProfilingTypeCPU)
+	newGroup(TaskStateError, ProfilingTypeHeap)
+	finishedCPU := newGroup(TaskStateFinish, ProfilingTypeCPU, ProfilingTypeHeap)
+	failedMixed := newGroup(TaskStateError, ProfilingTypeHeap, ProfilingTypeCPU)
+	partialCPU := newGroup(TaskStatePartialFinish, ProfilingTypeCPU)
+
+	groupIDs := func(resp *ListGroupsResponse) []uint {
+		ids := make([]uint, 0, len(resp.Groups))
+		for _, group := range resp.Groups {
+			ids = append(ids, group.ID)
+		}
+		return ids
+	}
+
+	resp, err := s.listGroups(ListGroupsRequest{
+		States:         []TaskState{TaskStateError},
+		ProfilingTypes: []TaskProfilingType{ProfilingTypeCPU},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), resp.Total)
+	require.Equal(t, []uint{failedMixed, failedCPU}, groupIDs(resp))
+
+	resp, err = s.listGroups(ListGroupsRequest{ProfilingTypes: []TaskProfilingType{ProfilingTypeCPU}})
+	require.NoError(t, err)
+	require.Equal(t, []uint{partialCPU, failedMixed, finishedCPU, failedCPU}, groupIDs(resp))
+
+	resp, err = s.listGroups(ListGroupsRequest{States: []TaskState{TaskStateFinish, TaskStatePartialFinish}, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), resp.Total)
+	require.Equal(t, []uint{partialCPU}, groupIDs(resp))
+
+	resp, err = s.listGroups(ListGroupsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), resp.Total)
+}
+
+func TestGoroutineFullProfiling(t *testing.T) {
+	s := newTestService(t)
+	dump := []byte("goroutine 1 [chan receive, 5 minutes]:\nmain.main()\n\t/src/main.go:10 +0x25\n")
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		require.Equal(t, "/debug/pprof/goroutine?debug=2", op.path)
+		return dump, nil
+	}}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeGoroutineFull},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Len(t, tasks, 2)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, RawDataTypeText, tasks[0].RawDataType)
+	require.Equal(t, ".txt", filepath.Ext(tasks[0].FilePath))
+	data, err := s.results.readResult(&tasks[0])
+	require.NoError(t, err)
+	require.Equal(t, dump, data)
+	require.Equal(t, TaskStateSkipped, tasks[1].State)
+	require.Equal(t, SkipReasonUnsupportedProfilingType, tasks[1].SkipReason)
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	var inFlight, maxInFlight, fetched int32
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&fetched, 1)
+		time.Sleep(20 * time.Millisecond)
+		return content, nil
+	}}
+
+	targets := make([]model.RequestTargetNode, 0, 10)
+	for i := 0; i < 10; i++ {
+		ip := fmt.Sprintf("127.0.0.%d", i+1)
+		targets = append(targets, model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: ip + ":4000", IP: ip, Port: 10080})
+	}
+	_, group := runTestGroup(t, s, &StartRequest{
+		Targets:                targets,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+		MaxConcurrency:         3,
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Equal(t, int32(10), atomic.LoadInt32(&fetched))
+	require.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+}
+
+func TestStaggerLaunch(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	var mu sync.Mutex
+	var launchedAt []time.Time
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		mu.Lock()
+		launchedAt = append(launchedAt, time.Now())
+		mu.Unlock()
+		return content, nil
+	}}
+
+	targets := make([]model.RequestTargetNode, 0, 4)
+	for i := 0; i < 4; i++ {
+		ip := fmt.Sprintf("127.0.0.%d", i+1)
+		targets = append(targets, model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: ip + ":4000", IP: ip, Port: 10080})
+	}
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                targets,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+		StaggerMs:              100,
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	for _, task := range tasks {
+		require.NotZero(t, task.StartedAt)
+	}
+	require.Len(t, launchedAt, 4)
+	sort.Slice(launchedAt, func(i, j int) bool { return launchedAt[i].Before(launchedAt[j]) })
+	// Each launch is delayed by at least half of the stagger.
+	for i := 1; i < len(launchedAt); i++ {
+		require.GreaterOrEqual(t, launchedAt[i].Sub(launchedAt[i-1]), 40*time.Millisecond)
+	}
+}
+
+func TestTiProxyProfiling(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	s.fetchers.tiproxy = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		require.Equal(t, "10.0.0.5", op.ip)
+		require.Equal(t, 3080, op.port)
+		return content, nil
+	}}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiProxy, DisplayName: "10.0.0.5:6000", IP: "10.0.0.5", Port: 3080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap, ProfilingTypeGoroutine},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Equal(t, 1, group.TargetStats.NumTiProxyNodes)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		require.Equal(t, TaskStateFinish, task.State)
+	}
+}
+
+func TestTiCDCProfiling(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.ticdc = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		require.Equal(t, "10.0.0.6", op.ip)
+		require.Equal(t, 8300, op.port)
+		return content, nil
+	}}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiCDC, DisplayName: "10.0.0.6:8300", IP: "10.0.0.6", Port: 8300}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Equal(t, 1, group.TargetStats.NumTiCDCNodes)
+	require.Len(t, tasks, 1)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+}
+
+func TestValidateDuration(t *testing.T) {
+	s := newTestService(t)
+	require.NoError(t, s.validateDuration(&StartRequest{DurationSecs: 1}))
+	require.NoError(t, s.validateDuration(&StartRequest{DurationSecs: config.MaxProfilingAutoCollectionDurationSecs}))
+	require.True(t, errorx.IsOfType(s.validateDuration(&StartRequest{DurationSecs: config.MaxProfilingAutoCollectionDurationSecs + 1}), rest.ErrBadRequest))
+	// The duration is required.
+	require.True(t, errorx.IsOfType(s.validateDuration(&StartRequest{}), rest.ErrBadRequest))
+
+	s.params.Config = &config.Config{ProfilingMaxDurationSecs: 10}
+	require.NoError(t, s.validateDuration(&StartRequest{DurationSecs: 10}))
+	require.True(t, errorx.IsOfType(s.validateDuration(&StartRequest{DurationSecs: 11}), rest.ErrBadRequest))
+	require.True(t, errorx.IsOfType(s.validateDuration(&StartRequest{DurationSecs: 0}), rest.ErrBadRequest))
+
+	// Durations of profiling types are checked as well.
+	require.NoError(t, s.validateDuration(&StartRequest{DurationSecs: 5, DurationSecsByType: map[TaskProfilingType]uint{ProfilingTypeTrace: 10}}))
+	for _, durations := range []map[TaskProfilingType]uint{
+		{ProfilingTypeTrace: 11},
+		{ProfilingTypeTrace: 0},
+		{"unknown": 1},
+	} {
+		require.True(t, errorx.IsOfType(s.validateDuration(&StartRequest{DurationSecs: 5, DurationSecsByType: durations}), rest.ErrBadRequest))
+	}
+	require.NoError(t, s.validateDuration(&StartRequest{DurationSecs: 5, DurationSecsList: []uint{5, 10}}))
+	for _, durations := range [][]uint{{5, 11}, {0, 5}, {5, 5}} {
+		require.True(t, errorx.IsOfType(s.validateDuration(&StartRequest{DurationSecs: 5, DurationSecsList: durations}), rest.ErrBadRequest))
+	}
+}
+
+func TestDurationSecsByType(t *testing.T) {
+	s := newTestService(t)
+	var mu sync.Mutex
+	paths := make(map[string]bool)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		mu.Lock()
+		paths[op.path] = true
+		mu.Unlock()
+		return newTestCPUProfile(t, map[string]int64{"main.work": 10000000}), nil
+	}}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap, ProfilingTypeCPU, ProfilingTypeTrace},
+		DurationSecsByType:     map[TaskProfilingType]uint{ProfilingTypeTrace: 2},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Equal(t, uint(1), group.ProfileDurationSecs)
+	require.Len(t, tasks, 3)
+	require.Equal(t, uint(1), tasks[1].DurationSecs)
+	require.Equal(t, uint(2), tasks[2].DurationSecs)
+	// The heap profile is captured instantly regardless of the duration.
+	require.Equal(t, map[string]bool{
+		"/debug/pprof/heap":              true,
+		"/debug/pprof/profile?seconds=1": true,
+		"/debug/pprof/trace?seconds=2":   true,
+	}, paths)
+
+	// Tasks saved by earlier versions fall back to the duration of the task group.
+	require.Equal(t, uint(3), (&TaskModel{}).profileDurationSecs(3))
+}
+
+func TestDurationSecsList(t *testing.T) {
+	s := newTestService(t)
+	var mu sync.Mutex
+	paths := make(map[string]bool)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		mu.Lock()
+		paths[op.path] = true
+		mu.Unlock()
+		return newTestCPUProfile(t, map[string]int64{"main.work": 10000000}), nil
+	}}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+		},
+		DurationSecs:           1,
+		DurationSecsList:       []uint{1, 2},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU, ProfilingTypeHeap},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	// Each target has a CPU profile per duration, and a single heap profile.
+	require.Len(t, tasks, 6)
+	durationsByTarget := make(map[string][]uint)
+	for _, task := range tasks {
+		if task.ProfilingType == ProfilingTypeCPU {
+			durationsByTarget[task.Target.IP] = append(durationsByTarget[task.Target.IP], task.DurationSecs)
+		}
+	}
+	require.Equal(t, map[string][]uint{"127.0.0.1": {1, 2}, "127.0.0.2": {1, 2}}, durationsByTarget)
+	require.Equal(t, map[string]bool{
+		"/debug/pprof/heap":              true,
+		"/debug/pprof/profile?seconds=1": true,
+		"/debug/pprof/profile?seconds=2": true,
+	}, paths)
+
+	// The profiles of the same target are exported without overwriting each other.
+	files, err := s.groupExportFiles(group.ID, ExportFilter{})
+	require.NoError(t, err)
+	names := make(map[string]struct{}, len(files))
+	for _, file := range files {
+		names[file.name] = struct{}{}
+	}
+	require.Len(t, names, 6)
+
+	// The durations are kept by a clone.
+	startReq, err := s.cloneGroupRequest(group.ID, CloneGroupRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []uint{1, 2}, startReq.DurationSecsList)
+	require.Empty(t, startReq.DurationSecsByType)
+}
+
+func TestCheckProfilingTypes(t *testing.T) {
+	s := newTestService(t)
+	target := model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}
+
+	for _, req := range []*StartRequest{
+		{Targets: []model.RequestTargetNode{target}},
+		{Targets: []model.RequestTargetNode{target}, RequstedProfilingTypes: TaskProfilingTypeList{}},
+		{Targets: []model.RequestTargetNode{target}, RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU, "bogus"}},
+		{
+			Targets:                []model.RequestTargetNode{target},
+			RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+			ProfilingTypesByKind:   map[model.NodeKind]TaskProfilingTypeList{model.NodeKindTiDB: {"bogus"}},
+		},
+	} {
+		require.True(t, errorx.IsOfType(checkProfilingTypes(req), rest.ErrBadRequest))
+		_, err := s.startGroup(context.Background(), req)
+		require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+	}
+	require.Contains(t, checkProfilingTypes(&StartRequest{RequstedProfilingTypes: TaskProfilingTypeList{"bogus"}}).Error(), `"bogus"`)
+
+	// Targets of a kind without profiling types are rejected instead of getting no task.
+	mixedKinds := &StartRequest{
+		Targets: []model.RequestTargetNode{
+			target,
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		DurationSecs:         1,
+		ProfilingTypesByKind: map[model.NodeKind]TaskProfilingTypeList{model.NodeKindTiDB: {ProfilingTypeCPU}},
+	}
+	err := checkProfilingTypes(mixedKinds)
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+	require.Contains(t, err.Error(), fmt.Sprintf("%q", model.NodeKindTiKV))
+	_, err = s.startGroup(context.Background(), mixedKinds)
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+
+	// No task group is saved for rejected requests.
+	var count int64
+	require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Count(&count).Error)
+	require.Equal(t, int64(0), count)
+
+	require.NoError(t, checkProfilingTypes(&StartRequest{RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU}}))
+	require.NoError(t, checkProfilingTypes(&StartRequest{
+		ProfilingTypesByKind: map[model.NodeKind]TaskProfilingTypeList{model.NodeKindTiDB: {ProfilingTypeHeap}},
+	}))
+}
+
+func TestProfilingTypesByKind(t *testing.T) {
+	s := newTestService(t)
+	cpuProfile := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	heapProfile := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	mf := &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		if strings.HasPrefix(op.path, "/debug/pprof/heap") {
+			return heapProfile, nil
+		}
+		return cpuProfile, nil
+	}}
+	s.fetchers.tidb = mf
+	s.fetchers.tikv = mf
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU, ProfilingTypeHeap},
+		ProfilingTypesByKind: map[model.NodeKind]TaskProfilingTypeList{
+			// Unsupported profiling types in the override are still skipped.
+			model.NodeKindTiKV: {ProfilingTypeCPU, ProfilingTypeGoroutine},
+		},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+
+	type cell struct {
+		kind          model.NodeKind
+		profilingType TaskProfilingType
+		state         TaskState
+	}
+	matrix := make([]cell, 0, len(tasks))
+	for _, task := range tasks {
+		matrix = append(matrix, cell{task.Target.Kind, task.ProfilingType, task.State})
+	}
+	require.Equal(t, []cell{
+		{model.NodeKindTiDB, ProfilingTypeCPU, TaskStateFinish},
+		{model.NodeKindTiDB, ProfilingTypeHeap, TaskStateFinish},
+		{model.NodeKindTiKV, ProfilingTypeCPU, TaskStateFinish},
+		{model.NodeKindTiKV, ProfilingTypeGoroutine, TaskStateSkipped},
+	}, matrix)
+}
+
+func TestProfileSize(t *testing.T) {
+	s := newTestService(t)
+	content := newTestHeapProfile(t, map[string]int64{"main.alloc": 10})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	s.fetchers.tikv = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return nil, fmt.Errorf("connection refused")
+	}}
+
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap, ProfilingTypeCPU},
+	})
+	require.Len(t, tasks, 4)
+	for _, task := range tasks {
+		if task.State != TaskStateFinish {
+			// Failed and skipped tasks have no result.
+			require.Equal(t, int64(0), task.SizeBytes)
+			continue
+		}
+		stored, err := s.results.readResult(&task)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(stored)), task.SizeBytes)
+		require.Equal(t, int64(len(content)), task.SizeBytes)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	s := newTestService(t)
+	core, logs := observer.New(zap.InfoLevel)
+	s.logger = zap.New(core)
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return newTestCPUProfile(t, map[string]int64{"main.work": 10}), nil
+	}}
+	s.fetchers.tikv = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return nil, fmt.Errorf("no responder found")
+	}}
+
+	_, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+
+	// All logs are tagged with the task group ID.
+	for _, entry := range logs.All() {
+		require.Equal(t, uint64(group.ID), entry.ContextMap()["task_group_id"], entry.Message)
+	}
+	require.Equal(t, 1, logs.FilterMessage("profiling task group started").Len())
+	require.Equal(t, 1, logs.FilterMessage("profiling task stopped").Len())
+	require.Equal(t, 1, logs.FilterMessage("profiling task group stopped").Len())
+	failed := logs.FilterMessage("profiling task failed").All()
+	require.Len(t, failed, 1)
+	require.Equal(t, zap.WarnLevel, failed[0].Level)
+	require.Equal(t, "tikv(127.0.0.1:20160)", failed[0].ContextMap()["target"])
+	require.Contains(t, failed[0].ContextMap()["error"], "no responder found")
+}
+
+func TestAllocsProfiling(t *testing.T) {
+	s := newTestService(t)
+	heap := newTestHeapProfile(t, map[string]int64{"main.cache.Put": 10})
+	allocs := newTestHeapProfile(t, map[string]int64{"main.buildRequest": 1000})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		switch op.path {
+		case "/debug/pprof/heap":
+			return heap, nil
+		case "/debug/pprof/allocs":
+			return allocs, nil
+		}
+		return nil, fmt.Errorf("unexpected path %s", op.path)
+	}}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.2:4000", IP: "127.0.0.2", Port: 10080},
+			{Kind: model.NodeKindTiKV, DisplayName: "127.0.0.1:20160", IP: "127.0.0.1", Port: 20180},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap, ProfilingTypeAllocs},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Len(t, tasks, 6)
+	tidbTasks := map[string]map[TaskProfilingType][]byte{}
+	for _, task := range tasks {
+		if task.Target.Kind != model.NodeKindTiDB {
+			// TiKV supports neither heap nor allocs profiles.
+			require.Equal(t, TaskStateSkipped, task.State)
+			require.Equal(t, SkipReasonUnsupportedProfilingType, task.SkipReason)
+			continue
+		}
+		require.Equal(t, TaskStateFinish, task.State)
+		require.Equal(t, RawDataTypeProtobuf, task.RawDataType)
+		data, err := s.results.readResult(&task)
+		require.NoError(t, err)
+		if tidbTasks[task.Target.DisplayName] == nil {
+			tidbTasks[task.Target.DisplayName] = map[TaskProfilingType][]byte{}
+		}
+		tidbTasks[task.Target.DisplayName][task.ProfilingType] = data
+	}
+	require.Len(t, tidbTasks, 2)
+	for _, profiles := range tidbTasks {
+		require.Equal(t, map[TaskProfilingType][]byte{ProfilingTypeHeap: heap, ProfilingTypeAllocs: allocs}, profiles)
+	}
+}
+
+func TestDuplicatedTargets(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	var fetched int32
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		atomic.AddInt32(&fetched, 1)
+		return content, nil
+	}}
+	tidbTarget := model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{tidbTarget, tidbTarget},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU, ProfilingTypeHeap},
+	})
+	require.Equal(t, TaskStateFinish, group.State)
+	require.Equal(t, 1, group.TargetStats.NumTiDBNodes)
+	require.Len(t, tasks, 2)
+	require.Equal(t, ProfilingTypeCPU, tasks[0].ProfilingType)
+	require.Equal(t, ProfilingTypeHeap, tasks[1].ProfilingType)
+	require.Equal(t, int32(2), atomic.LoadInt32(&fetched))
+
+	// Targets with the same address but different kinds are different targets.
+	require.Len(t, uniqueTargets([]model.RequestTargetNode{
+		tidbTarget,
+		{Kind: model.NodeKindTiProxy, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080},
+		tidbTarget,
+	}), 2)
+}
+
+func TestResultDir(t *testing.T) {
+	s := newTestService(t)
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+	req := &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	}
+
+	tasks, _ := runTestGroup(t, s, req)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, filepath.Clean(os.TempDir()), filepath.Dir(tasks[0].FilePath))
+
+	// The result directory is created if it does not exist.
+	s.fetchers.resultDir = filepath.Join(t.TempDir(), "profiling")
+	tasks, _ = runTestGroup(t, s, req)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, s.fetchers.resultDir, filepath.Dir(tasks[0].FilePath))
+	data, err := s.results.readResult(&tasks[0])
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
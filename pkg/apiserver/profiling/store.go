@@ -0,0 +1,148 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+)
+
+var ErrResultStoreFailed = ErrNS.NewType("result_store_failed")
+
+// resultStore stores profiling results out of the local filesystem, e.g. in an object storage, so that the
+// dashboard does not need to keep any result locally.
+type resultStore interface {
+	put(key string, content []byte) error
+	get(key string) ([]byte, error)
+	remove(key string) error
+}
+
+// resultStorageKey returns the key of a result in the result store, which is unique even if the task is
+// profiled again, since the result file name is unique.
+func resultStorageKey(taskID uint, path string) string {
+	return fmt.Sprintf("%d/%s", taskID, filepath.Base(path))
+}
+
+// resultFiles saves and reads the result files of tasks. A new result is compressed, encrypted by the cipher, and
+// then uploaded to the store if it is set, or kept in the local file otherwise. Existing results are read as they
+// are saved, regardless of the current configuration.
+type resultFiles struct {
+	cipher *resultCipher
+	store  resultStore // New results are uploaded to the store if it is set, instead of being kept locally
+	// The codec to compress new results, which is gzip if it is empty. Existing results are decompressed with the
+	// codecs they are saved with.
+	codec string
+}
+
+func newResultFiles(cfg *config.Config) (*resultFiles, error) {
+	rc, err := newResultCipher(cfg)
+	if err != nil {
+		return nil, err
+	}
+	r := &resultFiles{cipher: rc}
+	if cfg == nil {
+		return r, nil
+	}
+	switch cfg.ProfilingCompressionCodec {
+	case "", compressionCodecGzip, compressionCodecZstd, compressionCodecNone:
+		r.codec = cfg.ProfilingCompressionCodec
+	default:
+		return nil, ErrUnsupportedCompressionCodec.New("unsupported profiling compression codec %q, expect %s, %s or %s",
+			cfg.ProfilingCompressionCodec, compressionCodecGzip, compressionCodecZstd, compressionCodecNone)
+	}
+	if cfg.ProfilingS3 != nil {
+		store, err := newS3Store(cfg.ProfilingS3)
+		if err != nil {
+			return nil, err
+		}
+		r.store = store
+	}
+	return r, nil
+}
+
+// uploadFile uploads a result file to the result store with the key and removes the local file. The file is kept
+// locally and false is returned if no result store is configured.
+func (r *resultFiles) uploadFile(path string, key string) (bool, error) {
+	if r == nil || r.store == nil {
+		return false, nil
+	}
+	content, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return false, err
+	}
+	if err := r.store.put(key, content); err != nil {
+		return false, err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// readRaw reads the stored content of a result, which is read from the result store if the storage key is not
+// empty, or from the local file otherwise.
+func (r *resultFiles) readRaw(path string, storageKey string) ([]byte, error) {
+	if storageKey == "" {
+		return ioutil.ReadFile(filepath.Clean(path))
+	}
+	if r == nil || r.store == nil {
+		return nil, ErrResultStoreFailed.New("profiling result %s is in a result store which is not configured", storageKey)
+	}
+	return r.store.get(storageKey)
+}
+
+// removeResult removes a result from the result store or the local filesystem. Results already removed are ignored.
+func (r *resultFiles) removeResult(path string, storageKey string) error {
+	if storageKey == "" {
+		if path == "" {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if r == nil || r.store == nil {
+		return ErrResultStoreFailed.New("profiling result %s is in a result store which is not configured", storageKey)
+	}
+	return r.store.remove(storageKey)
+}
+
+// encryptFile encrypts a new result file in place if encryption is enabled, and returns the ID of the key used.
+func (r *resultFiles) encryptFile(path string) (string, error) {
+	if r == nil {
+		return "", nil
+	}
+	return r.cipher.encryptFile(path)
+}
+
+// readFile reads a result file, which is decrypted with the given key if the key ID is not empty.
+func (r *resultFiles) readFile(path string, storageKey string, keyID string) ([]byte, error) {
+	content, err := r.readRaw(path, storageKey)
+	if err != nil {
+		return nil, err
+	}
+	var rc *resultCipher
+	if r != nil {
+		rc = r.cipher
+	}
+	return rc.decrypt(content, keyID)
+}
+
+// openFile opens a result file for streaming. Encrypted files and files in the result store are read in memory.
+func (r *resultFiles) openFile(path string, storageKey string, keyID string) (io.ReadCloser, error) {
+	if keyID == "" && storageKey == "" {
+		return os.Open(filepath.Clean(path))
+	}
+	content, err := r.readFile(path, storageKey, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(content)), nil
+}
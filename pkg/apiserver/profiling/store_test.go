@@ -0,0 +1,165 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+)
+
+type memResultStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (m *memResultStore) put(key string, content []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = content
+	return nil
+}
+
+func (m *memResultStore) get(key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	content, ok := m.objects[key]
+	if !ok {
+		return nil, ErrResultStoreFailed.New("%s not found", key)
+	}
+	return content, nil
+}
+
+func (m *memResultStore) remove(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func TestResultStore(t *testing.T) {
+	s := newTestService(t)
+	store := &memResultStore{objects: make(map[string][]byte)}
+	s.results = &resultFiles{store: store}
+	content := newTestCPUProfile(t, map[string]int64{"main.work": 10000000})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return content, nil
+	}}
+
+	tasks, group := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, resultStorageKey(tasks[0].ID, tasks[0].FilePath), tasks[0].StorageKey)
+	require.Equal(t, content, store.objects[tasks[0].StorageKey])
+
+	// The result is not kept locally.
+	_, err := os.Stat(tasks[0].FilePath)
+	require.True(t, os.IsNotExist(err))
+
+	data, err := s.results.readResult(&tasks[0])
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	require.NoError(t, s.deleteGroup(group.ID))
+	require.Empty(t, store.objects)
+
+	// Results in the store cannot be read once the store is no longer configured.
+	s.results = &resultFiles{}
+	_, err = s.results.readResult(&tasks[0])
+	require.True(t, errorx.IsOfType(err, ErrResultStoreFailed))
+}
+
+type failingResultStore struct {
+	memResultStore
+}
+
+func (f *failingResultStore) put(key string, content []byte) error {
+	return ErrResultStoreFailed.New("failed to upload %s", key)
+}
+
+func TestFailedUploadRemovesResult(t *testing.T) {
+	s := newTestService(t)
+	s.results = &resultFiles{store: &failingResultStore{}}
+	s.fetchers.resultDir = t.TempDir()
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return newTestCPUProfile(t, map[string]int64{"main.work": 10000000}), nil
+	}}
+
+	tasks, _ := runTestGroup(t, s, &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindTiDB, DisplayName: "127.0.0.1:4000", IP: "127.0.0.1", Port: 10080}},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.Equal(t, TaskStateError, tasks[0].State)
+	require.Empty(t, tasks[0].FilePath)
+
+	// The fetched file is not left in the result directory, since no task points at it.
+	files, err := ioutil.ReadDir(s.fetchers.resultDir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestS3Store(t *testing.T) {
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := ioutil.ReadAll(r.Body)
+			objects[r.URL.EscapedPath()] = body
+		case http.MethodGet:
+			body, ok := objects[r.URL.EscapedPath()]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(body)
+		case http.MethodDelete:
+			delete(objects, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	store, err := newS3Store(&config.ProfilingS3Config{
+		Endpoint:        server.URL,
+		Bucket:          "bucket",
+		Prefix:          "profiling/",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.put("1/cpu result.proto", []byte("content")))
+	require.Contains(t, objects, "/bucket/profiling/1/cpu%20result.proto")
+	data, err := store.get("1/cpu result.proto")
+	require.NoError(t, err)
+	require.Equal(t, []byte("content"), data)
+
+	require.NoError(t, store.remove("1/cpu result.proto"))
+	_, err = store.get("1/cpu result.proto")
+	require.True(t, errorx.IsOfType(err, ErrResultStoreFailed))
+
+	for _, authorization := range authorizations {
+		require.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/"))
+		require.Contains(t, authorization, "/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=")
+	}
+
+	_, err = newS3Store(&config.ProfilingS3Config{Endpoint: server.URL})
+	require.True(t, errorx.IsOfType(err, ErrResultStoreFailed))
+}
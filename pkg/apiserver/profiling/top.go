@@ -0,0 +1,103 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"sort"
+
+	"github.com/pingcap/tidb-dashboard/util/rest"
+)
+
+const defaultTopN = 30
+
+type TopFunction struct {
+	Function string  `json:"function"`
+	Flat     int64   `json:"flat"`
+	Share    float64 `json:"share"` // Share of the total value, in range [0, 1]
+}
+
+type GroupTopResponse struct {
+	ProfilingType TaskProfilingType `json:"profiling_type"`
+	SampleType    string            `json:"sample_type"`
+	NumProfiles   int               `json:"num_profiles"`
+	Total         int64             `json:"total"`
+	Functions     []TopFunction     `json:"functions"`
+}
+
+// topOfGroup aggregates the flat value of functions across all finished profiles of a type in a task group,
+// and returns the top N functions. Profiles are parsed in parallel.
+func (s *Service) topOfGroup(taskGroupID uint, profilingType TaskProfilingType, topN int) (*GroupTopResponse, error) {
+	var tasks []TaskModel
+	err := s.params.LocalStore.
+		Where("task_group_id = ? AND state = ? AND profiling_type = ? AND raw_data_type = ?", taskGroupID, TaskStateFinish, profilingType, RawDataTypeProtobuf).
+		Order("id ASC").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, rest.ErrNotFound.New("no finished %s profile in task group %d", profilingType, taskGroupID)
+	}
+	return topOfProfiles(s.results, tasks, topN, analyzeConcurrency)
+}
+
+func topOfProfiles(results *resultFiles, tasks []TaskModel, topN int, workers int) (*GroupTopResponse, error) {
+	sampleTypes := make([]string, len(tasks))
+	flats := make([]map[string]int64, len(tasks))
+	err := forEachParallel(len(tasks), workers, func(i int) error {
+		content, err := results.readResult(&tasks[i])
+		if err != nil {
+			return err
+		}
+		p, err := parseProtobufProfile(content)
+		if err != nil {
+			return err
+		}
+		idx := defaultSampleIndex(p)
+		sampleTypes[i] = p.SampleType[idx].Type
+		flats[i], _ = flatByFunction(p, idx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Aggregate in the order of tasks, so that the result does not depend on the parallelism.
+	aggregated := make(map[string]int64)
+	var total int64
+	for i, flat := range flats {
+		if sampleTypes[i] != sampleTypes[0] {
+			return nil, ErrUnsupportedProfilingType.New("cannot aggregate %s samples with %s samples", sampleTypes[i], sampleTypes[0])
+		}
+		for fn, v := range flat {
+			aggregated[fn] += v
+			total += v
+		}
+	}
+
+	functions := make([]TopFunction, 0, len(aggregated))
+	for fn, v := range aggregated {
+		share := 0.0
+		if total != 0 {
+			share = float64(v) / float64(total)
+		}
+		functions = append(functions, TopFunction{Function: fn, Flat: v, Share: share})
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].Flat != functions[j].Flat {
+			return functions[i].Flat > functions[j].Flat
+		}
+		return functions[i].Function < functions[j].Function
+	})
+	if topN > 0 && len(functions) > topN {
+		functions = functions[:topN]
+	}
+
+	return &GroupTopResponse{
+		ProfilingType: tasks[0].ProfilingType,
+		SampleType:    sampleTypes[0],
+		NumProfiles:   len(tasks),
+		Total:         total,
+		Functions:     functions,
+	}, nil
+}
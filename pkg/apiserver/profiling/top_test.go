@@ -0,0 +1,102 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachParallelUsesMultipleWorkers(t *testing.T) {
+	const workers = 4
+	var running, maxRunning int32
+	var mu sync.Mutex
+	results := make([]int, 16)
+	err := forEachParallel(len(results), workers, func(i int) error {
+		n := atomic.AddInt32(&running, 1)
+		mu.Lock()
+		if n > maxRunning {
+			maxRunning = n
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		results[i] = i * i
+		return nil
+	})
+	require.NoError(t, err)
+	require.Greater(t, maxRunning, int32(1))
+	require.LessOrEqual(t, maxRunning, int32(workers))
+	for i, v := range results {
+		require.Equal(t, i*i, v)
+	}
+}
+
+func TestForEachParallelReturnsError(t *testing.T) {
+	var calls int32
+	err := forEachParallel(8, 3, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 5 {
+			return fmt.Errorf("failed at %d", i)
+		}
+		return nil
+	})
+	require.EqualError(t, err, "failed at 5")
+	require.Equal(t, int32(8), calls)
+}
+
+func TestTopOfProfilesParallelMatchesSerial(t *testing.T) {
+	s := newTestService(t)
+	tasks := make([]TaskModel, 0, 20)
+	for i := 0; i < 20; i++ {
+		content := newTestCPUProfile(t, map[string]int64{
+			"runtime.mallocgc":           int64(i+1) * 10000000,
+			"github.com/pingcap/tidb.Do": int64(20-i) * 10000000,
+			fmt.Sprintf("fn%d", i%3):     30000000,
+		})
+		tasks = append(tasks, *newTestFinishedTask(t, s, ProfilingTypeCPU, RawDataTypeProtobuf, content))
+	}
+
+	serial, err := topOfProfiles(nil, tasks, 0, 1)
+	require.NoError(t, err)
+	parallel, err := topOfProfiles(nil, tasks, 0, 8)
+	require.NoError(t, err)
+	require.Equal(t, serial, parallel)
+
+	require.Equal(t, 20, serial.NumProfiles)
+	require.Equal(t, "cpu", serial.SampleType)
+	require.Equal(t, int64(20*21*10000000+20*30000000), serial.Total)
+	require.Len(t, serial.Functions, 5)
+	// runtime.mallocgc and tidb.Do have the same flat value, so they are ordered by name.
+	require.Equal(t, "github.com/pingcap/tidb.Do", serial.Functions[0].Function)
+	require.Equal(t, "runtime.mallocgc", serial.Functions[1].Function)
+	require.Equal(t, int64(210*10000000), serial.Functions[1].Flat)
+
+	top, err := topOfProfiles(nil, tasks, 2, 8)
+	require.NoError(t, err)
+	require.Equal(t, serial.Functions[:2], top.Functions)
+}
+
+func TestTopOfGroup(t *testing.T) {
+	s := newTestService(t)
+	group := &TaskGroupModel{State: TaskStateFinish}
+	require.NoError(t, s.params.LocalStore.Create(group).Error)
+	for i := 0; i < 3; i++ {
+		task := newTestFinishedTask(t, s, ProfilingTypeCPU, RawDataTypeProtobuf, newTestCPUProfile(t, map[string]int64{"main.work": 10000000}))
+		task.TaskGroupID = group.ID
+		require.NoError(t, s.params.LocalStore.Save(task).Error)
+	}
+
+	resp, err := s.topOfGroup(group.ID, ProfilingTypeCPU, defaultTopN)
+	require.NoError(t, err)
+	require.Equal(t, 3, resp.NumProfiles)
+	require.Equal(t, []TopFunction{{Function: "main.work", Flat: 30000000, Share: 1}}, resp.Functions)
+
+	_, err = s.topOfGroup(group.ID, ProfilingTypeHeap, defaultTopN)
+	require.Error(t, err)
+}
@@ -0,0 +1,351 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+	"github.com/pingcap/tidb-dashboard/util/topo"
+)
+
+var (
+	ErrTopologyUnavailable = ErrNS.NewType("topology_unavailable")
+	ErrTargetNotInTopology = ErrNS.NewType("target_not_in_topology")
+	ErrUnknownStoreID      = ErrNS.NewType("unknown_store_id")
+)
+
+// profilingTargetKinds are the kinds of components which can be profiled.
+var profilingTargetKinds = []topo.Kind{topo.KindTiDB, topo.KindTiKV, topo.KindPD, topo.KindTiFlash, topo.KindTiProxy, topo.KindTiCDC, topo.KindPDTSO, topo.KindPDScheduling}
+
+func isProfilingTargetKind(kind topo.Kind) bool {
+	for _, k := range profilingTargetKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// profilingPort returns the port serving profiles of the component.
+// Profiles are fetched from the status port, except for PD which serves profiles on its client port.
+func profilingPort(info topo.CompInfo) uint {
+	if info.Kind == topo.KindPD {
+		return info.Port
+	}
+	return info.StatusPort
+}
+
+// topologyAddr returns the address used to identify the profiling target in the topology.
+func topologyAddr(info topo.CompInfo) string {
+	return fmt.Sprintf("%s:%d", info.IP, profilingPort(info))
+}
+
+type ListTargetsRequest struct {
+	// Only list components of these kinds if not empty, so that the topology of other kinds is not fetched.
+	Kinds []topo.Kind `json:"kinds" form:"kinds"`
+}
+
+// listTargets returns the components in the cluster topology which can be profiled.
+func (s *Service) listTargets(ctx context.Context, req ListTargetsRequest) ([]model.RequestTargetNode, error) {
+	kinds, err := requestedTargetKinds(req.Kinds)
+	if err != nil {
+		return nil, err
+	}
+	if s.topoProvider == nil {
+		return nil, ErrTopologyUnavailable.New("topology provider is not available")
+	}
+	targets := make([]model.RequestTargetNode, 0)
+	for _, kind := range kinds {
+		kindTargets, ok := s.targetsCache.get(kind)
+		if !ok {
+			if kindTargets, err = s.fetchTargets(ctx, kind); err != nil {
+				return nil, err
+			}
+		}
+		targets = append(targets, kindTargets...)
+	}
+	return targets, nil
+}
+
+type ResolveTargetsRequest struct {
+	Kinds []topo.Kind `json:"kinds" form:"kinds"`
+}
+
+// resolveTargets returns all current components of the requested kinds, which can be passed as the targets of
+// a StartRequest as they are. Unlike listTargets, the topology is always fetched rather than read from the cache,
+// so that the targets are not rejected by CheckTopology once a component is removed.
+func (s *Service) resolveTargets(ctx context.Context, req ResolveTargetsRequest) ([]model.RequestTargetNode, error) {
+	if len(req.Kinds) == 0 {
+		return nil, rest.ErrBadRequest.New("kinds to resolve are not specified")
+	}
+	kinds, err := requestedTargetKinds(req.Kinds)
+	if err != nil {
+		return nil, err
+	}
+	if s.topoProvider == nil {
+		return nil, ErrTopologyUnavailable.New("topology provider is not available")
+	}
+	targets := make([]model.RequestTargetNode, 0)
+	for _, kind := range kinds {
+		kindTargets, err := s.fetchTargets(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, kindTargets...)
+	}
+	return targets, nil
+}
+
+// requestedTargetKinds returns the kinds to list targets of, which are all kinds that can be profiled if none is
+// requested. Targets are listed in the same order regardless of the order of the requested kinds.
+func requestedTargetKinds(requestedKinds []topo.Kind) ([]topo.Kind, error) {
+	if len(requestedKinds) == 0 {
+		return profilingTargetKinds, nil
+	}
+	requested := make(map[topo.Kind]struct{}, len(requestedKinds))
+	for _, kind := range requestedKinds {
+		if !isProfilingTargetKind(kind) {
+			return nil, rest.ErrBadRequest.New("%s cannot be profiled", kind)
+		}
+		requested[kind] = struct{}{}
+	}
+	kinds := make([]topo.Kind, 0, len(requested))
+	for _, kind := range profilingTargetKinds {
+		if _, ok := requested[kind]; ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds, nil
+}
+
+// fetchTargets fetches the targets of a kind from the cluster topology, which are also cached.
+func (s *Service) fetchTargets(ctx context.Context, kind topo.Kind) ([]model.RequestTargetNode, error) {
+	infos, err := topo.GetInfoByKind(ctx, s.topoProvider, kind)
+	if err != nil {
+		return nil, ErrTopologyUnavailable.Wrap(err, "failed to fetch %s topology", kind)
+	}
+	targets := make([]model.RequestTargetNode, 0, len(infos))
+	for _, info := range infos {
+		if info.Status == topo.CompStatusTombstone {
+			continue
+		}
+		targets = append(targets, model.RequestTargetNode{
+			Kind:        model.NodeKind(kind),
+			DisplayName: fmt.Sprintf("%s:%d", info.IP, info.Port),
+			IP:          info.IP,
+			Port:        int(profilingPort(info)),
+		})
+	}
+	s.targetsCache.set(kind, targets)
+	return targets, nil
+}
+
+const defaultTargetsCacheTTL = 5 * time.Second
+
+// targetsCache caches the listed targets of each component kind for a short time, so that listing targets
+// repeatedly does not fetch the topology from PD every time. A nil cache caches nothing.
+type targetsCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[topo.Kind]targetsCacheEntry
+}
+
+type targetsCacheEntry struct {
+	targets  []model.RequestTargetNode
+	expireAt time.Time
+}
+
+// newTargetsCache returns a cache of listed targets, or nil if caching is disabled by cfg.
+func newTargetsCache(cfg *config.Config) *targetsCache {
+	ttl := defaultTargetsCacheTTL
+	if cfg != nil && cfg.ProfilingTargetsCacheTTL != 0 {
+		ttl = cfg.ProfilingTargetsCacheTTL
+	}
+	if ttl < 0 {
+		return nil
+	}
+	return &targetsCache{ttl: ttl, now: time.Now, entries: make(map[topo.Kind]targetsCacheEntry)}
+}
+
+func (c *targetsCache) get(kind topo.Kind) ([]model.RequestTargetNode, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[kind]
+	if !ok || !c.now().Before(entry.expireAt) {
+		return nil, false
+	}
+	return entry.targets, true
+}
+
+func (c *targetsCache) set(kind topo.Kind, targets []model.RequestTargetNode) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[kind] = targetsCacheEntry{targets: targets, expireAt: c.now().Add(c.ttl)}
+}
+
+// uniqueTargets removes duplicated targets with the same kind and address, keeping the first occurrence,
+// so that a target is not profiled twice in a task group.
+func uniqueTargets(targets []model.RequestTargetNode) []model.RequestTargetNode {
+	type targetKey struct {
+		kind model.NodeKind
+		ip   string
+		port int
+	}
+	seen := make(map[targetKey]struct{}, len(targets))
+	result := make([]model.RequestTargetNode, 0, len(targets))
+	for _, target := range targets {
+		key := targetKey{kind: target.Kind, ip: target.IP, port: target.Port}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, target)
+	}
+	return result
+}
+
+// checkTargetsInTopology rejects targets which are no longer present in the current cluster topology,
+// so that decommissioned nodes are not profiled. The topology is always fetched, which is also cached.
+func (s *Service) checkTargetsInTopology(ctx context.Context, targets []model.RequestTargetNode) error {
+	return s.checkTargetsInTopologyOf(ctx, targets, false)
+}
+
+// checkTargetInTopology rejects a target which is no longer present in the current cluster topology, when its task
+// is dispatched. The topology is read from the targets cache if possible, so that the tasks of a task group with
+// many targets do not fetch the topology from PD one by one.
+func (s *Service) checkTargetInTopology(ctx context.Context, target model.RequestTargetNode) error {
+	return s.checkTargetsInTopologyOf(ctx, []model.RequestTargetNode{target}, true)
+}
+
+func (s *Service) checkTargetsInTopologyOf(ctx context.Context, targets []model.RequestTargetNode, useCache bool) error {
+	if s.topoProvider == nil {
+		return ErrTopologyUnavailable.New("topology provider is not available")
+	}
+
+	existingAddrs := make(map[model.NodeKind]map[string]struct{})
+	missingTargets := make([]string, 0)
+	for _, target := range targets {
+		addrs, ok := existingAddrs[target.Kind]
+		if !ok {
+			var kindTargets []model.RequestTargetNode
+			cached := false
+			if useCache {
+				kindTargets, cached = s.targetsCache.get(topo.Kind(target.Kind))
+			}
+			if !cached {
+				var err error
+				if kindTargets, err = s.fetchTargets(ctx, topo.Kind(target.Kind)); err != nil {
+					return err
+				}
+			}
+			addrs = make(map[string]struct{}, len(kindTargets))
+			for _, kindTarget := range kindTargets {
+				addrs[fmt.Sprintf("%s:%d", kindTarget.IP, kindTarget.Port)] = struct{}{}
+			}
+			existingAddrs[target.Kind] = addrs
+		}
+		if _, ok := addrs[fmt.Sprintf("%s:%d", target.IP, target.Port)]; !ok {
+			missingTargets = append(missingTargets, target.String())
+		}
+	}
+	if len(missingTargets) > 0 {
+		return ErrTargetNotInTopology.New("targets no longer exist in the cluster topology: %s", strings.Join(missingTargets, ", "))
+	}
+	return nil
+}
+
+// leaderTargets keeps only the PD leader among the PD targets, since profiling the other PD members is rarely useful.
+// Targets of other kinds have no leader and are kept as they are.
+func (s *Service) leaderTargets(ctx context.Context, targets []model.RequestTargetNode) ([]model.RequestTargetNode, error) {
+	hasPD := false
+	for _, target := range targets {
+		if target.Kind == model.NodeKindPD {
+			hasPD = true
+			break
+		}
+	}
+	if !hasPD {
+		return targets, nil
+	}
+	if s.topoProvider == nil {
+		return nil, ErrTopologyUnavailable.New("topology provider is not available")
+	}
+
+	pds, err := s.topoProvider.GetPD(ctx)
+	if err != nil {
+		return nil, ErrTopologyUnavailable.Wrap(err, "failed to fetch %s topology", model.NodeKindPD)
+	}
+	leaderAddr := ""
+	for _, pd := range pds {
+		if pd.IsLeader {
+			leaderAddr = topologyAddr(pd.Info())
+			break
+		}
+	}
+	if leaderAddr == "" {
+		return nil, ErrTopologyUnavailable.New("PD leader is unknown")
+	}
+
+	result := make([]model.RequestTargetNode, 0, len(targets))
+	for _, target := range targets {
+		if target.Kind == model.NodeKindPD && fmt.Sprintf("%s:%d", target.IP, target.Port) != leaderAddr {
+			continue
+		}
+		result = append(result, target)
+	}
+	if len(result) == 0 {
+		return nil, ErrTargetNotInTopology.New("PD leader %s is not in the targets", leaderAddr)
+	}
+	return result, nil
+}
+
+// tiKVStoreTargets resolves TiKV store IDs to profiling targets using the cluster topology.
+func (s *Service) tiKVStoreTargets(ctx context.Context, storeIDs []uint64) ([]model.RequestTargetNode, error) {
+	if s.topoProvider == nil {
+		return nil, ErrTopologyUnavailable.New("topology provider is not available")
+	}
+	stores, err := s.topoProvider.GetTiKV(ctx)
+	if err != nil {
+		return nil, ErrTopologyUnavailable.Wrap(err, "failed to fetch %s topology", model.NodeKindTiKV)
+	}
+	storeByID := make(map[uint64]topo.TiKVStoreInfo, len(stores))
+	for _, store := range stores {
+		storeByID[store.ID] = store
+	}
+
+	targets := make([]model.RequestTargetNode, 0, len(storeIDs))
+	unknownIDs := make([]string, 0)
+	for _, id := range storeIDs {
+		store, ok := storeByID[id]
+		if !ok {
+			unknownIDs = append(unknownIDs, strconv.FormatUint(id, 10))
+			continue
+		}
+		targets = append(targets, model.RequestTargetNode{
+			Kind:        model.NodeKindTiKV,
+			DisplayName: fmt.Sprintf("%s:%d", store.IP, store.Port),
+			IP:          store.IP,
+			Port:        int(store.StatusPort),
+		})
+	}
+	if len(unknownIDs) > 0 {
+		return nil, ErrUnknownStoreID.New("%s stores do not exist: %s", model.NodeKindTiKV, strings.Join(unknownIDs, ", "))
+	}
+	return targets, nil
+}
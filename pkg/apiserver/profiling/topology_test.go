@@ -0,0 +1,445 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/pkg/apiserver/model"
+	"github.com/pingcap/tidb-dashboard/pkg/config"
+	"github.com/pingcap/tidb-dashboard/util/rest"
+	"github.com/pingcap/tidb-dashboard/util/topo"
+)
+
+func TestCheckTargetsInTopology(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetTiDB", mock.Anything).Return([]topo.TiDBInfo{
+		{IP: "10.0.0.1", Port: 4000, StatusPort: 10080},
+	}, nil)
+	provider.On("GetPD", mock.Anything).Return([]topo.PDInfo{
+		{IP: "10.0.0.2", Port: 2379},
+	}, nil)
+	s.topoProvider = provider
+
+	present := []model.RequestTargetNode{
+		{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.1:4000", IP: "10.0.0.1", Port: 10080},
+		{Kind: model.NodeKindPD, DisplayName: "10.0.0.2:2379", IP: "10.0.0.2", Port: 2379},
+	}
+	require.NoError(t, s.checkTargetsInTopology(context.Background(), present))
+
+	decommissioned := model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.3:4000", IP: "10.0.0.3", Port: 10080}
+	_, err := s.exclusiveExecute(context.Background(), &StartRequest{
+		Targets:                append(present, decommissioned),
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+		CheckTopology:          true,
+	})
+	require.True(t, errorx.IsOfType(err, ErrTargetNotInTopology))
+	require.Contains(t, err.Error(), "tidb(10.0.0.3:4000)")
+
+	var count int64
+	require.NoError(t, s.params.LocalStore.Model(&TaskGroupModel{}).Count(&count).Error)
+	require.Zero(t, count)
+}
+
+func TestCheckTargetsWithoutTopologyProvider(t *testing.T) {
+	s := newTestService(t)
+	err := s.checkTargetsInTopology(context.Background(), []model.RequestTargetNode{
+		{Kind: model.NodeKindTiKV, DisplayName: "10.0.0.1:20160", IP: "10.0.0.1", Port: 20180},
+	})
+	require.True(t, errorx.IsOfType(err, ErrTopologyUnavailable))
+}
+
+func TestLeaderOnly(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetPD", mock.Anything).Return([]topo.PDInfo{
+		{IP: "10.0.0.1", Port: 2379},
+		{IP: "10.0.0.2", Port: 2379, IsLeader: true},
+		{IP: "10.0.0.3", Port: 2379},
+	}, nil)
+	s.topoProvider = provider
+	var profiledAddrs []string
+	var mu sync.Mutex
+	s.fetchers.pd = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		profiledAddrs = append(profiledAddrs, fmt.Sprintf("%s:%d", op.ip, op.port))
+		return []byte("profile"), nil
+	}}
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return []byte("profile"), nil
+	}}
+
+	taskGroup, err := s.exclusiveExecute(context.Background(), &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindPD, DisplayName: "10.0.0.1:2379", IP: "10.0.0.1", Port: 2379},
+			{Kind: model.NodeKindPD, DisplayName: "10.0.0.2:2379", IP: "10.0.0.2", Port: 2379},
+			{Kind: model.NodeKindPD, DisplayName: "10.0.0.3:2379", IP: "10.0.0.3", Port: 2379},
+			{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.4:4000", IP: "10.0.0.4", Port: 10080},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+		LeaderOnly:             true,
+	})
+	require.NoError(t, err)
+	s.wg.Wait()
+
+	require.Equal(t, []string{"10.0.0.2:2379"}, profiledAddrs)
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Order("id ASC").Find(&tasks).Error)
+	require.Len(t, tasks, 2)
+	require.Equal(t, "10.0.0.2", tasks[0].Target.IP)
+	require.Equal(t, model.NodeKindTiDB, tasks[1].Target.Kind)
+	require.Equal(t, 1, taskGroup.TargetStats.NumPDNodes)
+
+	_, err = s.leaderTargets(context.Background(), []model.RequestTargetNode{
+		{Kind: model.NodeKindPD, DisplayName: "10.0.0.1:2379", IP: "10.0.0.1", Port: 2379},
+	})
+	require.True(t, errorx.IsOfType(err, ErrTargetNotInTopology))
+}
+
+func TestTiKVStoreTargets(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetTiKV", mock.Anything).Return([]topo.TiKVStoreInfo{
+		{ID: 1, IP: "10.0.0.1", Port: 20160, StatusPort: 20180},
+		{ID: 4, IP: "10.0.0.4", Port: 20160, StatusPort: 20180},
+		{ID: 5, IP: "10.0.0.5", Port: 20161, StatusPort: 20181},
+	}, nil)
+	s.topoProvider = provider
+	var profiledAddrs []string
+	var mu sync.Mutex
+	s.fetchers.tikv = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		profiledAddrs = append(profiledAddrs, fmt.Sprintf("%s:%d", op.ip, op.port))
+		return []byte("profile"), nil
+	}}
+
+	taskGroup, err := s.exclusiveExecute(context.Background(), &StartRequest{
+		TiKVStoreIDs:           []uint64{5},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.NoError(t, err)
+	s.wg.Wait()
+
+	require.Equal(t, []string{"10.0.0.5:20181"}, profiledAddrs)
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Find(&tasks).Error)
+	require.Len(t, tasks, 1)
+	require.Equal(t, model.NodeKindTiKV, tasks[0].Target.Kind)
+	require.Equal(t, "10.0.0.5:20161", tasks[0].Target.DisplayName)
+	require.Equal(t, 1, taskGroup.TargetStats.NumTiKVNodes)
+
+	_, err = s.exclusiveExecute(context.Background(), &StartRequest{
+		TiKVStoreIDs:           []uint64{1, 2, 3},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+	})
+	require.True(t, errorx.IsOfType(err, ErrUnknownStoreID))
+	require.Contains(t, err.Error(), "2, 3")
+}
+
+func TestListTargets(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetTiDB", mock.Anything).Return([]topo.TiDBInfo{
+		{IP: "10.0.0.1", Port: 4000, StatusPort: 10080},
+	}, nil)
+	provider.On("GetTiKV", mock.Anything).Return([]topo.TiKVStoreInfo{
+		{IP: "10.0.0.2", Port: 20160, StatusPort: 20180},
+		{IP: "10.0.0.3", Port: 20160, StatusPort: 20180, Status: topo.CompStatusTombstone},
+	}, nil)
+	provider.On("GetPD", mock.Anything).Return([]topo.PDInfo{
+		{IP: "10.0.0.4", Port: 2379},
+	}, nil)
+	provider.On("GetTiFlash", mock.Anything).Return([]topo.TiFlashStoreInfo{}, nil)
+	provider.On("GetTiProxy", mock.Anything).Return([]topo.TiProxyInfo{
+		{IP: "10.0.0.5", Port: 6000, StatusPort: 3080},
+	}, nil)
+	provider.On("GetTiCDC", mock.Anything).Return([]topo.TiCDCInfo{
+		{IP: "10.0.0.6", Port: 8300},
+	}, nil)
+	provider.On("GetPDTSO", mock.Anything).Return([]topo.PDTSOInfo{
+		{IP: "10.0.0.7", Port: 3379},
+	}, nil)
+	provider.On("GetPDScheduling", mock.Anything).Return([]topo.PDSchedulingInfo{
+		{IP: "10.0.0.8", Port: 3379},
+	}, nil)
+	s.topoProvider = provider
+
+	targets, err := s.listTargets(context.Background(), ListTargetsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []model.RequestTargetNode{
+		{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.1:4000", IP: "10.0.0.1", Port: 10080},
+		{Kind: model.NodeKindTiKV, DisplayName: "10.0.0.2:20160", IP: "10.0.0.2", Port: 20180},
+		{Kind: model.NodeKindPD, DisplayName: "10.0.0.4:2379", IP: "10.0.0.4", Port: 2379},
+		{Kind: model.NodeKindTiProxy, DisplayName: "10.0.0.5:6000", IP: "10.0.0.5", Port: 3080},
+		{Kind: model.NodeKindTiCDC, DisplayName: "10.0.0.6:8300", IP: "10.0.0.6", Port: 8300},
+		{Kind: model.NodeKindPDTSO, DisplayName: "10.0.0.7:3379", IP: "10.0.0.7", Port: 3379},
+		{Kind: model.NodeKindPDScheduling, DisplayName: "10.0.0.8:3379", IP: "10.0.0.8", Port: 3379},
+	}, targets)
+}
+
+func TestListTargetsByKinds(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetTiDB", mock.Anything).Return([]topo.TiDBInfo{
+		{IP: "10.0.0.1", Port: 4000, StatusPort: 10080},
+	}, nil)
+	provider.On("GetPD", mock.Anything).Return([]topo.PDInfo{
+		{IP: "10.0.0.4", Port: 2379},
+	}, nil)
+	s.topoProvider = provider
+
+	targets, err := s.listTargets(context.Background(), ListTargetsRequest{Kinds: []topo.Kind{topo.KindPD}})
+	require.NoError(t, err)
+	require.Equal(t, []model.RequestTargetNode{
+		{Kind: model.NodeKindPD, DisplayName: "10.0.0.4:2379", IP: "10.0.0.4", Port: 2379},
+	}, targets)
+	provider.AssertNotCalled(t, "GetTiKV", mock.Anything)
+	provider.AssertNotCalled(t, "GetTiDB", mock.Anything)
+
+	// Targets are listed in the same order as listing all kinds.
+	targets, err = s.listTargets(context.Background(), ListTargetsRequest{Kinds: []topo.Kind{topo.KindPD, topo.KindTiDB, topo.KindPD}})
+	require.NoError(t, err)
+	require.Equal(t, []model.RequestTargetNode{
+		{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.1:4000", IP: "10.0.0.1", Port: 10080},
+		{Kind: model.NodeKindPD, DisplayName: "10.0.0.4:2379", IP: "10.0.0.4", Port: 2379},
+	}, targets)
+	provider.AssertNotCalled(t, "GetTiKV", mock.Anything)
+
+	_, err = s.listTargets(context.Background(), ListTargetsRequest{Kinds: []topo.Kind{topo.KindPrometheus}})
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+}
+
+func TestListTargetsCache(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetPD", mock.Anything).Return([]topo.PDInfo{
+		{IP: "10.0.0.4", Port: 2379},
+	}, nil)
+	s.topoProvider = provider
+	now := time.Unix(1600000000, 0)
+	s.targetsCache = newTargetsCache(&config.Config{ProfilingTargetsCacheTTL: 5 * time.Second})
+	s.targetsCache.now = func() time.Time { return now }
+
+	req := ListTargetsRequest{Kinds: []topo.Kind{topo.KindPD}}
+	expected := []model.RequestTargetNode{{Kind: model.NodeKindPD, DisplayName: "10.0.0.4:2379", IP: "10.0.0.4", Port: 2379}}
+	for i := 0; i < 2; i++ {
+		targets, err := s.listTargets(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, expected, targets)
+	}
+	provider.AssertNumberOfCalls(t, "GetPD", 1)
+
+	now = now.Add(5 * time.Second)
+	targets, err := s.listTargets(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, expected, targets)
+	provider.AssertNumberOfCalls(t, "GetPD", 2)
+
+	require.Nil(t, newTargetsCache(&config.Config{ProfilingTargetsCacheTTL: -1}))
+}
+
+func TestResolveTargets(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetTiKV", mock.Anything).Return([]topo.TiKVStoreInfo{
+		{IP: "10.0.0.2", Port: 20160, StatusPort: 20180},
+		{IP: "10.0.0.3", Port: 20160, StatusPort: 20180, Status: topo.CompStatusTombstone},
+	}, nil)
+	s.topoProvider = provider
+	s.targetsCache = newTargetsCache(&config.Config{})
+	s.fetchers.tikv = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return newTestCPUProfile(t, map[string]int64{"raftstore.poll": 10000000}), nil
+	}}
+
+	// Cached targets are not used when resolving.
+	_, err := s.listTargets(context.Background(), ListTargetsRequest{Kinds: []topo.Kind{topo.KindTiKV}})
+	require.NoError(t, err)
+	targets, err := s.resolveTargets(context.Background(), ResolveTargetsRequest{Kinds: []topo.Kind{topo.KindTiKV}})
+	require.NoError(t, err)
+	require.Equal(t, []model.RequestTargetNode{
+		{Kind: model.NodeKindTiKV, DisplayName: "10.0.0.2:20160", IP: "10.0.0.2", Port: 20180},
+	}, targets)
+	provider.AssertNumberOfCalls(t, "GetTiKV", 2)
+
+	// The resolved targets are accepted for profiling as they are.
+	taskGroup, err := s.exclusiveExecute(context.Background(), &StartRequest{
+		Targets:                targets,
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+		CheckTopology:          true,
+	})
+	require.NoError(t, err)
+	s.wg.Wait()
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Find(&tasks).Error)
+	require.Len(t, tasks, 1)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+
+	_, err = s.resolveTargets(context.Background(), ResolveTargetsRequest{})
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+	_, err = s.resolveTargets(context.Background(), ResolveTargetsRequest{Kinds: []topo.Kind{topo.KindPrometheus}})
+	require.True(t, errorx.IsOfType(err, rest.ErrBadRequest))
+}
+
+func TestCheckTopologyOnDispatch(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	// The second TiDB is removed from the cluster after the task group is started.
+	provider.On("GetTiDB", mock.Anything).Return([]topo.TiDBInfo{
+		{IP: "10.0.0.1", Port: 4000, StatusPort: 10080},
+		{IP: "10.0.0.2", Port: 4000, StatusPort: 10080},
+	}, nil).Once()
+	provider.On("GetTiDB", mock.Anything).Return([]topo.TiDBInfo{
+		{IP: "10.0.0.1", Port: 4000, StatusPort: 10080},
+	}, nil)
+	s.topoProvider = provider
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		require.Equal(t, "10.0.0.1", op.ip)
+		return newTestHeapProfile(t, map[string]int64{"main.alloc": 10}), nil
+	}}
+
+	taskGroup, err := s.exclusiveExecute(context.Background(), &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.1:4000", IP: "10.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.2:4000", IP: "10.0.0.2", Port: 10080},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+		CheckTopology:          true,
+		StaggerMs:              50,
+	})
+	require.NoError(t, err)
+	s.wg.Wait()
+
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Order("id ASC").Find(&tasks).Error)
+	require.Len(t, tasks, 2)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, TaskStateError, tasks[1].State)
+	require.Contains(t, tasks[1].Error, "no longer exist in the cluster topology")
+	require.Contains(t, tasks[1].Error, "10.0.0.2")
+}
+
+func TestCheckTopologyOnDispatchUsesTargetsCache(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetTiDB", mock.Anything).Return([]topo.TiDBInfo{
+		{IP: "10.0.0.1", Port: 4000, StatusPort: 10080},
+		{IP: "10.0.0.2", Port: 4000, StatusPort: 10080},
+		{IP: "10.0.0.3", Port: 4000, StatusPort: 10080},
+	}, nil)
+	s.topoProvider = provider
+	s.targetsCache = newTargetsCache(&config.Config{ProfilingTargetsCacheTTL: time.Minute})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		return newTestHeapProfile(t, map[string]int64{"main.alloc": 10}), nil
+	}}
+
+	targets := make([]model.RequestTargetNode, 0, 3)
+	for i := 1; i <= 3; i++ {
+		ip := fmt.Sprintf("10.0.0.%d", i)
+		targets = append(targets, model.RequestTargetNode{Kind: model.NodeKindTiDB, DisplayName: ip + ":4000", IP: ip, Port: 10080})
+	}
+	taskGroup, err := s.exclusiveExecute(context.Background(), &StartRequest{
+		Targets:                targets,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+		CheckTopology:          true,
+		MaxConcurrency:         1,
+	})
+	require.NoError(t, err)
+	s.wg.Wait()
+
+	// The topology fetched when starting the task group is reused when each task is dispatched.
+	provider.AssertNumberOfCalls(t, "GetTiDB", 1)
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Find(&tasks).Error)
+	require.Len(t, tasks, 3)
+	for _, task := range tasks {
+		require.Equal(t, TaskStateFinish, task.State)
+	}
+}
+
+func TestCancelWhileWaitingForDispatch(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetTiDB", mock.Anything).Return([]topo.TiDBInfo{
+		{IP: "10.0.0.1", Port: 4000, StatusPort: 10080},
+		{IP: "10.0.0.2", Port: 4000, StatusPort: 10080},
+	}, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	s.topoProvider = provider
+	started := make(chan struct{})
+	s.fetchers.tidb = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		close(started)
+		<-op.ctx.Done()
+		return nil, op.ctx.Err()
+	}}
+
+	taskGroup, err := s.exclusiveExecute(context.Background(), &StartRequest{
+		Targets: []model.RequestTargetNode{
+			{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.1:4000", IP: "10.0.0.1", Port: 10080},
+			{Kind: model.NodeKindTiDB, DisplayName: "10.0.0.2:4000", IP: "10.0.0.2", Port: 10080},
+		},
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeHeap},
+		CheckTopology:          true,
+		MaxConcurrency:         1,
+	})
+	require.NoError(t, err)
+	<-started
+	require.NoError(t, s.cancelGroup(taskGroup.ID))
+	s.wg.Wait()
+
+	// The queued task fails to check its target since it is cancelled, which is not an error of the target.
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Order("id ASC").Find(&tasks).Error)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		require.Equal(t, TaskStateCancelled, task.State)
+	}
+}
+
+func TestPDTSOProfiling(t *testing.T) {
+	s := newTestService(t)
+	provider := new(topo.MockTopologyProvider)
+	provider.On("GetPDTSO", mock.Anything).Return([]topo.PDTSOInfo{
+		{IP: "10.0.0.7", Port: 3379},
+	}, nil)
+	s.topoProvider = provider
+	content := newTestCPUProfile(t, map[string]int64{"tso.(*timestampOracle).getTS": 10000000})
+	s.fetchers.pdTSO = &mockFetcher{fetchFn: func(op *fetchOptions) ([]byte, error) {
+		require.Equal(t, "10.0.0.7", op.ip)
+		require.Equal(t, 3379, op.port)
+		require.Equal(t, "/debug/pprof/profile?seconds=1", op.path)
+		return content, nil
+	}}
+
+	// The TSO server is profiled as a distinct target, which is checked against its own topology.
+	taskGroup, err := s.exclusiveExecute(context.Background(), &StartRequest{
+		Targets:                []model.RequestTargetNode{{Kind: model.NodeKindPDTSO, DisplayName: "10.0.0.7:3379", IP: "10.0.0.7", Port: 3379}},
+		DurationSecs:           1,
+		RequstedProfilingTypes: TaskProfilingTypeList{ProfilingTypeCPU},
+		CheckTopology:          true,
+	})
+	require.NoError(t, err)
+	s.wg.Wait()
+	provider.AssertNumberOfCalls(t, "GetPDTSO", 1)
+
+	var tasks []TaskModel
+	require.NoError(t, s.params.LocalStore.Where("task_group_id = ?", taskGroup.ID).Find(&tasks).Error)
+	require.Len(t, tasks, 1)
+	require.Equal(t, TaskStateFinish, tasks[0].State)
+	require.Equal(t, RawDataTypeProtobuf, tasks[0].RawDataType)
+	require.Equal(t, 1, taskGroup.TargetStats.NumPDTSONodes)
+}
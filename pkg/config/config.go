@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/pingcap/tidb-dashboard/pkg/utils/version"
 )
@@ -16,6 +17,8 @@ const (
 	UIPathPrefix      = "/dashboard/"
 	APIPathPrefix     = "/dashboard/api/"
 	SwaggerPathPrefix = "/dashboard/api/swagger/"
+
+	DefaultProfilingRetention = 7 * 24 * time.Hour
 )
 
 type Config struct {
@@ -26,12 +29,78 @@ type Config struct {
 
 	ClusterTLSConfig *tls.Config // TLS config for mTLS authentication between TiDB components.
 	TiDBTLSConfig    *tls.Config // TLS config for mTLS authentication between TiDB and MySQL client.
+	// TLS config for mTLS authentication with a specific kind of TiDB component, which overrides ClusterTLSConfig.
+	// It is useful when components are signed by different CAs. Keys are the kinds of components, e.g. "tikv".
+	ComponentTLSConfigs map[string]*tls.Config
+
+	// AES keys to encrypt profiling results at rest, indexed by key ID. Keys no longer used for new results
+	// should be kept to decrypt existing results, so that keys can be rotated by changing the key ID.
+	ProfilingEncryptionKeys map[string][]byte
+	// The ID of the key to encrypt new profiling results. Encryption is disabled when it is empty.
+	ProfilingEncryptionKeyID string
+	// Profiling results older than the retention are deleted automatically, which is 7 days by default. Results are
+	// kept forever when it is 0 or negative.
+	ProfilingRetention time.Duration
+	// The maximum bytes per second of all profile downloads in total. Downloads are not limited when it is 0.
+	ProfilingBandwidthLimit int64
+	// The time allowed for fetching a profile beyond the profile duration, unless it is specified by the
+	// profiling request. 30 seconds is used when it is 0.
+	ProfilingRequestTimeoutSlack time.Duration
+	// The maximum duration of a profiling request, above which the request is rejected. 120 seconds is used
+	// when it is 0.
+	ProfilingMaxDurationSecs uint
+	// The maximum times of retrying a profile fetch failed due to a connection error. 2 is used when it is 0, and
+	// fetches are not retried when it is negative.
+	ProfilingFetchMaxRetries int
+	// The directory to store profiling results, which is created if it does not exist. Results are stored in the
+	// temporary directory of the OS when it is empty, where they may be removed by the OS.
+	ProfilingResultDir string
+	// The S3 compatible object storage to store profiling results, instead of the local filesystem. Results
+	// are uploaded once they are fetched and are not kept locally. Results are stored locally when it is nil.
+	ProfilingS3 *ProfilingS3Config
+	// The path under which pprof handlers of a kind of component are served, e.g. /debug/pprof, for components
+	// deployed with a non-default path. Keys are the kinds of components, e.g. "tidb". /debug/pprof is used for
+	// kinds not in the map.
+	ProfilingPprofPathPrefixes map[string]string
+	// The time for which the listed profiling targets are cached, so that the topology is not fetched from PD for
+	// each listing. 5 seconds is used when it is 0, and targets are not cached when it is negative.
+	ProfilingTargetsCacheTTL time.Duration
+	// The codec to compress new profiling results, which is "gzip", "zstd" or "none". gzip is used when it is empty.
+	// The codec of each result is saved along with it, so existing results are still read correctly after the codec
+	// is changed.
+	ProfilingCompressionCodec string
+	// The Authorization header sent along with profiling requests to a kind of component, e.g. "Bearer <token>"
+	// or "Basic <credentials>", for components whose status ports are secured, keyed by the kind, e.g. "tikv". No
+	// header is sent for other kinds.
+	ProfilingAuthorizations map[string]string
+	// The total size in bytes of stored profiling results. When a task group would exceed it, the oldest stopped task
+	// groups are deleted to make room, or the task group is rejected if there is still no room. 0 means no limit.
+	ProfilingStorageBudget int64
+	// The maximum number of task groups running at the same time. Starting more task groups is rejected instead of
+	// being queued. 32 is used when it is 0, and it is not limited when it is negative.
+	ProfilingMaxRunningGroups int
+	// The HTTP proxy through which profiles are fetched, for networks where status ports of components are only
+	// reachable via an egress proxy. Profiles are fetched directly when it is nil.
+	ProfilingProxyURL *url.URL
+	// The HTTP proxy through which profiles of a kind of component are fetched, which overrides ProfilingProxyURL.
+	// Keys are the kinds of components, e.g. "tikv".
+	ProfilingProxyURLs map[string]*url.URL
 
 	EnableTelemetry    bool
 	EnableExperimental bool
 	FeatureVersion     string // assign the target TiDB version when running TiDB Dashboard as standalone mode
 }
 
+// ProfilingS3Config is the S3 compatible object storage to store profiling results.
+type ProfilingS3Config struct {
+	Endpoint        string // e.g. https://s3.us-east-1.amazonaws.com, or the endpoint of a MinIO server
+	Region          string // us-east-1 is used when it is empty
+	Bucket          string
+	Prefix          string // The prefix of the object keys of profiling results, e.g. "dashboard/profiling/"
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
 func Default() *Config {
 	return &Config{
 		DataDir:            "/tmp/dashboard-data",
@@ -43,6 +112,8 @@ func Default() *Config {
 		EnableTelemetry:    true,
 		EnableExperimental: false,
 		FeatureVersion:     version.PDVersion,
+
+		ProfilingRetention: DefaultProfilingRetention,
 	}
 }
 
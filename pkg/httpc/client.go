@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/joomcode/errorx"
@@ -21,6 +22,8 @@ import (
 
 const (
 	defaultTimeout = time.Second * 10
+	// The body of a failed response is truncated in the error, since it may be a large page, e.g. of a proxy.
+	maxErrorBodyBytes = 1024
 )
 
 type Client struct {
@@ -30,15 +33,33 @@ type Client struct {
 }
 
 func NewHTTPClient(lc fx.Lifecycle, config *config.Config) *Client {
-	cli := http.Client{
-		Transport: &http.Transport{
-			DialTLS: func(network, addr string) (net.Conn, error) {
-				conn, err := tls.Dial(network, addr, config.ClusterTLSConfig)
-				return conn, err
-			},
-			TLSClientConfig: config.ClusterTLSConfig,
+	return NewHTTPClientWithTLSConfig(lc, config.ClusterTLSConfig)
+}
+
+// NewHTTPClientWithTLSConfig creates a client which uses the specified TLS config instead of the cluster one.
+func NewHTTPClientWithTLSConfig(lc fx.Lifecycle, tlsConfig *tls.Config) *Client {
+	return newHTTPClient(lc, &http.Transport{
+		DialTLS: func(network, addr string) (net.Conn, error) {
+			conn, err := tls.Dial(network, addr, tlsConfig)
+			return conn, err
 		},
-		Timeout: defaultTimeout,
+		TLSClientConfig: tlsConfig,
+	})
+}
+
+// NewHTTPClientWithProxy creates a client which sends requests through the HTTP proxy, using the specified TLS
+// config to connect to the targets. HTTPS requests are tunneled through the proxy by CONNECT.
+func NewHTTPClientWithProxy(lc fx.Lifecycle, tlsConfig *tls.Config, proxyURL *url.URL) *Client {
+	return newHTTPClient(lc, &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: tlsConfig,
+	})
+}
+
+func newHTTPClient(lc fx.Lifecycle, transport *http.Transport) *Client {
+	cli := http.Client{
+		Transport: transport,
+		Timeout:   defaultTimeout,
 	}
 
 	lc.Append(fx.Hook{
@@ -117,9 +138,13 @@ func (c *Client) Send(
 
 	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
 		defer resp.Body.Close()
-		data, _ := ioutil.ReadAll(resp.Body)
-		e := errType.New("Request failed with status code %d from %s API: %s", resp.StatusCode, errOriginComponent, string(data))
-		log.Warn("SendRequest failed", zap.String("uri", uri), zap.Error(err))
+		data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes+1))
+		body := string(data)
+		if len(data) > maxErrorBodyBytes {
+			body = string(data[:maxErrorBodyBytes]) + "..."
+		}
+		e := errType.New("Request failed with status %d %s from %s API: %s", resp.StatusCode, http.StatusText(resp.StatusCode), errOriginComponent, body)
+		log.Warn("SendRequest failed", zap.String("uri", uri), zap.Error(e))
 		return nil, e
 	}
 
@@ -6,8 +6,10 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/joomcode/errorx"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx/fxtest"
 
@@ -65,3 +67,19 @@ func Test_Send_withHeader(t *testing.T) {
 	d3, _ := resp3.Body()
 	require.Equal(t, "", string(d3))
 }
+
+func Test_Send_failedStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(strings.Repeat("x", 2*maxErrorBodyBytes)))
+	}))
+	defer ts.Close()
+
+	c := newTestClient(t)
+	_, err := c.Send(context.Background(), ts.URL, http.MethodGet, nil, errorx.NewNamespace("test").NewType("failed"), "test")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "503 Service Unavailable")
+	// The body is truncated.
+	require.Contains(t, err.Error(), strings.Repeat("x", maxErrorBodyBytes)+"...")
+	require.NotContains(t, err.Error(), strings.Repeat("x", maxErrorBodyBytes+1))
+}
@@ -77,6 +77,18 @@ func (c Client) getPrefix() string {
 	return "/pd/api/v1"
 }
 
+func (c Client) WithHTTPClient(httpClient *httpc.Client, httpScheme string) *Client {
+	c.httpClient = httpClient
+	c.httpScheme = httpScheme
+	return &c
+}
+
+// WithContext returns a client whose requests are cancelled when the given context is done.
+func (c Client) WithContext(ctx context.Context) *Client {
+	c.lifecycleCtx = ctx
+	return &c
+}
+
 func (c Client) AddRequestHeader(key, value string) *Client {
 	c.httpClient = c.httpClient.CloneAndAddRequestHeader(key, value)
 	return &c
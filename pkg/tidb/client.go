@@ -6,8 +6,10 @@ import (
 	"context"
 	"database/sql/driver"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -100,6 +102,24 @@ func (c Client) WithEnforcedStatusAPIAddress(host string, statusPort int) *Clien
 	return &c
 }
 
+func (c Client) WithStatusAPIHTTPClient(httpClient *httpc.Client, httpScheme string) *Client {
+	c.statusAPIHTTPClient = httpClient
+	c.statusAPIHTTPScheme = httpScheme
+	return &c
+}
+
+// AddStatusAPIRequestHeader returns a client which sends the header along with status API requests.
+func (c Client) AddStatusAPIRequestHeader(key, value string) *Client {
+	c.statusAPIHTTPClient = c.statusAPIHTTPClient.CloneAndAddRequestHeader(key, value)
+	return &c
+}
+
+// WithContext returns a client whose status API requests are cancelled when the given context is done.
+func (c Client) WithContext(ctx context.Context) *Client {
+	c.lifecycleCtx = ctx
+	return &c
+}
+
 func (c Client) WithSQLAPIAddress(host string, sqlPort int) *Client {
 	c.sqlAPIAddress = fmt.Sprintf("%s:%d", host, sqlPort)
 	return &c
@@ -163,6 +183,10 @@ func (c *Client) OpenSQLConn(user string, pass string) (*gorm.DB, error) {
 }
 
 func (c *Client) Get(relativeURI string) (*httpc.Response, error) {
+	return c.sendStatusAPIRequest(c.statusAPIHTTPClient, http.MethodGet, relativeURI, nil)
+}
+
+func (c *Client) sendStatusAPIRequest(httpClient *httpc.Client, method string, relativeURI string, body io.Reader) (*httpc.Response, error) {
 	var err error
 
 	overrideEndpoint := os.Getenv(tidbOverrideStatusEndpointEnvVar)
@@ -188,9 +212,9 @@ func (c *Client) Get(relativeURI string) (*httpc.Response, error) {
 	}
 
 	uri := fmt.Sprintf("%s://%s%s", c.statusAPIHTTPScheme, addr, relativeURI)
-	res, err := c.statusAPIHTTPClient.
+	res, err := httpClient.
 		WithTimeout(c.statusAPITimeout).
-		Send(c.lifecycleCtx, uri, http.MethodGet, nil, ErrTiDBClientRequestFailed, distro.R().TiDB)
+		Send(c.lifecycleCtx, uri, method, body, ErrTiDBClientRequestFailed, distro.R().TiDB)
 	if err != nil && c.forwarder.statusProxy.noAliveRemote.Load() {
 		return nil, ErrNoAliveTiDB.NewWithNoMessage()
 	}
@@ -205,3 +229,13 @@ func (c *Client) SendGetRequest(relativeURI string) ([]byte, error) {
 	}
 	return res.Body()
 }
+
+// SendPostFormRequest sends a POST request with a URL encoded form to the status API.
+func (c *Client) SendPostFormRequest(relativeURI string, form url.Values) ([]byte, error) {
+	httpClient := c.statusAPIHTTPClient.CloneAndAddRequestHeader("Content-Type", "application/x-www-form-urlencoded")
+	res, err := c.sendStatusAPIRequest(httpClient, http.MethodPost, relativeURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	return res.Body()
+}
@@ -93,12 +93,58 @@ import { MetricsPutCustomPromAddressResponse } from '../models';
 // @ts-ignore
 import { MetricsQueryResponse } from '../models';
 // @ts-ignore
+import { ModelRequestTargetNode } from '../models';
+// @ts-ignore
+import { ProfilingAddGroupCommentRequest } from '../models';
+// @ts-ignore
+import { ProfilingBaselineCompareResult } from '../models';
+// @ts-ignore
+import { ProfilingBaselineModel } from '../models';
+// @ts-ignore
+import { ProfilingCampaignDetailResponse } from '../models';
+// @ts-ignore
+import { ProfilingCampaignModel } from '../models';
+// @ts-ignore
+import { ProfilingCloneGroupRequest } from '../models';
+// @ts-ignore
+import { ProfilingCreateScheduleRequest } from '../models';
+// @ts-ignore
+import { ProfilingEstimateGroupResponse } from '../models';
+// @ts-ignore
 import { ProfilingGroupDetailResponse } from '../models';
 // @ts-ignore
+import { ProfilingGroupProgressEvent } from '../models';
+// @ts-ignore
+import { ProfilingGroupTopResponse } from '../models';
+// @ts-ignore
+import { ProfilingKindCapabilitiesResponse } from '../models';
+// @ts-ignore
+import { ProfilingListGroupsResponse } from '../models';
+// @ts-ignore
+import { ProfilingPingTargetsRequest } from '../models';
+// @ts-ignore
+import { ProfilingProfilePreview } from '../models';
+// @ts-ignore
+import { ProfilingRegisterBaselineRequest } from '../models';
+// @ts-ignore
+import { ProfilingScheduleModel } from '../models';
+// @ts-ignore
+import { ProfilingStartCampaignRequest } from '../models';
+// @ts-ignore
 import { ProfilingStartRequest } from '../models';
 // @ts-ignore
+import { ProfilingTargetReachability } from '../models';
+// @ts-ignore
+import { ProfilingTaskGroupCommentModel } from '../models';
+// @ts-ignore
 import { ProfilingTaskGroupModel } from '../models';
 // @ts-ignore
+import { ProfilingTaskModel } from '../models';
+// @ts-ignore
+import { ProfilingTaskWithData } from '../models';
+// @ts-ignore
+import { ProfilingTasksDataRequest } from '../models';
+// @ts-ignore
 import { QueryeditorRunRequest } from '../models';
 // @ts-ignore
 import { QueryeditorRunResponse } from '../models';
@@ -151,7 +197,50 @@ import { UserTokenResponse } from '../models';
 export const DefaultApiAxiosParamCreator = function (configuration?: Configuration) {
     return {
         /**
-         * Cancel all profling tasks with a given group ID
+         * Add a comment to a profiling task group. Comments cannot be modified once added.
+         * @summary Add a comment to a task group
+         * @param {string} groupId group ID
+         * @param {ProfilingAddGroupCommentRequest} req comment request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        addProfilingGroupComment: async (groupId: string, req: ProfilingAddGroupCommentRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'groupId' is not null or undefined
+            assertParamExists('addProfilingGroupComment', 'groupId', groupId)
+            // verify required parameter 'req' is not null or undefined
+            assertParamExists('addProfilingGroupComment', 'req', req)
+            const localVarPath = `/profiling/group/comments/{groupId}`
+                .replace(`{${"groupId"}}`, encodeURIComponent(String(groupId)));
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(req, localVarRequestOptions, configuration)
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * Cancel all running profling tasks with a given group ID. Results of finished tasks are kept.
          * @summary Cancel all tasks with a given group ID
          * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
@@ -187,6 +276,49 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 options: localVarRequestOptions,
             };
         },
+        /**
+         * Start a new group profiling the same targets with the same profiling types as a stopped group. The original group is kept.
+         * @summary Profile the targets of a group again
+         * @param {string} groupId group ID
+         * @param {ProfilingCloneGroupRequest} req clone request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        cloneProfilingGroup: async (groupId: string, req: ProfilingCloneGroupRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'groupId' is not null or undefined
+            assertParamExists('cloneProfilingGroup', 'groupId', groupId)
+            // verify required parameter 'req' is not null or undefined
+            assertParamExists('cloneProfilingGroup', 'req', req)
+            const localVarPath = `/profiling/group/clone/{groupId}`
+                .replace(`{${"groupId"}}`, encodeURIComponent(String(groupId)));
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(req, localVarRequestOptions, configuration)
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
         /**
          * 
          * @summary Get information of all hosts
@@ -244,6 +376,58 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
 
 
     
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * Compare the profiling result of a task with a baseline and report functions regressed more than the threshold
+         * @summary Compare a task with a baseline
+         * @param {string} taskId task ID
+         * @param {string} baseline baseline name
+         * @param {number} [threshold] regression threshold in share of total samples, e.g. 0.05
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        compareProfilingToBaseline: async (taskId: string, baseline: string, threshold?: number, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'taskId' is not null or undefined
+            assertParamExists('compareProfilingToBaseline', 'taskId', taskId)
+            // verify required parameter 'baseline' is not null or undefined
+            assertParamExists('compareProfilingToBaseline', 'baseline', baseline)
+            const localVarPath = `/profiling/baseline/compare`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+            if (taskId !== undefined) {
+                localVarQueryParameter['task_id'] = taskId;
+            }
+
+            if (baseline !== undefined) {
+                localVarQueryParameter['baseline'] = baseline;
+            }
+
+            if (threshold !== undefined) {
+                localVarQueryParameter['threshold'] = threshold;
+            }
+
+
+    
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
@@ -679,6 +863,45 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 options: localVarRequestOptions,
             };
         },
+        /**
+         * Profile with the same request every interval. Each run is a task group linked by the schedule ID.
+         * @summary Create a profiling schedule
+         * @param {ProfilingCreateScheduleRequest} req schedule request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        createProfilingSchedule: async (req: ProfilingCreateScheduleRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'req' is not null or undefined
+            assertParamExists('createProfilingSchedule', 'req', req)
+            const localVarPath = `/profiling/schedule/create`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(req, localVarRequestOptions, configuration)
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
         /**
          * 
          * @summary List all deadlock records
@@ -822,7 +1045,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * Delete all finished profiling tasks with a given group ID
+         * Delete all profiling tasks and results with a given group ID. A running group must be cancelled first. Baselines registered from its results are deleted with it.
          * @summary Delete all tasks with a given group ID
          * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
@@ -849,6 +1072,43 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
 
 
     
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * Stop running a profiling schedule. Task groups already started by the schedule are kept.
+         * @summary Delete a profiling schedule
+         * @param {string} scheduleId schedule ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        deleteProfilingSchedule: async (scheduleId: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'scheduleId' is not null or undefined
+            assertParamExists('deleteProfilingSchedule', 'scheduleId', scheduleId)
+            const localVarPath = `/profiling/schedule/delete/{scheduleId}`
+                .replace(`{${"scheduleId"}}`, encodeURIComponent(String(scheduleId)));
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'DELETE', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
@@ -1151,16 +1411,17 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * Download all finished profiling results of a task group
-         * @summary Download all results of a task group
+         * Download the profiling results of each target merged across all iterations of a campaign
+         * @summary Download the merged results of a campaign
          * @param {string} token download token
+         * @param {string} [profilingType] profiling type, cpu by default
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        downloadProfilingGroup: async (token: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+        downloadProfilingCampaign: async (token: string, profilingType?: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
             // verify required parameter 'token' is not null or undefined
-            assertParamExists('downloadProfilingGroup', 'token', token)
-            const localVarPath = `/profiling/group/download`;
+            assertParamExists('downloadProfilingCampaign', 'token', token)
+            const localVarPath = `/profiling/campaign/download`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1179,6 +1440,10 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 localVarQueryParameter['token'] = token;
             }
 
+            if (profilingType !== undefined) {
+                localVarQueryParameter['profiling_type'] = profilingType;
+            }
+
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -1191,16 +1456,21 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * Download the finished profiling result of a task
-         * @summary Download the result of a task
+         * Download all finished profiling results of a task group
+         * @summary Download all results of a task group
          * @param {string} token download token
+         * @param {boolean} [autoDelete] delete the task group after the results are fully exported, which is not allowed with filters. The token must be issued for group_download_auto_delete
+         * @param {Array<string>} [kinds] 
+         * @param {Array<string>} [profilingTypes] 
+         * @param {Array<string>} [targets] Addresses of targets in the form of \&quot;ip:port\&quot;
+         * @param {boolean} [mergeCpu] also download the finished CPU profiles merged into a single profile
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        downloadProfilingSingle: async (token: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+        downloadProfilingGroup: async (token: string, autoDelete?: boolean, kinds?: Array<string>, profilingTypes?: Array<string>, targets?: Array<string>, mergeCpu?: boolean, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
             // verify required parameter 'token' is not null or undefined
-            assertParamExists('downloadProfilingSingle', 'token', token)
-            const localVarPath = `/profiling/single/download`;
+            assertParamExists('downloadProfilingGroup', 'token', token)
+            const localVarPath = `/profiling/group/download`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1219,6 +1489,26 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 localVarQueryParameter['token'] = token;
             }
 
+            if (autoDelete !== undefined) {
+                localVarQueryParameter['auto_delete'] = autoDelete;
+            }
+
+            if (kinds) {
+                localVarQueryParameter['kinds'] = kinds;
+            }
+
+            if (profilingTypes) {
+                localVarQueryParameter['profiling_types'] = profilingTypes;
+            }
+
+            if (targets) {
+                localVarQueryParameter['targets'] = targets;
+            }
+
+            if (mergeCpu !== undefined) {
+                localVarQueryParameter['merge_cpu'] = mergeCpu;
+            }
+
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -1231,15 +1521,17 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * Get token with a given group ID or task ID and action type
-         * @summary Get action token for download or view
-         * @param {string} [id] group or task ID
-         * @param {string} [action] action
+         * Download the finished profiling results of a profiling type across all targets of a task group merged into a single profile. Samples are labeled with their source instance and component.
+         * @summary Download the merged result of a task group
+         * @param {string} token download token of the task group
+         * @param {string} [profilingType] profiling type, cpu by default
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getActionToken: async (id?: string, action?: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/profiling/action_token`;
+        downloadProfilingGroupMerged: async (token: string, profilingType?: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'token' is not null or undefined
+            assertParamExists('downloadProfilingGroupMerged', 'token', token)
+            const localVarPath = `/profiling/group/merged_download`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1254,12 +1546,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
-            if (id !== undefined) {
-                localVarQueryParameter['id'] = id;
+            if (token !== undefined) {
+                localVarQueryParameter['token'] = token;
             }
 
-            if (action !== undefined) {
-                localVarQueryParameter['action'] = action;
+            if (profilingType !== undefined) {
+                localVarQueryParameter['profiling_type'] = profilingType;
             }
 
 
@@ -1274,17 +1566,16 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Get current alert count from AlertManager
-         * @param {string} address ip:port
+         * Download the finished profiling result of a task
+         * @summary Download the result of a task
+         * @param {string} token download token
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getAlertManagerCounts: async (address: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'address' is not null or undefined
-            assertParamExists('getAlertManagerCounts', 'address', address)
-            const localVarPath = `/topology/alertmanager/{address}/count`
-                .replace(`{${"address"}}`, encodeURIComponent(String(address)));
+        downloadProfilingSingle: async (token: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'token' is not null or undefined
+            assertParamExists('downloadProfilingSingle', 'token', token)
+            const localVarPath = `/profiling/single/download`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1299,6 +1590,10 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
+            if (token !== undefined) {
+                localVarQueryParameter['token'] = token;
+            }
+
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -1311,13 +1606,16 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Get AlertManager instance
+         * Estimate the total size of the profiles of a profiling request from recently finished tasks, without starting it
+         * @summary Estimate the size of a profiling group
+         * @param {ProfilingStartRequest} req profiling request
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getAlertManagerTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/topology/alertmanager`;
+        estimateProfilingGroup: async (req: ProfilingStartRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'req' is not null or undefined
+            assertParamExists('estimateProfilingGroup', 'req', req)
+            const localVarPath = `/profiling/group/estimate`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1325,7 +1623,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 baseOptions = configuration.baseOptions;
             }
 
-            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
@@ -1334,9 +1632,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
 
 
     
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(req, localVarRequestOptions, configuration)
 
             return {
                 url: toPathString(localVarUrlObj),
@@ -1344,13 +1645,15 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Get Grafana instance
+         * Get token with a given group ID or task ID and action type
+         * @summary Get action token for download or view
+         * @param {string} [id] group or task ID
+         * @param {string} [action] action
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getGrafanaTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/topology/grafana`;
+        getActionToken: async (id?: string, action?: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/profiling/action_token`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1365,6 +1668,14 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
+            if (id !== undefined) {
+                localVarQueryParameter['id'] = id;
+            }
+
+            if (action !== undefined) {
+                localVarQueryParameter['action'] = action;
+            }
+
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -1378,12 +1689,16 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary Get all PD instances
+         * @summary Get current alert count from AlertManager
+         * @param {string} address ip:port
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getPDTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/topology/pd`;
+        getAlertManagerCounts: async (address: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'address' is not null or undefined
+            assertParamExists('getAlertManagerCounts', 'address', address)
+            const localVarPath = `/topology/alertmanager/{address}/count`
+                .replace(`{${"address"}}`, encodeURIComponent(String(address)));
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1410,17 +1725,13 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * List all profiling tasks with a given group ID
-         * @summary List all tasks with a given group ID
-         * @param {string} groupId group ID
+         * 
+         * @summary Get AlertManager instance
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getProfilingGroupDetail: async (groupId: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'groupId' is not null or undefined
-            assertParamExists('getProfilingGroupDetail', 'groupId', groupId)
-            const localVarPath = `/profiling/group/detail/{groupId}`
-                .replace(`{${"groupId"}}`, encodeURIComponent(String(groupId)));
+        getAlertManagerTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/topology/alertmanager`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1447,13 +1758,13 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * List all profiling groups
-         * @summary List all profiling groups
+         * 
+         * @summary Get Grafana instance
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getProfilingGroups: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/profiling/group/list`;
+        getGrafanaTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/topology/grafana`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1481,12 +1792,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary Get location labels of all TiKV / TiFlash instances
+         * @summary Get all PD instances
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getStoreLocationTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/topology/store_location`;
+        getPDTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/topology/pd`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1513,13 +1824,13 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Get all TiKV / TiFlash instances
+         * List all registered profiling baselines
+         * @summary List all baselines
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getStoreTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/topology/store`;
+        getProfilingBaselines: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/profiling/baseline/list`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1547,12 +1858,16 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary Get all TiDB instances
+         * @summary Get a profiling campaign with all of its task groups
+         * @param {string} campaignId campaign ID
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getTiDBTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/topology/tidb`;
+        getProfilingCampaignDetail: async (campaignId: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'campaignId' is not null or undefined
+            assertParamExists('getProfilingCampaignDetail', 'campaignId', campaignId)
+            const localVarPath = `/profiling/campaign/detail/{campaignId}`
+                .replace(`{${"campaignId"}}`, encodeURIComponent(String(campaignId)));
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1579,13 +1894,17 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Get information about this TiDB Dashboard
+         * List comments of a profiling task group in the order they are added
+         * @summary List comments of a task group
+         * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        infoGet: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/info/info`;
+        getProfilingGroupComments: async (groupId: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'groupId' is not null or undefined
+            assertParamExists('getProfilingGroupComments', 'groupId', groupId)
+            const localVarPath = `/profiling/group/comments/{groupId}`
+                .replace(`{${"groupId"}}`, encodeURIComponent(String(groupId)));
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1612,13 +1931,19 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary List all databases
+         * List all profiling tasks with a given group ID
+         * @summary List all tasks with a given group ID
+         * @param {string} groupId group ID
+         * @param {boolean} [includeTasks] whether to include the tasks, true by default
+         * @param {Array<number>} [states] only include tasks in these states, all tasks by default
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        infoListDatabases: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/info/databases`;
+        getProfilingGroupDetail: async (groupId: string, includeTasks?: boolean, states?: Array<number>, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'groupId' is not null or undefined
+            assertParamExists('getProfilingGroupDetail', 'groupId', groupId)
+            const localVarPath = `/profiling/group/detail/{groupId}`
+                .replace(`{${"groupId"}}`, encodeURIComponent(String(groupId)));
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1633,6 +1958,14 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
+            if (includeTasks !== undefined) {
+                localVarQueryParameter['include_tasks'] = includeTasks;
+            }
+
+            if (states) {
+                localVarQueryParameter['states'] = states;
+            }
+
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -1645,14 +1978,17 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary List tables by database name
-         * @param {string} [databaseName] Database name
+         * Send the progress of a task group as server-sent events whenever it is changed, until the task group is stopped
+         * @summary Subscribe to the progress of a task group
+         * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        infoListTables: async (databaseName?: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/info/tables`;
+        getProfilingGroupEvents: async (groupId: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'groupId' is not null or undefined
+            assertParamExists('getProfilingGroupEvents', 'groupId', groupId)
+            const localVarPath = `/profiling/group/events/{groupId}`
+                .replace(`{${"groupId"}}`, encodeURIComponent(String(groupId)));
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1667,10 +2003,6 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
-            if (databaseName !== undefined) {
-                localVarQueryParameter['database_name'] = databaseName;
-            }
-
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -1683,13 +2015,19 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Get information about current session
+         * Aggregate all finished profiles of a profiling type in a task group and list the top functions by flat value
+         * @summary Get top functions of a task group
+         * @param {string} groupId group ID
+         * @param {string} [profilingType] profiling type, cpu by default
+         * @param {number} [limit] number of functions to return
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        infoWhoami: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/info/whoami`;
+        getProfilingGroupTop: async (groupId: string, profilingType?: string, limit?: number, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'groupId' is not null or undefined
+            assertParamExists('getProfilingGroupTop', 'groupId', groupId)
+            const localVarPath = `/profiling/group/top/{groupId}`
+                .replace(`{${"groupId"}}`, encodeURIComponent(String(groupId)));
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1704,6 +2042,14 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
+            if (profilingType !== undefined) {
+                localVarQueryParameter['profiling_type'] = profilingType;
+            }
+
+            if (limit !== undefined) {
+                localVarQueryParameter['limit'] = limit;
+            }
+
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -1716,13 +2062,19 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Get Key Visual Dynamic Config
+         * List profiling groups ordered by ID descending, with the total number of groups
+         * @summary List profiling groups by page
+         * @param {Array<string>} [labels] Only list task groups with all of these labels if not empty, each in the form of \&quot;key:value\&quot;.
+         * @param {number} [limit] 
+         * @param {number} [offset] 
+         * @param {Array<string>} [profilingTypes] Only list task groups with tasks of these profiling types if not empty.
+         * @param {Array<number>} [states] Only list task groups in these states if not empty.
+         * @param {string} [triggeredBy] Only list task groups started by this user if not empty.
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        keyvisualConfigGet: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/keyvisual/config`;
+        getProfilingGroupsPaged: async (labels?: Array<string>, limit?: number, offset?: number, profilingTypes?: Array<string>, states?: Array<number>, triggeredBy?: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/profiling/group/paged_list`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1737,6 +2089,30 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
+            if (labels) {
+                localVarQueryParameter['labels'] = labels;
+            }
+
+            if (limit !== undefined) {
+                localVarQueryParameter['limit'] = limit;
+            }
+
+            if (offset !== undefined) {
+                localVarQueryParameter['offset'] = offset;
+            }
+
+            if (profilingTypes) {
+                localVarQueryParameter['profiling_types'] = profilingTypes;
+            }
+
+            if (states) {
+                localVarQueryParameter['states'] = states;
+            }
+
+            if (triggeredBy !== undefined) {
+                localVarQueryParameter['triggered_by'] = triggeredBy;
+            }
+
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -1750,15 +2126,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary Set Key Visual Dynamic Config
-         * @param {ConfigKeyVisualConfig} request Request body
+         * @summary List profiling schedules
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        keyvisualConfigPut: async (request: ConfigKeyVisualConfig, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'request' is not null or undefined
-            assertParamExists('keyvisualConfigPut', 'request', request)
-            const localVarPath = `/keyvisual/config`;
+        getProfilingScheduleList: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/profiling/schedule/list`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1766,7 +2139,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 baseOptions = configuration.baseOptions;
             }
 
-            const localVarRequestOptions = { method: 'PUT', ...baseOptions, ...options};
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
@@ -1775,12 +2148,9 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
 
 
     
-            localVarHeaderParameter['Content-Type'] = 'application/json';
-
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
-            localVarRequestOptions.data = serializeDataIfNeeded(request, localVarRequestOptions, configuration)
 
             return {
                 url: toPathString(localVarUrlObj),
@@ -1788,18 +2158,17 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * Heatmaps in a given range to visualize TiKV usage
-         * @summary Key Visual Heatmaps
-         * @param {string} [startkey] The start of the key range
-         * @param {string} [endkey] The end of the key range
-         * @param {number} [starttime] The start of the time range (Unix)
-         * @param {number} [endtime] The end of the time range (Unix)
-         * @param {'written_bytes' | 'read_bytes' | 'written_keys' | 'read_keys' | 'integration'} [type] Main types of data
+         * Get a lightweight summary of a finished task, e.g. the top functions of a CPU profile
+         * @summary Preview a single profiling result
+         * @param {string} taskId task ID
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        keyvisualHeatmapsGet: async (startkey?: string, endkey?: string, starttime?: number, endtime?: number, type?: 'written_bytes' | 'read_bytes' | 'written_keys' | 'read_keys' | 'integration', options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/keyvisual/heatmaps`;
+        getProfilingSinglePreview: async (taskId: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'taskId' is not null or undefined
+            assertParamExists('getProfilingSinglePreview', 'taskId', taskId)
+            const localVarPath = `/profiling/single/preview/{taskId}`
+                .replace(`{${"taskId"}}`, encodeURIComponent(String(taskId)));
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1814,31 +2183,50 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
-            if (startkey !== undefined) {
-                localVarQueryParameter['startkey'] = startkey;
-            }
 
-            if (endkey !== undefined) {
-                localVarQueryParameter['endkey'] = endkey;
-            }
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
 
-            if (starttime !== undefined) {
-                localVarQueryParameter['starttime'] = starttime;
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * Get multiple tasks with their profiling results in the requested order. Tasks which are not finished are returned without data.
+         * @summary Get the results of tasks
+         * @param {ProfilingTasksDataRequest} req task IDs
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getProfilingTasksData: async (req: ProfilingTasksDataRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'req' is not null or undefined
+            assertParamExists('getProfilingTasksData', 'req', req)
+            const localVarPath = `/profiling/single/data`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
             }
 
-            if (endtime !== undefined) {
-                localVarQueryParameter['endtime'] = endtime;
-            }
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
 
-            if (type !== undefined) {
-                localVarQueryParameter['type'] = type;
-            }
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
 
     
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(req, localVarRequestOptions, configuration)
 
             return {
                 url: toPathString(localVarUrlObj),
@@ -1847,13 +2235,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary Generate a download token for downloading logs
-         * @param {Array<string>} [id] task id
+         * @summary Get location labels of all TiKV / TiFlash instances
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        logsDownloadAcquireTokenGet: async (id?: Array<string>, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/logs/download/acquire_token`;
+        getStoreLocationTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/topology/store_location`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1868,10 +2255,39 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
-            if (id) {
-                localVarQueryParameter['id'] = id.join(COLLECTION_FORMATS.csv);
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Get all TiKV / TiFlash instances
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getStoreTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/topology/store`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
             }
 
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -1885,15 +2301,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary Download logs
-         * @param {string} token download token
+         * @summary Get all TiDB instances
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        logsDownloadGet: async (token: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'token' is not null or undefined
-            assertParamExists('logsDownloadGet', 'token', token)
-            const localVarPath = `/logs/download`;
+        getTiDBTopology: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/topology/tidb`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1905,9 +2318,8 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
-            if (token !== undefined) {
-                localVarQueryParameter['token'] = token;
-            }
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
 
     
@@ -1922,15 +2334,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary Create and run a new log search task group
-         * @param {LogsearchCreateTaskGroupRequest} request Request body
+         * @summary Get information about this TiDB Dashboard
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        logsTaskgroupPut: async (request: LogsearchCreateTaskGroupRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'request' is not null or undefined
-            assertParamExists('logsTaskgroupPut', 'request', request)
-            const localVarPath = `/logs/taskgroup`;
+        infoGet: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/info/info`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1938,21 +2347,670 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 baseOptions = configuration.baseOptions;
             }
 
-            const localVarRequestOptions = { method: 'PUT', ...baseOptions, ...options};
-            const localVarHeaderParameter = {} as any;
-            const localVarQueryParameter = {} as any;
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary List all databases
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        infoListDatabases: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/info/databases`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary List tables by database name
+         * @param {string} [databaseName] Database name
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        infoListTables: async (databaseName?: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/info/tables`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+            if (databaseName !== undefined) {
+                localVarQueryParameter['database_name'] = databaseName;
+            }
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Get information about current session
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        infoWhoami: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/info/whoami`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Get Key Visual Dynamic Config
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        keyvisualConfigGet: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/keyvisual/config`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Set Key Visual Dynamic Config
+         * @param {ConfigKeyVisualConfig} request Request body
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        keyvisualConfigPut: async (request: ConfigKeyVisualConfig, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'request' is not null or undefined
+            assertParamExists('keyvisualConfigPut', 'request', request)
+            const localVarPath = `/keyvisual/config`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'PUT', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(request, localVarRequestOptions, configuration)
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * Heatmaps in a given range to visualize TiKV usage
+         * @summary Key Visual Heatmaps
+         * @param {string} [startkey] The start of the key range
+         * @param {string} [endkey] The end of the key range
+         * @param {number} [starttime] The start of the time range (Unix)
+         * @param {number} [endtime] The end of the time range (Unix)
+         * @param {'written_bytes' | 'read_bytes' | 'written_keys' | 'read_keys' | 'integration'} [type] Main types of data
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        keyvisualHeatmapsGet: async (startkey?: string, endkey?: string, starttime?: number, endtime?: number, type?: 'written_bytes' | 'read_bytes' | 'written_keys' | 'read_keys' | 'integration', options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/keyvisual/heatmaps`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+            if (startkey !== undefined) {
+                localVarQueryParameter['startkey'] = startkey;
+            }
+
+            if (endkey !== undefined) {
+                localVarQueryParameter['endkey'] = endkey;
+            }
+
+            if (starttime !== undefined) {
+                localVarQueryParameter['starttime'] = starttime;
+            }
+
+            if (endtime !== undefined) {
+                localVarQueryParameter['endtime'] = endtime;
+            }
+
+            if (type !== undefined) {
+                localVarQueryParameter['type'] = type;
+            }
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Generate a download token for downloading logs
+         * @param {Array<string>} [id] task id
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        logsDownloadAcquireTokenGet: async (id?: Array<string>, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/logs/download/acquire_token`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+            if (id) {
+                localVarQueryParameter['id'] = id.join(COLLECTION_FORMATS.csv);
+            }
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Download logs
+         * @param {string} token download token
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        logsDownloadGet: async (token: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'token' is not null or undefined
+            assertParamExists('logsDownloadGet', 'token', token)
+            const localVarPath = `/logs/download`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            if (token !== undefined) {
+                localVarQueryParameter['token'] = token;
+            }
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Create and run a new log search task group
+         * @param {LogsearchCreateTaskGroupRequest} request Request body
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        logsTaskgroupPut: async (request: LogsearchCreateTaskGroupRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'request' is not null or undefined
+            assertParamExists('logsTaskgroupPut', 'request', request)
+            const localVarPath = `/logs/taskgroup`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'PUT', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(request, localVarRequestOptions, configuration)
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary List all log search task groups
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        logsTaskgroupsGet: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/logs/taskgroups`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Cancel running tasks in a log search task group
+         * @param {string} id task group id
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        logsTaskgroupsIdCancelPost: async (id: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'id' is not null or undefined
+            assertParamExists('logsTaskgroupsIdCancelPost', 'id', id)
+            const localVarPath = `/logs/taskgroups/{id}/cancel`
+                .replace(`{${"id"}}`, encodeURIComponent(String(id)));
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Delete a log search task group
+         * @param {string} id task group id
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        logsTaskgroupsIdDelete: async (id: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'id' is not null or undefined
+            assertParamExists('logsTaskgroupsIdDelete', 'id', id)
+            const localVarPath = `/logs/taskgroups/{id}`
+                .replace(`{${"id"}}`, encodeURIComponent(String(id)));
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'DELETE', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary List tasks in a log search task group
+         * @param {string} id Task Group ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        logsTaskgroupsIdGet: async (id: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'id' is not null or undefined
+            assertParamExists('logsTaskgroupsIdGet', 'id', id)
+            const localVarPath = `/logs/taskgroups/{id}`
+                .replace(`{${"id"}}`, encodeURIComponent(String(id)));
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Preview a log search task group
+         * @param {string} id task group id
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        logsTaskgroupsIdPreviewGet: async (id: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'id' is not null or undefined
+            assertParamExists('logsTaskgroupsIdPreviewGet', 'id', id)
+            const localVarPath = `/logs/taskgroups/{id}/preview`
+                .replace(`{${"id"}}`, encodeURIComponent(String(id)));
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Retry failed tasks in a log search task group
+         * @param {string} id task group id
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        logsTaskgroupsIdRetryPost: async (id: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'id' is not null or undefined
+            assertParamExists('logsTaskgroupsIdRetryPost', 'id', id)
+            const localVarPath = `/logs/taskgroups/{id}/retry`
+                .replace(`{${"id"}}`, encodeURIComponent(String(id)));
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @summary Get the Prometheus address cluster config
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        metricsGetPromAddress: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/metrics/prom_address`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * Query metrics in the given range
+         * @summary Query metrics
+         * @param {number} [endTimeSec] 
+         * @param {string} [query] 
+         * @param {number} [startTimeSec] 
+         * @param {number} [stepSec] 
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        metricsQueryGet: async (endTimeSec?: number, query?: string, startTimeSec?: number, stepSec?: number, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/metrics/query`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+            if (endTimeSec !== undefined) {
+                localVarQueryParameter['end_time_sec'] = endTimeSec;
+            }
+
+            if (query !== undefined) {
+                localVarQueryParameter['query'] = query;
+            }
+
+            if (startTimeSec !== undefined) {
+                localVarQueryParameter['start_time_sec'] = startTimeSec;
+            }
 
-            // authentication JwtAuth required
-            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+            if (stepSec !== undefined) {
+                localVarQueryParameter['step_sec'] = stepSec;
+            }
 
 
     
-            localVarHeaderParameter['Content-Type'] = 'application/json';
-
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
-            localVarRequestOptions.data = serializeDataIfNeeded(request, localVarRequestOptions, configuration)
 
             return {
                 url: toPathString(localVarUrlObj),
@@ -1961,12 +3019,15 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary List all log search task groups
+         * @summary Set or clear the customized Prometheus address
+         * @param {MetricsPutCustomPromAddressRequest} request Request body
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        logsTaskgroupsGet: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/logs/taskgroups`;
+        metricsSetCustomPromAddress: async (request: MetricsPutCustomPromAddressRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'request' is not null or undefined
+            assertParamExists('metricsSetCustomPromAddress', 'request', request)
+            const localVarPath = `/metrics/prom_address`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -1974,7 +3035,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 baseOptions = configuration.baseOptions;
             }
 
-            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarRequestOptions = { method: 'PUT', ...baseOptions, ...options};
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
@@ -1983,9 +3044,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
 
 
     
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(request, localVarRequestOptions, configuration)
 
             return {
                 url: toPathString(localVarUrlObj),
@@ -1993,17 +3057,16 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Cancel running tasks in a log search task group
-         * @param {string} id task group id
+         * Check whether the status API of each target is reachable before profiling
+         * @summary Check whether profiling targets are reachable
+         * @param {ProfilingPingTargetsRequest} req ping request
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        logsTaskgroupsIdCancelPost: async (id: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'id' is not null or undefined
-            assertParamExists('logsTaskgroupsIdCancelPost', 'id', id)
-            const localVarPath = `/logs/taskgroups/{id}/cancel`
-                .replace(`{${"id"}}`, encodeURIComponent(String(id)));
+        pingProfilingTargets: async (req: ProfilingPingTargetsRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'req' is not null or undefined
+            assertParamExists('pingProfilingTargets', 'req', req)
+            const localVarPath = `/profiling/targets/ping`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2020,9 +3083,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
 
 
     
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(req, localVarRequestOptions, configuration)
 
             return {
                 url: toPathString(localVarUrlObj),
@@ -2031,16 +3097,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary Delete a log search task group
-         * @param {string} id task group id
+         * @summary Get Profiling Dynamic Config
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        logsTaskgroupsIdDelete: async (id: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'id' is not null or undefined
-            assertParamExists('logsTaskgroupsIdDelete', 'id', id)
-            const localVarPath = `/logs/taskgroups/{id}`
-                .replace(`{${"id"}}`, encodeURIComponent(String(id)));
+        profilingConfigGet: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/profiling/config`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2048,7 +3110,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 baseOptions = configuration.baseOptions;
             }
 
-            const localVarRequestOptions = { method: 'DELETE', ...baseOptions, ...options};
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
@@ -2068,16 +3130,15 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary List tasks in a log search task group
-         * @param {string} id Task Group ID
+         * @summary Set Profiling Dynamic Config
+         * @param {ConfigProfilingConfig} request Request body
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        logsTaskgroupsIdGet: async (id: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'id' is not null or undefined
-            assertParamExists('logsTaskgroupsIdGet', 'id', id)
-            const localVarPath = `/logs/taskgroups/{id}`
-                .replace(`{${"id"}}`, encodeURIComponent(String(id)));
+        profilingConfigPut: async (request: ConfigProfilingConfig, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'request' is not null or undefined
+            assertParamExists('profilingConfigPut', 'request', request)
+            const localVarPath = `/profiling/config`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2085,7 +3146,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 baseOptions = configuration.baseOptions;
             }
 
-            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarRequestOptions = { method: 'PUT', ...baseOptions, ...options};
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
@@ -2094,9 +3155,12 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
 
 
     
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(request, localVarRequestOptions, configuration)
 
             return {
                 url: toPathString(localVarUrlObj),
@@ -2104,17 +3168,13 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Preview a log search task group
-         * @param {string} id task group id
+         * List the latest profiling groups, at most 1000 groups are returned. Use the paged list to get more.
+         * @summary List profiling groups
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        logsTaskgroupsIdPreviewGet: async (id: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'id' is not null or undefined
-            assertParamExists('logsTaskgroupsIdPreviewGet', 'id', id)
-            const localVarPath = `/logs/taskgroups/{id}/preview`
-                .replace(`{${"id"}}`, encodeURIComponent(String(id)));
+        profilingGroupListGet: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/profiling/group/list`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2141,17 +3201,13 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Retry failed tasks in a log search task group
-         * @param {string} id task group id
+         * Get the profiling types supported by each kind of component
+         * @summary Get profiling capabilities
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        logsTaskgroupsIdRetryPost: async (id: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'id' is not null or undefined
-            assertParamExists('logsTaskgroupsIdRetryPost', 'id', id)
-            const localVarPath = `/logs/taskgroups/{id}/retry`
-                .replace(`{${"id"}}`, encodeURIComponent(String(id)));
+        profilingTargetsCapabilitiesGet: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/profiling/targets/capabilities`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2159,7 +3215,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 baseOptions = configuration.baseOptions;
             }
 
-            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
+            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
@@ -2178,13 +3234,14 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Get the Prometheus address cluster config
+         * List all components in the cluster which can be profiled
+         * @summary List profiling targets
+         * @param {Array<string>} [kinds] Only list components of these kinds if not empty, so that the topology of other kinds is not fetched.
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        metricsGetPromAddress: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/metrics/prom_address`;
+        profilingTargetsGet: async (kinds?: Array<string>, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/profiling/targets`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2199,6 +3256,10 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
+            if (kinds) {
+                localVarQueryParameter['kinds'] = kinds;
+            }
+
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -2211,17 +3272,14 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * Query metrics in the given range
-         * @summary Query metrics
-         * @param {number} [endTimeSec] 
-         * @param {string} [query] 
-         * @param {number} [startTimeSec] 
-         * @param {number} [stepSec] 
+         * Resolve all current components of the kinds to profiling targets, which can be used to start profiling
+         * @summary Resolve profiling targets
+         * @param {Array<string>} [kinds] 
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        metricsQueryGet: async (endTimeSec?: number, query?: string, startTimeSec?: number, stepSec?: number, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/metrics/query`;
+        profilingTargetsResolveGet: async (kinds?: Array<string>, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            const localVarPath = `/profiling/targets/resolve`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2236,20 +3294,8 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             // authentication JwtAuth required
             await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
 
-            if (endTimeSec !== undefined) {
-                localVarQueryParameter['end_time_sec'] = endTimeSec;
-            }
-
-            if (query !== undefined) {
-                localVarQueryParameter['query'] = query;
-            }
-
-            if (startTimeSec !== undefined) {
-                localVarQueryParameter['start_time_sec'] = startTimeSec;
-            }
-
-            if (stepSec !== undefined) {
-                localVarQueryParameter['step_sec'] = stepSec;
+            if (kinds) {
+                localVarQueryParameter['kinds'] = kinds;
             }
 
 
@@ -2265,15 +3311,15 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
         },
         /**
          * 
-         * @summary Set or clear the customized Prometheus address
-         * @param {MetricsPutCustomPromAddressRequest} request Request body
+         * @summary Run statements
+         * @param {QueryeditorRunRequest} request Request body
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        metricsSetCustomPromAddress: async (request: MetricsPutCustomPromAddressRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+        queryEditorRun: async (request: QueryeditorRunRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
             // verify required parameter 'request' is not null or undefined
-            assertParamExists('metricsSetCustomPromAddress', 'request', request)
-            const localVarPath = `/metrics/prom_address`;
+            assertParamExists('queryEditorRun', 'request', request)
+            const localVarPath = `/query_editor/run`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2281,7 +3327,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 baseOptions = configuration.baseOptions;
             }
 
-            const localVarRequestOptions = { method: 'PUT', ...baseOptions, ...options};
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
@@ -2303,13 +3349,17 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Get Profiling Dynamic Config
+         * Profile the target of a stopped task again and replace its result in place. The task ID does not change. A result registered as a baseline cannot be refreshed.
+         * @summary Profile a single task again
+         * @param {string} taskId task ID
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        profilingConfigGet: async (options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            const localVarPath = `/profiling/config`;
+        refreshProfilingSingle: async (taskId: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'taskId' is not null or undefined
+            assertParamExists('refreshProfilingSingle', 'taskId', taskId)
+            const localVarPath = `/profiling/single/refresh/{taskId}`
+                .replace(`{${"taskId"}}`, encodeURIComponent(String(taskId)));
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2317,7 +3367,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 baseOptions = configuration.baseOptions;
             }
 
-            const localVarRequestOptions = { method: 'GET', ...baseOptions, ...options};
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
@@ -2336,16 +3386,16 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Set Profiling Dynamic Config
-         * @param {ConfigProfilingConfig} request Request body
+         * Register a finished profiling result as a named baseline. An existing baseline with the same name is replaced.
+         * @summary Register a baseline
+         * @param {ProfilingRegisterBaselineRequest} req baseline request
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        profilingConfigPut: async (request: ConfigProfilingConfig, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'request' is not null or undefined
-            assertParamExists('profilingConfigPut', 'request', request)
-            const localVarPath = `/profiling/config`;
+        registerProfilingBaseline: async (req: ProfilingRegisterBaselineRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'req' is not null or undefined
+            assertParamExists('registerProfilingBaseline', 'req', req)
+            const localVarPath = `/profiling/baseline/register`;
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2353,7 +3403,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 baseOptions = configuration.baseOptions;
             }
 
-            const localVarRequestOptions = { method: 'PUT', ...baseOptions, ...options};
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
@@ -2367,7 +3417,7 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
-            localVarRequestOptions.data = serializeDataIfNeeded(request, localVarRequestOptions, configuration)
+            localVarRequestOptions.data = serializeDataIfNeeded(req, localVarRequestOptions, configuration)
 
             return {
                 url: toPathString(localVarUrlObj),
@@ -2375,16 +3425,17 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * 
-         * @summary Run statements
-         * @param {QueryeditorRunRequest} request Request body
+         * Profile the targets of failed tasks in a stopped group again. Finished tasks are kept.
+         * @summary Profile failed tasks of a group again
+         * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        queryEditorRun: async (request: QueryeditorRunRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
-            // verify required parameter 'request' is not null or undefined
-            assertParamExists('queryEditorRun', 'request', request)
-            const localVarPath = `/query_editor/run`;
+        retryProfilingGroup: async (groupId: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'groupId' is not null or undefined
+            assertParamExists('retryProfilingGroup', 'groupId', groupId)
+            const localVarPath = `/profiling/group/retry/{groupId}`
+                .replace(`{${"groupId"}}`, encodeURIComponent(String(groupId)));
             // use dummy base URL string because the URL constructor only accepts absolute URLs.
             const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
             let baseOptions;
@@ -2401,12 +3452,9 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
 
 
     
-            localVarHeaderParameter['Content-Type'] = 'application/json';
-
             setSearchParams(localVarUrlObj, localVarQueryParameter);
             let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
             localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
-            localVarRequestOptions.data = serializeDataIfNeeded(request, localVarRequestOptions, configuration)
 
             return {
                 url: toPathString(localVarUrlObj),
@@ -2680,6 +3728,45 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
 
 
     
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
+            setSearchParams(localVarUrlObj, localVarQueryParameter);
+            let headersFromBaseOptions = baseOptions && baseOptions.headers ? baseOptions.headers : {};
+            localVarRequestOptions.headers = {...localVarHeaderParameter, ...headersFromBaseOptions, ...options.headers};
+            localVarRequestOptions.data = serializeDataIfNeeded(req, localVarRequestOptions, configuration)
+
+            return {
+                url: toPathString(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * Run the same profiling request several times with a gap. Each iteration is a task group.
+         * @summary Start a profiling campaign
+         * @param {ProfilingStartCampaignRequest} req profiling campaign request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        startProfilingCampaign: async (req: ProfilingStartCampaignRequest, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+            // verify required parameter 'req' is not null or undefined
+            assertParamExists('startProfilingCampaign', 'req', req)
+            const localVarPath = `/profiling/campaign/start`;
+            // use dummy base URL string because the URL constructor only accepts absolute URLs.
+            const localVarUrlObj = new URL(localVarPath, DUMMY_BASE_URL);
+            let baseOptions;
+            if (configuration) {
+                baseOptions = configuration.baseOptions;
+            }
+
+            const localVarRequestOptions = { method: 'POST', ...baseOptions, ...options};
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication JwtAuth required
+            await setApiKeyToObject(localVarHeaderParameter, "Authorization", configuration)
+
+
+    
             localVarHeaderParameter['Content-Type'] = 'application/json';
 
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -3628,13 +4715,18 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
             };
         },
         /**
-         * View the finished profiling result of a task
+         * View the finished profiling result of a task, or the bytes received so far by a running task with the raw output type
          * @summary View the result of a task
          * @param {string} token download token
+         * @param {string} [outputType] output type, e.g. graph, flamegraph, top, raw, protobuf or text
+         * @param {number} [topN] number of functions in the top report, 30 by default
+         * @param {number} [offset] offset of the raw output in bytes
+         * @param {number} [length] maximum length of the raw output in bytes, all bytes from the offset by default
+         * @param {boolean} [decompress] output a gzipped protobuf profile as plain protobuf
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        viewProfilingSingle: async (token: string, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
+        viewProfilingSingle: async (token: string, outputType?: string, topN?: number, offset?: number, length?: number, decompress?: boolean, options: AxiosRequestConfig = {}): Promise<RequestArgs> => {
             // verify required parameter 'token' is not null or undefined
             assertParamExists('viewProfilingSingle', 'token', token)
             const localVarPath = `/profiling/single/view`;
@@ -3656,6 +4748,26 @@ export const DefaultApiAxiosParamCreator = function (configuration?: Configurati
                 localVarQueryParameter['token'] = token;
             }
 
+            if (outputType !== undefined) {
+                localVarQueryParameter['output_type'] = outputType;
+            }
+
+            if (topN !== undefined) {
+                localVarQueryParameter['top_n'] = topN;
+            }
+
+            if (offset !== undefined) {
+                localVarQueryParameter['offset'] = offset;
+            }
+
+            if (length !== undefined) {
+                localVarQueryParameter['length'] = length;
+            }
+
+            if (decompress !== undefined) {
+                localVarQueryParameter['decompress'] = decompress;
+            }
+
 
     
             setSearchParams(localVarUrlObj, localVarQueryParameter);
@@ -3678,7 +4790,19 @@ export const DefaultApiFp = function(configuration?: Configuration) {
     const localVarAxiosParamCreator = DefaultApiAxiosParamCreator(configuration)
     return {
         /**
-         * Cancel all profling tasks with a given group ID
+         * Add a comment to a profiling task group. Comments cannot be modified once added.
+         * @summary Add a comment to a task group
+         * @param {string} groupId group ID
+         * @param {ProfilingAddGroupCommentRequest} req comment request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async addProfilingGroupComment(groupId: string, req: ProfilingAddGroupCommentRequest, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingTaskGroupCommentModel>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.addProfilingGroupComment(groupId, req, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * Cancel all running profling tasks with a given group ID. Results of finished tasks are kept.
          * @summary Cancel all tasks with a given group ID
          * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
@@ -3688,6 +4812,18 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             const localVarAxiosArgs = await localVarAxiosParamCreator.cancelProfilingGroup(groupId, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
+        /**
+         * Start a new group profiling the same targets with the same profiling types as a stopped group. The original group is kept.
+         * @summary Profile the targets of a group again
+         * @param {string} groupId group ID
+         * @param {ProfilingCloneGroupRequest} req clone request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async cloneProfilingGroup(groupId: string, req: ProfilingCloneGroupRequest, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingTaskGroupModel>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.cloneProfilingGroup(groupId, req, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
         /**
          * 
          * @summary Get information of all hosts
@@ -3708,6 +4844,19 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             const localVarAxiosArgs = await localVarAxiosParamCreator.clusterInfoGetStatistics(options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
+        /**
+         * Compare the profiling result of a task with a baseline and report functions regressed more than the threshold
+         * @summary Compare a task with a baseline
+         * @param {string} taskId task ID
+         * @param {string} baseline baseline name
+         * @param {number} [threshold] regression threshold in share of total samples, e.g. 0.05
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async compareProfilingToBaseline(taskId: string, baseline: string, threshold?: number, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingBaselineCompareResult>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.compareProfilingToBaseline(taskId, baseline, threshold, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
         /**
          * 
          * @summary Edit a configuration
@@ -3829,6 +4978,17 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             const localVarAxiosArgs = await localVarAxiosParamCreator.continuousProfilingSingleProfileViewGet(address, component, profileType, ts, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
+        /**
+         * Profile with the same request every interval. Each run is a task group linked by the schedule ID.
+         * @summary Create a profiling schedule
+         * @param {ProfilingCreateScheduleRequest} req schedule request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async createProfilingSchedule(req: ProfilingCreateScheduleRequest, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingScheduleModel>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.createProfilingSchedule(req, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
         /**
          * 
          * @summary List all deadlock records
@@ -3872,7 +5032,7 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
         /**
-         * Delete all finished profiling tasks with a given group ID
+         * Delete all profiling tasks and results with a given group ID. A running group must be cancelled first. Baselines registered from its results are deleted with it.
          * @summary Delete all tasks with a given group ID
          * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
@@ -3882,6 +5042,17 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             const localVarAxiosArgs = await localVarAxiosParamCreator.deleteProfilingGroup(groupId, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
+        /**
+         * Stop running a profiling schedule. Task groups already started by the schedule are kept.
+         * @summary Delete a profiling schedule
+         * @param {string} scheduleId schedule ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async deleteProfilingSchedule(scheduleId: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<object>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.deleteProfilingSchedule(scheduleId, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
         /**
          * Generate sql diagnosis report
          * @summary SQL diagnosis report
@@ -3969,15 +5140,44 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             const localVarAxiosArgs = await localVarAxiosParamCreator.diagnoseReportsPost(request, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
+        /**
+         * Download the profiling results of each target merged across all iterations of a campaign
+         * @summary Download the merged results of a campaign
+         * @param {string} token download token
+         * @param {string} [profilingType] profiling type, cpu by default
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async downloadProfilingCampaign(token: string, profilingType?: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<void>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.downloadProfilingCampaign(token, profilingType, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
         /**
          * Download all finished profiling results of a task group
          * @summary Download all results of a task group
          * @param {string} token download token
+         * @param {boolean} [autoDelete] delete the task group after the results are fully exported, which is not allowed with filters. The token must be issued for group_download_auto_delete
+         * @param {Array<string>} [kinds] 
+         * @param {Array<string>} [profilingTypes] 
+         * @param {Array<string>} [targets] Addresses of targets in the form of \&quot;ip:port\&quot;
+         * @param {boolean} [mergeCpu] also download the finished CPU profiles merged into a single profile
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async downloadProfilingGroup(token: string, autoDelete?: boolean, kinds?: Array<string>, profilingTypes?: Array<string>, targets?: Array<string>, mergeCpu?: boolean, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<void>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.downloadProfilingGroup(token, autoDelete, kinds, profilingTypes, targets, mergeCpu, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * Download the finished profiling results of a profiling type across all targets of a task group merged into a single profile. Samples are labeled with their source instance and component.
+         * @summary Download the merged result of a task group
+         * @param {string} token download token of the task group
+         * @param {string} [profilingType] profiling type, cpu by default
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        async downloadProfilingGroup(token: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<void>> {
-            const localVarAxiosArgs = await localVarAxiosParamCreator.downloadProfilingGroup(token, options);
+        async downloadProfilingGroupMerged(token: string, profilingType?: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<void>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.downloadProfilingGroupMerged(token, profilingType, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
         /**
@@ -3987,8 +5187,19 @@ export const DefaultApiFp = function(configuration?: Configuration) {
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        async downloadProfilingSingle(token: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<void>> {
-            const localVarAxiosArgs = await localVarAxiosParamCreator.downloadProfilingSingle(token, options);
+        async downloadProfilingSingle(token: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<void>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.downloadProfilingSingle(token, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * Estimate the total size of the profiles of a profiling request from recently finished tasks, without starting it
+         * @summary Estimate the size of a profiling group
+         * @param {ProfilingStartRequest} req profiling request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async estimateProfilingGroup(req: ProfilingStartRequest, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingEstimateGroupResponse>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.estimateProfilingGroup(req, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
         /**
@@ -4044,25 +5255,121 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             const localVarAxiosArgs = await localVarAxiosParamCreator.getPDTopology(options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
+        /**
+         * List all registered profiling baselines
+         * @summary List all baselines
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async getProfilingBaselines(options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<Array<ProfilingBaselineModel>>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingBaselines(options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * 
+         * @summary Get a profiling campaign with all of its task groups
+         * @param {string} campaignId campaign ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async getProfilingCampaignDetail(campaignId: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingCampaignDetailResponse>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingCampaignDetail(campaignId, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * List comments of a profiling task group in the order they are added
+         * @summary List comments of a task group
+         * @param {string} groupId group ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async getProfilingGroupComments(groupId: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<Array<ProfilingTaskGroupCommentModel>>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingGroupComments(groupId, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
         /**
          * List all profiling tasks with a given group ID
          * @summary List all tasks with a given group ID
          * @param {string} groupId group ID
+         * @param {boolean} [includeTasks] whether to include the tasks, true by default
+         * @param {Array<number>} [states] only include tasks in these states, all tasks by default
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async getProfilingGroupDetail(groupId: string, includeTasks?: boolean, states?: Array<number>, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingGroupDetailResponse>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingGroupDetail(groupId, includeTasks, states, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * Send the progress of a task group as server-sent events whenever it is changed, until the task group is stopped
+         * @summary Subscribe to the progress of a task group
+         * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        async getProfilingGroupDetail(groupId: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingGroupDetailResponse>> {
-            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingGroupDetail(groupId, options);
+        async getProfilingGroupEvents(groupId: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingGroupProgressEvent>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingGroupEvents(groupId, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
         /**
-         * List all profiling groups
-         * @summary List all profiling groups
+         * Aggregate all finished profiles of a profiling type in a task group and list the top functions by flat value
+         * @summary Get top functions of a task group
+         * @param {string} groupId group ID
+         * @param {string} [profilingType] profiling type, cpu by default
+         * @param {number} [limit] number of functions to return
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async getProfilingGroupTop(groupId: string, profilingType?: string, limit?: number, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingGroupTopResponse>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingGroupTop(groupId, profilingType, limit, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * List profiling groups ordered by ID descending, with the total number of groups
+         * @summary List profiling groups by page
+         * @param {Array<string>} [labels] Only list task groups with all of these labels if not empty, each in the form of \&quot;key:value\&quot;.
+         * @param {number} [limit] 
+         * @param {number} [offset] 
+         * @param {Array<string>} [profilingTypes] Only list task groups with tasks of these profiling types if not empty.
+         * @param {Array<number>} [states] Only list task groups in these states if not empty.
+         * @param {string} [triggeredBy] Only list task groups started by this user if not empty.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async getProfilingGroupsPaged(labels?: Array<string>, limit?: number, offset?: number, profilingTypes?: Array<string>, states?: Array<number>, triggeredBy?: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingListGroupsResponse>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingGroupsPaged(labels, limit, offset, profilingTypes, states, triggeredBy, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * 
+         * @summary List profiling schedules
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async getProfilingScheduleList(options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<Array<ProfilingScheduleModel>>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingScheduleList(options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * Get a lightweight summary of a finished task, e.g. the top functions of a CPU profile
+         * @summary Preview a single profiling result
+         * @param {string} taskId task ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async getProfilingSinglePreview(taskId: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingProfilePreview>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingSinglePreview(taskId, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * Get multiple tasks with their profiling results in the requested order. Tasks which are not finished are returned without data.
+         * @summary Get the results of tasks
+         * @param {ProfilingTasksDataRequest} req task IDs
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        async getProfilingGroups(options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<Array<ProfilingTaskGroupModel>>> {
-            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingGroups(options);
+        async getProfilingTasksData(req: ProfilingTasksDataRequest, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<Array<ProfilingTaskWithData>>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.getProfilingTasksData(req, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
         /**
@@ -4305,6 +5612,17 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             const localVarAxiosArgs = await localVarAxiosParamCreator.metricsSetCustomPromAddress(request, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
+        /**
+         * Check whether the status API of each target is reachable before profiling
+         * @summary Check whether profiling targets are reachable
+         * @param {ProfilingPingTargetsRequest} req ping request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async pingProfilingTargets(req: ProfilingPingTargetsRequest, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<Array<ProfilingTargetReachability>>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.pingProfilingTargets(req, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
         /**
          * 
          * @summary Get Profiling Dynamic Config
@@ -4326,6 +5644,48 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             const localVarAxiosArgs = await localVarAxiosParamCreator.profilingConfigPut(request, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
+        /**
+         * List the latest profiling groups, at most 1000 groups are returned. Use the paged list to get more.
+         * @summary List profiling groups
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async profilingGroupListGet(options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<Array<ProfilingTaskGroupModel>>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.profilingGroupListGet(options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * Get the profiling types supported by each kind of component
+         * @summary Get profiling capabilities
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async profilingTargetsCapabilitiesGet(options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingKindCapabilitiesResponse>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.profilingTargetsCapabilitiesGet(options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * List all components in the cluster which can be profiled
+         * @summary List profiling targets
+         * @param {Array<string>} [kinds] Only list components of these kinds if not empty, so that the topology of other kinds is not fetched.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async profilingTargetsGet(kinds?: Array<string>, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<Array<ModelRequestTargetNode>>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.profilingTargetsGet(kinds, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * Resolve all current components of the kinds to profiling targets, which can be used to start profiling
+         * @summary Resolve profiling targets
+         * @param {Array<string>} [kinds] 
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async profilingTargetsResolveGet(kinds?: Array<string>, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<Array<ModelRequestTargetNode>>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.profilingTargetsResolveGet(kinds, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
         /**
          * 
          * @summary Run statements
@@ -4337,6 +5697,39 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             const localVarAxiosArgs = await localVarAxiosParamCreator.queryEditorRun(request, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
+        /**
+         * Profile the target of a stopped task again and replace its result in place. The task ID does not change. A result registered as a baseline cannot be refreshed.
+         * @summary Profile a single task again
+         * @param {string} taskId task ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async refreshProfilingSingle(taskId: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingTaskModel>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.refreshProfilingSingle(taskId, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * Register a finished profiling result as a named baseline. An existing baseline with the same name is replaced.
+         * @summary Register a baseline
+         * @param {ProfilingRegisterBaselineRequest} req baseline request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async registerProfilingBaseline(req: ProfilingRegisterBaselineRequest, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingBaselineModel>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.registerProfilingBaseline(req, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
+        /**
+         * Profile the targets of failed tasks in a stopped group again. Finished tasks are kept.
+         * @summary Profile failed tasks of a group again
+         * @param {string} groupId group ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async retryProfilingGroup(groupId: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<Array<ProfilingTaskModel>>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.retryProfilingGroup(groupId, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
         /**
          * Get available field names by slowquery table columns
          * @summary Get available field names
@@ -4413,6 +5806,17 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             const localVarAxiosArgs = await localVarAxiosParamCreator.startProfiling(req, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
+        /**
+         * Run the same profiling request several times with a gap. Each iteration is a task group.
+         * @summary Start a profiling campaign
+         * @param {ProfilingStartCampaignRequest} req profiling campaign request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        async startProfilingCampaign(req: ProfilingStartCampaignRequest, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<ProfilingCampaignModel>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.startProfilingCampaign(req, options);
+            return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
+        },
         /**
          * Get available field names by statements table columns
          * @summary Get available field names
@@ -4680,14 +6084,19 @@ export const DefaultApiFp = function(configuration?: Configuration) {
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
         /**
-         * View the finished profiling result of a task
+         * View the finished profiling result of a task, or the bytes received so far by a running task with the raw output type
          * @summary View the result of a task
          * @param {string} token download token
+         * @param {string} [outputType] output type, e.g. graph, flamegraph, top, raw, protobuf or text
+         * @param {number} [topN] number of functions in the top report, 30 by default
+         * @param {number} [offset] offset of the raw output in bytes
+         * @param {number} [length] maximum length of the raw output in bytes, all bytes from the offset by default
+         * @param {boolean} [decompress] output a gzipped protobuf profile as plain protobuf
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        async viewProfilingSingle(token: string, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<void>> {
-            const localVarAxiosArgs = await localVarAxiosParamCreator.viewProfilingSingle(token, options);
+        async viewProfilingSingle(token: string, outputType?: string, topN?: number, offset?: number, length?: number, decompress?: boolean, options?: AxiosRequestConfig): Promise<(axios?: AxiosInstance, basePath?: string) => AxiosPromise<void>> {
+            const localVarAxiosArgs = await localVarAxiosParamCreator.viewProfilingSingle(token, outputType, topN, offset, length, decompress, options);
             return createRequestFunction(localVarAxiosArgs, globalAxios, BASE_PATH, configuration);
         },
     }
@@ -4701,7 +6110,18 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
     const localVarFp = DefaultApiFp(configuration)
     return {
         /**
-         * Cancel all profling tasks with a given group ID
+         * Add a comment to a profiling task group. Comments cannot be modified once added.
+         * @summary Add a comment to a task group
+         * @param {string} groupId group ID
+         * @param {ProfilingAddGroupCommentRequest} req comment request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        addProfilingGroupComment(groupId: string, req: ProfilingAddGroupCommentRequest, options?: any): AxiosPromise<ProfilingTaskGroupCommentModel> {
+            return localVarFp.addProfilingGroupComment(groupId, req, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * Cancel all running profling tasks with a given group ID. Results of finished tasks are kept.
          * @summary Cancel all tasks with a given group ID
          * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
@@ -4710,6 +6130,17 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
         cancelProfilingGroup(groupId: string, options?: any): AxiosPromise<object> {
             return localVarFp.cancelProfilingGroup(groupId, options).then((request) => request(axios, basePath));
         },
+        /**
+         * Start a new group profiling the same targets with the same profiling types as a stopped group. The original group is kept.
+         * @summary Profile the targets of a group again
+         * @param {string} groupId group ID
+         * @param {ProfilingCloneGroupRequest} req clone request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        cloneProfilingGroup(groupId: string, req: ProfilingCloneGroupRequest, options?: any): AxiosPromise<ProfilingTaskGroupModel> {
+            return localVarFp.cloneProfilingGroup(groupId, req, options).then((request) => request(axios, basePath));
+        },
         /**
          * 
          * @summary Get information of all hosts
@@ -4728,6 +6159,18 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
         clusterInfoGetStatistics(options?: any): AxiosPromise<ClusterinfoClusterStatistics> {
             return localVarFp.clusterInfoGetStatistics(options).then((request) => request(axios, basePath));
         },
+        /**
+         * Compare the profiling result of a task with a baseline and report functions regressed more than the threshold
+         * @summary Compare a task with a baseline
+         * @param {string} taskId task ID
+         * @param {string} baseline baseline name
+         * @param {number} [threshold] regression threshold in share of total samples, e.g. 0.05
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        compareProfilingToBaseline(taskId: string, baseline: string, threshold?: number, options?: any): AxiosPromise<ProfilingBaselineCompareResult> {
+            return localVarFp.compareProfilingToBaseline(taskId, baseline, threshold, options).then((request) => request(axios, basePath));
+        },
         /**
          * 
          * @summary Edit a configuration
@@ -4838,6 +6281,16 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
         continuousProfilingSingleProfileViewGet(address?: string, component?: string, profileType?: string, ts?: number, options?: any): AxiosPromise<void> {
             return localVarFp.continuousProfilingSingleProfileViewGet(address, component, profileType, ts, options).then((request) => request(axios, basePath));
         },
+        /**
+         * Profile with the same request every interval. Each run is a task group linked by the schedule ID.
+         * @summary Create a profiling schedule
+         * @param {ProfilingCreateScheduleRequest} req schedule request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        createProfilingSchedule(req: ProfilingCreateScheduleRequest, options?: any): AxiosPromise<ProfilingScheduleModel> {
+            return localVarFp.createProfilingSchedule(req, options).then((request) => request(axios, basePath));
+        },
         /**
          * 
          * @summary List all deadlock records
@@ -4877,7 +6330,7 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
             return localVarFp.debugApiDownloadGet(token, options).then((request) => request(axios, basePath));
         },
         /**
-         * Delete all finished profiling tasks with a given group ID
+         * Delete all profiling tasks and results with a given group ID. A running group must be cancelled first. Baselines registered from its results are deleted with it.
          * @summary Delete all tasks with a given group ID
          * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
@@ -4886,6 +6339,16 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
         deleteProfilingGroup(groupId: string, options?: any): AxiosPromise<object> {
             return localVarFp.deleteProfilingGroup(groupId, options).then((request) => request(axios, basePath));
         },
+        /**
+         * Stop running a profiling schedule. Task groups already started by the schedule are kept.
+         * @summary Delete a profiling schedule
+         * @param {string} scheduleId schedule ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        deleteProfilingSchedule(scheduleId: string, options?: any): AxiosPromise<object> {
+            return localVarFp.deleteProfilingSchedule(scheduleId, options).then((request) => request(axios, basePath));
+        },
         /**
          * Generate sql diagnosis report
          * @summary SQL diagnosis report
@@ -4965,15 +6428,42 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
         diagnoseReportsPost(request: DiagnoseGenerateReportRequest, options?: any): AxiosPromise<number> {
             return localVarFp.diagnoseReportsPost(request, options).then((request) => request(axios, basePath));
         },
+        /**
+         * Download the profiling results of each target merged across all iterations of a campaign
+         * @summary Download the merged results of a campaign
+         * @param {string} token download token
+         * @param {string} [profilingType] profiling type, cpu by default
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        downloadProfilingCampaign(token: string, profilingType?: string, options?: any): AxiosPromise<void> {
+            return localVarFp.downloadProfilingCampaign(token, profilingType, options).then((request) => request(axios, basePath));
+        },
         /**
          * Download all finished profiling results of a task group
          * @summary Download all results of a task group
          * @param {string} token download token
+         * @param {boolean} [autoDelete] delete the task group after the results are fully exported, which is not allowed with filters. The token must be issued for group_download_auto_delete
+         * @param {Array<string>} [kinds] 
+         * @param {Array<string>} [profilingTypes] 
+         * @param {Array<string>} [targets] Addresses of targets in the form of \&quot;ip:port\&quot;
+         * @param {boolean} [mergeCpu] also download the finished CPU profiles merged into a single profile
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        downloadProfilingGroup(token: string, autoDelete?: boolean, kinds?: Array<string>, profilingTypes?: Array<string>, targets?: Array<string>, mergeCpu?: boolean, options?: any): AxiosPromise<void> {
+            return localVarFp.downloadProfilingGroup(token, autoDelete, kinds, profilingTypes, targets, mergeCpu, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * Download the finished profiling results of a profiling type across all targets of a task group merged into a single profile. Samples are labeled with their source instance and component.
+         * @summary Download the merged result of a task group
+         * @param {string} token download token of the task group
+         * @param {string} [profilingType] profiling type, cpu by default
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        downloadProfilingGroup(token: string, options?: any): AxiosPromise<void> {
-            return localVarFp.downloadProfilingGroup(token, options).then((request) => request(axios, basePath));
+        downloadProfilingGroupMerged(token: string, profilingType?: string, options?: any): AxiosPromise<void> {
+            return localVarFp.downloadProfilingGroupMerged(token, profilingType, options).then((request) => request(axios, basePath));
         },
         /**
          * Download the finished profiling result of a task
@@ -4985,6 +6475,16 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
         downloadProfilingSingle(token: string, options?: any): AxiosPromise<void> {
             return localVarFp.downloadProfilingSingle(token, options).then((request) => request(axios, basePath));
         },
+        /**
+         * Estimate the total size of the profiles of a profiling request from recently finished tasks, without starting it
+         * @summary Estimate the size of a profiling group
+         * @param {ProfilingStartRequest} req profiling request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        estimateProfilingGroup(req: ProfilingStartRequest, options?: any): AxiosPromise<ProfilingEstimateGroupResponse> {
+            return localVarFp.estimateProfilingGroup(req, options).then((request) => request(axios, basePath));
+        },
         /**
          * Get token with a given group ID or task ID and action type
          * @summary Get action token for download or view
@@ -5033,24 +6533,112 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
         getPDTopology(options?: any): AxiosPromise<Array<TopologyPDInfo>> {
             return localVarFp.getPDTopology(options).then((request) => request(axios, basePath));
         },
+        /**
+         * List all registered profiling baselines
+         * @summary List all baselines
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getProfilingBaselines(options?: any): AxiosPromise<Array<ProfilingBaselineModel>> {
+            return localVarFp.getProfilingBaselines(options).then((request) => request(axios, basePath));
+        },
+        /**
+         * 
+         * @summary Get a profiling campaign with all of its task groups
+         * @param {string} campaignId campaign ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getProfilingCampaignDetail(campaignId: string, options?: any): AxiosPromise<ProfilingCampaignDetailResponse> {
+            return localVarFp.getProfilingCampaignDetail(campaignId, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * List comments of a profiling task group in the order they are added
+         * @summary List comments of a task group
+         * @param {string} groupId group ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getProfilingGroupComments(groupId: string, options?: any): AxiosPromise<Array<ProfilingTaskGroupCommentModel>> {
+            return localVarFp.getProfilingGroupComments(groupId, options).then((request) => request(axios, basePath));
+        },
         /**
          * List all profiling tasks with a given group ID
          * @summary List all tasks with a given group ID
          * @param {string} groupId group ID
+         * @param {boolean} [includeTasks] whether to include the tasks, true by default
+         * @param {Array<number>} [states] only include tasks in these states, all tasks by default
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getProfilingGroupDetail(groupId: string, includeTasks?: boolean, states?: Array<number>, options?: any): AxiosPromise<ProfilingGroupDetailResponse> {
+            return localVarFp.getProfilingGroupDetail(groupId, includeTasks, states, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * Send the progress of a task group as server-sent events whenever it is changed, until the task group is stopped
+         * @summary Subscribe to the progress of a task group
+         * @param {string} groupId group ID
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getProfilingGroupEvents(groupId: string, options?: any): AxiosPromise<ProfilingGroupProgressEvent> {
+            return localVarFp.getProfilingGroupEvents(groupId, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * Aggregate all finished profiles of a profiling type in a task group and list the top functions by flat value
+         * @summary Get top functions of a task group
+         * @param {string} groupId group ID
+         * @param {string} [profilingType] profiling type, cpu by default
+         * @param {number} [limit] number of functions to return
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getProfilingGroupTop(groupId: string, profilingType?: string, limit?: number, options?: any): AxiosPromise<ProfilingGroupTopResponse> {
+            return localVarFp.getProfilingGroupTop(groupId, profilingType, limit, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * List profiling groups ordered by ID descending, with the total number of groups
+         * @summary List profiling groups by page
+         * @param {Array<string>} [labels] Only list task groups with all of these labels if not empty, each in the form of \&quot;key:value\&quot;.
+         * @param {number} [limit] 
+         * @param {number} [offset] 
+         * @param {Array<string>} [profilingTypes] Only list task groups with tasks of these profiling types if not empty.
+         * @param {Array<number>} [states] Only list task groups in these states if not empty.
+         * @param {string} [triggeredBy] Only list task groups started by this user if not empty.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getProfilingGroupsPaged(labels?: Array<string>, limit?: number, offset?: number, profilingTypes?: Array<string>, states?: Array<number>, triggeredBy?: string, options?: any): AxiosPromise<ProfilingListGroupsResponse> {
+            return localVarFp.getProfilingGroupsPaged(labels, limit, offset, profilingTypes, states, triggeredBy, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * 
+         * @summary List profiling schedules
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getProfilingScheduleList(options?: any): AxiosPromise<Array<ProfilingScheduleModel>> {
+            return localVarFp.getProfilingScheduleList(options).then((request) => request(axios, basePath));
+        },
+        /**
+         * Get a lightweight summary of a finished task, e.g. the top functions of a CPU profile
+         * @summary Preview a single profiling result
+         * @param {string} taskId task ID
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getProfilingGroupDetail(groupId: string, options?: any): AxiosPromise<ProfilingGroupDetailResponse> {
-            return localVarFp.getProfilingGroupDetail(groupId, options).then((request) => request(axios, basePath));
+        getProfilingSinglePreview(taskId: string, options?: any): AxiosPromise<ProfilingProfilePreview> {
+            return localVarFp.getProfilingSinglePreview(taskId, options).then((request) => request(axios, basePath));
         },
         /**
-         * List all profiling groups
-         * @summary List all profiling groups
+         * Get multiple tasks with their profiling results in the requested order. Tasks which are not finished are returned without data.
+         * @summary Get the results of tasks
+         * @param {ProfilingTasksDataRequest} req task IDs
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        getProfilingGroups(options?: any): AxiosPromise<Array<ProfilingTaskGroupModel>> {
-            return localVarFp.getProfilingGroups(options).then((request) => request(axios, basePath));
+        getProfilingTasksData(req: ProfilingTasksDataRequest, options?: any): AxiosPromise<Array<ProfilingTaskWithData>> {
+            return localVarFp.getProfilingTasksData(req, options).then((request) => request(axios, basePath));
         },
         /**
          * 
@@ -5271,33 +6859,111 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
             return localVarFp.metricsSetCustomPromAddress(request, options).then((request) => request(axios, basePath));
         },
         /**
-         * 
-         * @summary Get Profiling Dynamic Config
+         * Check whether the status API of each target is reachable before profiling
+         * @summary Check whether profiling targets are reachable
+         * @param {ProfilingPingTargetsRequest} req ping request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        pingProfilingTargets(req: ProfilingPingTargetsRequest, options?: any): AxiosPromise<Array<ProfilingTargetReachability>> {
+            return localVarFp.pingProfilingTargets(req, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * 
+         * @summary Get Profiling Dynamic Config
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        profilingConfigGet(options?: any): AxiosPromise<ConfigProfilingConfig> {
+            return localVarFp.profilingConfigGet(options).then((request) => request(axios, basePath));
+        },
+        /**
+         * 
+         * @summary Set Profiling Dynamic Config
+         * @param {ConfigProfilingConfig} request Request body
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        profilingConfigPut(request: ConfigProfilingConfig, options?: any): AxiosPromise<ConfigProfilingConfig> {
+            return localVarFp.profilingConfigPut(request, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * List the latest profiling groups, at most 1000 groups are returned. Use the paged list to get more.
+         * @summary List profiling groups
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        profilingGroupListGet(options?: any): AxiosPromise<Array<ProfilingTaskGroupModel>> {
+            return localVarFp.profilingGroupListGet(options).then((request) => request(axios, basePath));
+        },
+        /**
+         * Get the profiling types supported by each kind of component
+         * @summary Get profiling capabilities
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        profilingTargetsCapabilitiesGet(options?: any): AxiosPromise<ProfilingKindCapabilitiesResponse> {
+            return localVarFp.profilingTargetsCapabilitiesGet(options).then((request) => request(axios, basePath));
+        },
+        /**
+         * List all components in the cluster which can be profiled
+         * @summary List profiling targets
+         * @param {Array<string>} [kinds] Only list components of these kinds if not empty, so that the topology of other kinds is not fetched.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        profilingTargetsGet(kinds?: Array<string>, options?: any): AxiosPromise<Array<ModelRequestTargetNode>> {
+            return localVarFp.profilingTargetsGet(kinds, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * Resolve all current components of the kinds to profiling targets, which can be used to start profiling
+         * @summary Resolve profiling targets
+         * @param {Array<string>} [kinds] 
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        profilingTargetsResolveGet(kinds?: Array<string>, options?: any): AxiosPromise<Array<ModelRequestTargetNode>> {
+            return localVarFp.profilingTargetsResolveGet(kinds, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * 
+         * @summary Run statements
+         * @param {QueryeditorRunRequest} request Request body
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        queryEditorRun(request: QueryeditorRunRequest, options?: any): AxiosPromise<QueryeditorRunResponse> {
+            return localVarFp.queryEditorRun(request, options).then((request) => request(axios, basePath));
+        },
+        /**
+         * Profile the target of a stopped task again and replace its result in place. The task ID does not change. A result registered as a baseline cannot be refreshed.
+         * @summary Profile a single task again
+         * @param {string} taskId task ID
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        profilingConfigGet(options?: any): AxiosPromise<ConfigProfilingConfig> {
-            return localVarFp.profilingConfigGet(options).then((request) => request(axios, basePath));
+        refreshProfilingSingle(taskId: string, options?: any): AxiosPromise<ProfilingTaskModel> {
+            return localVarFp.refreshProfilingSingle(taskId, options).then((request) => request(axios, basePath));
         },
         /**
-         * 
-         * @summary Set Profiling Dynamic Config
-         * @param {ConfigProfilingConfig} request Request body
+         * Register a finished profiling result as a named baseline. An existing baseline with the same name is replaced.
+         * @summary Register a baseline
+         * @param {ProfilingRegisterBaselineRequest} req baseline request
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        profilingConfigPut(request: ConfigProfilingConfig, options?: any): AxiosPromise<ConfigProfilingConfig> {
-            return localVarFp.profilingConfigPut(request, options).then((request) => request(axios, basePath));
+        registerProfilingBaseline(req: ProfilingRegisterBaselineRequest, options?: any): AxiosPromise<ProfilingBaselineModel> {
+            return localVarFp.registerProfilingBaseline(req, options).then((request) => request(axios, basePath));
         },
         /**
-         * 
-         * @summary Run statements
-         * @param {QueryeditorRunRequest} request Request body
+         * Profile the targets of failed tasks in a stopped group again. Finished tasks are kept.
+         * @summary Profile failed tasks of a group again
+         * @param {string} groupId group ID
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        queryEditorRun(request: QueryeditorRunRequest, options?: any): AxiosPromise<QueryeditorRunResponse> {
-            return localVarFp.queryEditorRun(request, options).then((request) => request(axios, basePath));
+        retryProfilingGroup(groupId: string, options?: any): AxiosPromise<Array<ProfilingTaskModel>> {
+            return localVarFp.retryProfilingGroup(groupId, options).then((request) => request(axios, basePath));
         },
         /**
          * Get available field names by slowquery table columns
@@ -5369,6 +7035,16 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
         startProfiling(req: ProfilingStartRequest, options?: any): AxiosPromise<ProfilingTaskGroupModel> {
             return localVarFp.startProfiling(req, options).then((request) => request(axios, basePath));
         },
+        /**
+         * Run the same profiling request several times with a gap. Each iteration is a task group.
+         * @summary Start a profiling campaign
+         * @param {ProfilingStartCampaignRequest} req profiling campaign request
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        startProfilingCampaign(req: ProfilingStartCampaignRequest, options?: any): AxiosPromise<ProfilingCampaignModel> {
+            return localVarFp.startProfilingCampaign(req, options).then((request) => request(axios, basePath));
+        },
         /**
          * Get available field names by statements table columns
          * @summary Get available field names
@@ -5613,18 +7289,44 @@ export const DefaultApiFactory = function (configuration?: Configuration, basePa
             return localVarFp.userShareSession(request, options).then((request) => request(axios, basePath));
         },
         /**
-         * View the finished profiling result of a task
+         * View the finished profiling result of a task, or the bytes received so far by a running task with the raw output type
          * @summary View the result of a task
          * @param {string} token download token
+         * @param {string} [outputType] output type, e.g. graph, flamegraph, top, raw, protobuf or text
+         * @param {number} [topN] number of functions in the top report, 30 by default
+         * @param {number} [offset] offset of the raw output in bytes
+         * @param {number} [length] maximum length of the raw output in bytes, all bytes from the offset by default
+         * @param {boolean} [decompress] output a gzipped protobuf profile as plain protobuf
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        viewProfilingSingle(token: string, options?: any): AxiosPromise<void> {
-            return localVarFp.viewProfilingSingle(token, options).then((request) => request(axios, basePath));
+        viewProfilingSingle(token: string, outputType?: string, topN?: number, offset?: number, length?: number, decompress?: boolean, options?: any): AxiosPromise<void> {
+            return localVarFp.viewProfilingSingle(token, outputType, topN, offset, length, decompress, options).then((request) => request(axios, basePath));
         },
     };
 };
 
+/**
+ * Request parameters for addProfilingGroupComment operation in DefaultApi.
+ * @export
+ * @interface DefaultApiAddProfilingGroupCommentRequest
+ */
+export interface DefaultApiAddProfilingGroupCommentRequest {
+    /**
+     * group ID
+     * @type {string}
+     * @memberof DefaultApiAddProfilingGroupComment
+     */
+    readonly groupId: string
+
+    /**
+     * comment request
+     * @type {ProfilingAddGroupCommentRequest}
+     * @memberof DefaultApiAddProfilingGroupComment
+     */
+    readonly req: ProfilingAddGroupCommentRequest
+}
+
 /**
  * Request parameters for cancelProfilingGroup operation in DefaultApi.
  * @export
@@ -5639,6 +7341,55 @@ export interface DefaultApiCancelProfilingGroupRequest {
     readonly groupId: string
 }
 
+/**
+ * Request parameters for cloneProfilingGroup operation in DefaultApi.
+ * @export
+ * @interface DefaultApiCloneProfilingGroupRequest
+ */
+export interface DefaultApiCloneProfilingGroupRequest {
+    /**
+     * group ID
+     * @type {string}
+     * @memberof DefaultApiCloneProfilingGroup
+     */
+    readonly groupId: string
+
+    /**
+     * clone request
+     * @type {ProfilingCloneGroupRequest}
+     * @memberof DefaultApiCloneProfilingGroup
+     */
+    readonly req: ProfilingCloneGroupRequest
+}
+
+/**
+ * Request parameters for compareProfilingToBaseline operation in DefaultApi.
+ * @export
+ * @interface DefaultApiCompareProfilingToBaselineRequest
+ */
+export interface DefaultApiCompareProfilingToBaselineRequest {
+    /**
+     * task ID
+     * @type {string}
+     * @memberof DefaultApiCompareProfilingToBaseline
+     */
+    readonly taskId: string
+
+    /**
+     * baseline name
+     * @type {string}
+     * @memberof DefaultApiCompareProfilingToBaseline
+     */
+    readonly baseline: string
+
+    /**
+     * regression threshold in share of total samples, e.g. 0.05
+     * @type {number}
+     * @memberof DefaultApiCompareProfilingToBaseline
+     */
+    readonly threshold?: number
+}
+
 /**
  * Request parameters for configurationEdit operation in DefaultApi.
  * @export
@@ -5765,6 +7516,20 @@ export interface DefaultApiContinuousProfilingSingleProfileViewGetRequest {
     readonly ts?: number
 }
 
+/**
+ * Request parameters for createProfilingSchedule operation in DefaultApi.
+ * @export
+ * @interface DefaultApiCreateProfilingScheduleRequest
+ */
+export interface DefaultApiCreateProfilingScheduleRequest {
+    /**
+     * schedule request
+     * @type {ProfilingCreateScheduleRequest}
+     * @memberof DefaultApiCreateProfilingSchedule
+     */
+    readonly req: ProfilingCreateScheduleRequest
+}
+
 /**
  * Request parameters for debugAPIRequestEndpoint operation in DefaultApi.
  * @export
@@ -5807,6 +7572,20 @@ export interface DefaultApiDeleteProfilingGroupRequest {
     readonly groupId: string
 }
 
+/**
+ * Request parameters for deleteProfilingSchedule operation in DefaultApi.
+ * @export
+ * @interface DefaultApiDeleteProfilingScheduleRequest
+ */
+export interface DefaultApiDeleteProfilingScheduleRequest {
+    /**
+     * schedule ID
+     * @type {string}
+     * @memberof DefaultApiDeleteProfilingSchedule
+     */
+    readonly scheduleId: string
+}
+
 /**
  * Request parameters for diagnoseDiagnosisPost operation in DefaultApi.
  * @export
@@ -5905,6 +7684,27 @@ export interface DefaultApiDiagnoseReportsPostRequest {
     readonly request: DiagnoseGenerateReportRequest
 }
 
+/**
+ * Request parameters for downloadProfilingCampaign operation in DefaultApi.
+ * @export
+ * @interface DefaultApiDownloadProfilingCampaignRequest
+ */
+export interface DefaultApiDownloadProfilingCampaignRequest {
+    /**
+     * download token
+     * @type {string}
+     * @memberof DefaultApiDownloadProfilingCampaign
+     */
+    readonly token: string
+
+    /**
+     * profiling type, cpu by default
+     * @type {string}
+     * @memberof DefaultApiDownloadProfilingCampaign
+     */
+    readonly profilingType?: string
+}
+
 /**
  * Request parameters for downloadProfilingGroup operation in DefaultApi.
  * @export
@@ -5917,6 +7717,62 @@ export interface DefaultApiDownloadProfilingGroupRequest {
      * @memberof DefaultApiDownloadProfilingGroup
      */
     readonly token: string
+
+    /**
+     * delete the task group after the results are fully exported, which is not allowed with filters. The token must be issued for group_download_auto_delete
+     * @type {boolean}
+     * @memberof DefaultApiDownloadProfilingGroup
+     */
+    readonly autoDelete?: boolean
+
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof DefaultApiDownloadProfilingGroup
+     */
+    readonly kinds?: Array<string>
+
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof DefaultApiDownloadProfilingGroup
+     */
+    readonly profilingTypes?: Array<string>
+
+    /**
+     * Addresses of targets in the form of \&quot;ip:port\&quot;
+     * @type {Array<string>}
+     * @memberof DefaultApiDownloadProfilingGroup
+     */
+    readonly targets?: Array<string>
+
+    /**
+     * also download the finished CPU profiles merged into a single profile
+     * @type {boolean}
+     * @memberof DefaultApiDownloadProfilingGroup
+     */
+    readonly mergeCpu?: boolean
+}
+
+/**
+ * Request parameters for downloadProfilingGroupMerged operation in DefaultApi.
+ * @export
+ * @interface DefaultApiDownloadProfilingGroupMergedRequest
+ */
+export interface DefaultApiDownloadProfilingGroupMergedRequest {
+    /**
+     * download token of the task group
+     * @type {string}
+     * @memberof DefaultApiDownloadProfilingGroupMerged
+     */
+    readonly token: string
+
+    /**
+     * profiling type, cpu by default
+     * @type {string}
+     * @memberof DefaultApiDownloadProfilingGroupMerged
+     */
+    readonly profilingType?: string
 }
 
 /**
@@ -5933,6 +7789,20 @@ export interface DefaultApiDownloadProfilingSingleRequest {
     readonly token: string
 }
 
+/**
+ * Request parameters for estimateProfilingGroup operation in DefaultApi.
+ * @export
+ * @interface DefaultApiEstimateProfilingGroupRequest
+ */
+export interface DefaultApiEstimateProfilingGroupRequest {
+    /**
+     * profiling request
+     * @type {ProfilingStartRequest}
+     * @memberof DefaultApiEstimateProfilingGroup
+     */
+    readonly req: ProfilingStartRequest
+}
+
 /**
  * Request parameters for getActionToken operation in DefaultApi.
  * @export
@@ -5968,6 +7838,34 @@ export interface DefaultApiGetAlertManagerCountsRequest {
     readonly address: string
 }
 
+/**
+ * Request parameters for getProfilingCampaignDetail operation in DefaultApi.
+ * @export
+ * @interface DefaultApiGetProfilingCampaignDetailRequest
+ */
+export interface DefaultApiGetProfilingCampaignDetailRequest {
+    /**
+     * campaign ID
+     * @type {string}
+     * @memberof DefaultApiGetProfilingCampaignDetail
+     */
+    readonly campaignId: string
+}
+
+/**
+ * Request parameters for getProfilingGroupComments operation in DefaultApi.
+ * @export
+ * @interface DefaultApiGetProfilingGroupCommentsRequest
+ */
+export interface DefaultApiGetProfilingGroupCommentsRequest {
+    /**
+     * group ID
+     * @type {string}
+     * @memberof DefaultApiGetProfilingGroupComments
+     */
+    readonly groupId: string
+}
+
 /**
  * Request parameters for getProfilingGroupDetail operation in DefaultApi.
  * @export
@@ -5980,6 +7878,139 @@ export interface DefaultApiGetProfilingGroupDetailRequest {
      * @memberof DefaultApiGetProfilingGroupDetail
      */
     readonly groupId: string
+
+    /**
+     * whether to include the tasks, true by default
+     * @type {boolean}
+     * @memberof DefaultApiGetProfilingGroupDetail
+     */
+    readonly includeTasks?: boolean
+
+    /**
+     * only include tasks in these states, all tasks by default
+     * @type {Array<number>}
+     * @memberof DefaultApiGetProfilingGroupDetail
+     */
+    readonly states?: Array<number>
+}
+
+/**
+ * Request parameters for getProfilingGroupEvents operation in DefaultApi.
+ * @export
+ * @interface DefaultApiGetProfilingGroupEventsRequest
+ */
+export interface DefaultApiGetProfilingGroupEventsRequest {
+    /**
+     * group ID
+     * @type {string}
+     * @memberof DefaultApiGetProfilingGroupEvents
+     */
+    readonly groupId: string
+}
+
+/**
+ * Request parameters for getProfilingGroupTop operation in DefaultApi.
+ * @export
+ * @interface DefaultApiGetProfilingGroupTopRequest
+ */
+export interface DefaultApiGetProfilingGroupTopRequest {
+    /**
+     * group ID
+     * @type {string}
+     * @memberof DefaultApiGetProfilingGroupTop
+     */
+    readonly groupId: string
+
+    /**
+     * profiling type, cpu by default
+     * @type {string}
+     * @memberof DefaultApiGetProfilingGroupTop
+     */
+    readonly profilingType?: string
+
+    /**
+     * number of functions to return
+     * @type {number}
+     * @memberof DefaultApiGetProfilingGroupTop
+     */
+    readonly limit?: number
+}
+
+/**
+ * Request parameters for getProfilingGroupsPaged operation in DefaultApi.
+ * @export
+ * @interface DefaultApiGetProfilingGroupsPagedRequest
+ */
+export interface DefaultApiGetProfilingGroupsPagedRequest {
+    /**
+     * Only list task groups with all of these labels if not empty, each in the form of \&quot;key:value\&quot;.
+     * @type {Array<string>}
+     * @memberof DefaultApiGetProfilingGroupsPaged
+     */
+    readonly labels?: Array<string>
+
+    /**
+     * 
+     * @type {number}
+     * @memberof DefaultApiGetProfilingGroupsPaged
+     */
+    readonly limit?: number
+
+    /**
+     * 
+     * @type {number}
+     * @memberof DefaultApiGetProfilingGroupsPaged
+     */
+    readonly offset?: number
+
+    /**
+     * Only list task groups with tasks of these profiling types if not empty.
+     * @type {Array<string>}
+     * @memberof DefaultApiGetProfilingGroupsPaged
+     */
+    readonly profilingTypes?: Array<string>
+
+    /**
+     * Only list task groups in these states if not empty.
+     * @type {Array<number>}
+     * @memberof DefaultApiGetProfilingGroupsPaged
+     */
+    readonly states?: Array<number>
+
+    /**
+     * Only list task groups started by this user if not empty.
+     * @type {string}
+     * @memberof DefaultApiGetProfilingGroupsPaged
+     */
+    readonly triggeredBy?: string
+}
+
+/**
+ * Request parameters for getProfilingSinglePreview operation in DefaultApi.
+ * @export
+ * @interface DefaultApiGetProfilingSinglePreviewRequest
+ */
+export interface DefaultApiGetProfilingSinglePreviewRequest {
+    /**
+     * task ID
+     * @type {string}
+     * @memberof DefaultApiGetProfilingSinglePreview
+     */
+    readonly taskId: string
+}
+
+/**
+ * Request parameters for getProfilingTasksData operation in DefaultApi.
+ * @export
+ * @interface DefaultApiGetProfilingTasksDataRequest
+ */
+export interface DefaultApiGetProfilingTasksDataRequest {
+    /**
+     * task IDs
+     * @type {ProfilingTasksDataRequest}
+     * @memberof DefaultApiGetProfilingTasksData
+     */
+    readonly req: ProfilingTasksDataRequest
 }
 
 /**
@@ -6204,13 +8235,27 @@ export interface DefaultApiMetricsQueryGetRequest {
  * @export
  * @interface DefaultApiMetricsSetCustomPromAddressRequest
  */
-export interface DefaultApiMetricsSetCustomPromAddressRequest {
+export interface DefaultApiMetricsSetCustomPromAddressRequest {
+    /**
+     * Request body
+     * @type {MetricsPutCustomPromAddressRequest}
+     * @memberof DefaultApiMetricsSetCustomPromAddress
+     */
+    readonly request: MetricsPutCustomPromAddressRequest
+}
+
+/**
+ * Request parameters for pingProfilingTargets operation in DefaultApi.
+ * @export
+ * @interface DefaultApiPingProfilingTargetsRequest
+ */
+export interface DefaultApiPingProfilingTargetsRequest {
     /**
-     * Request body
-     * @type {MetricsPutCustomPromAddressRequest}
-     * @memberof DefaultApiMetricsSetCustomPromAddress
+     * ping request
+     * @type {ProfilingPingTargetsRequest}
+     * @memberof DefaultApiPingProfilingTargets
      */
-    readonly request: MetricsPutCustomPromAddressRequest
+    readonly req: ProfilingPingTargetsRequest
 }
 
 /**
@@ -6227,6 +8272,34 @@ export interface DefaultApiProfilingConfigPutRequest {
     readonly request: ConfigProfilingConfig
 }
 
+/**
+ * Request parameters for profilingTargetsGet operation in DefaultApi.
+ * @export
+ * @interface DefaultApiProfilingTargetsGetRequest
+ */
+export interface DefaultApiProfilingTargetsGetRequest {
+    /**
+     * Only list components of these kinds if not empty, so that the topology of other kinds is not fetched.
+     * @type {Array<string>}
+     * @memberof DefaultApiProfilingTargetsGet
+     */
+    readonly kinds?: Array<string>
+}
+
+/**
+ * Request parameters for profilingTargetsResolveGet operation in DefaultApi.
+ * @export
+ * @interface DefaultApiProfilingTargetsResolveGetRequest
+ */
+export interface DefaultApiProfilingTargetsResolveGetRequest {
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof DefaultApiProfilingTargetsResolveGet
+     */
+    readonly kinds?: Array<string>
+}
+
 /**
  * Request parameters for queryEditorRun operation in DefaultApi.
  * @export
@@ -6241,6 +8314,48 @@ export interface DefaultApiQueryEditorRunRequest {
     readonly request: QueryeditorRunRequest
 }
 
+/**
+ * Request parameters for refreshProfilingSingle operation in DefaultApi.
+ * @export
+ * @interface DefaultApiRefreshProfilingSingleRequest
+ */
+export interface DefaultApiRefreshProfilingSingleRequest {
+    /**
+     * task ID
+     * @type {string}
+     * @memberof DefaultApiRefreshProfilingSingle
+     */
+    readonly taskId: string
+}
+
+/**
+ * Request parameters for registerProfilingBaseline operation in DefaultApi.
+ * @export
+ * @interface DefaultApiRegisterProfilingBaselineRequest
+ */
+export interface DefaultApiRegisterProfilingBaselineRequest {
+    /**
+     * baseline request
+     * @type {ProfilingRegisterBaselineRequest}
+     * @memberof DefaultApiRegisterProfilingBaseline
+     */
+    readonly req: ProfilingRegisterBaselineRequest
+}
+
+/**
+ * Request parameters for retryProfilingGroup operation in DefaultApi.
+ * @export
+ * @interface DefaultApiRetryProfilingGroupRequest
+ */
+export interface DefaultApiRetryProfilingGroupRequest {
+    /**
+     * group ID
+     * @type {string}
+     * @memberof DefaultApiRetryProfilingGroup
+     */
+    readonly groupId: string
+}
+
 /**
  * Request parameters for slowQueryDetailGet operation in DefaultApi.
  * @export
@@ -6388,6 +8503,20 @@ export interface DefaultApiStartProfilingRequest {
     readonly req: ProfilingStartRequest
 }
 
+/**
+ * Request parameters for startProfilingCampaign operation in DefaultApi.
+ * @export
+ * @interface DefaultApiStartProfilingCampaignRequest
+ */
+export interface DefaultApiStartProfilingCampaignRequest {
+    /**
+     * profiling campaign request
+     * @type {ProfilingStartCampaignRequest}
+     * @memberof DefaultApiStartProfilingCampaign
+     */
+    readonly req: ProfilingStartCampaignRequest
+}
+
 /**
  * Request parameters for statementsConfigPost operation in DefaultApi.
  * @export
@@ -6764,6 +8893,41 @@ export interface DefaultApiViewProfilingSingleRequest {
      * @memberof DefaultApiViewProfilingSingle
      */
     readonly token: string
+
+    /**
+     * output type, e.g. graph, flamegraph, top, raw, protobuf or text
+     * @type {string}
+     * @memberof DefaultApiViewProfilingSingle
+     */
+    readonly outputType?: string
+
+    /**
+     * number of functions in the top report, 30 by default
+     * @type {number}
+     * @memberof DefaultApiViewProfilingSingle
+     */
+    readonly topN?: number
+
+    /**
+     * offset of the raw output in bytes
+     * @type {number}
+     * @memberof DefaultApiViewProfilingSingle
+     */
+    readonly offset?: number
+
+    /**
+     * maximum length of the raw output in bytes, all bytes from the offset by default
+     * @type {number}
+     * @memberof DefaultApiViewProfilingSingle
+     */
+    readonly length?: number
+
+    /**
+     * output a gzipped protobuf profile as plain protobuf
+     * @type {boolean}
+     * @memberof DefaultApiViewProfilingSingle
+     */
+    readonly decompress?: boolean
 }
 
 /**
@@ -6774,7 +8938,19 @@ export interface DefaultApiViewProfilingSingleRequest {
  */
 export class DefaultApi extends BaseAPI {
     /**
-     * Cancel all profling tasks with a given group ID
+     * Add a comment to a profiling task group. Comments cannot be modified once added.
+     * @summary Add a comment to a task group
+     * @param {DefaultApiAddProfilingGroupCommentRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public addProfilingGroupComment(requestParameters: DefaultApiAddProfilingGroupCommentRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).addProfilingGroupComment(requestParameters.groupId, requestParameters.req, options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * Cancel all running profling tasks with a given group ID. Results of finished tasks are kept.
      * @summary Cancel all tasks with a given group ID
      * @param {DefaultApiCancelProfilingGroupRequest} requestParameters Request parameters.
      * @param {*} [options] Override http request option.
@@ -6785,6 +8961,18 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).cancelProfilingGroup(requestParameters.groupId, options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * Start a new group profiling the same targets with the same profiling types as a stopped group. The original group is kept.
+     * @summary Profile the targets of a group again
+     * @param {DefaultApiCloneProfilingGroupRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public cloneProfilingGroup(requestParameters: DefaultApiCloneProfilingGroupRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).cloneProfilingGroup(requestParameters.groupId, requestParameters.req, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * 
      * @summary Get information of all hosts
@@ -6807,6 +8995,18 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).clusterInfoGetStatistics(options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * Compare the profiling result of a task with a baseline and report functions regressed more than the threshold
+     * @summary Compare a task with a baseline
+     * @param {DefaultApiCompareProfilingToBaselineRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public compareProfilingToBaseline(requestParameters: DefaultApiCompareProfilingToBaselineRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).compareProfilingToBaseline(requestParameters.taskId, requestParameters.baseline, requestParameters.threshold, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * 
      * @summary Edit a configuration
@@ -6935,6 +9135,18 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).continuousProfilingSingleProfileViewGet(requestParameters.address, requestParameters.component, requestParameters.profileType, requestParameters.ts, options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * Profile with the same request every interval. Each run is a task group linked by the schedule ID.
+     * @summary Create a profiling schedule
+     * @param {DefaultApiCreateProfilingScheduleRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public createProfilingSchedule(requestParameters: DefaultApiCreateProfilingScheduleRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).createProfilingSchedule(requestParameters.req, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * 
      * @summary List all deadlock records
@@ -6982,7 +9194,7 @@ export class DefaultApi extends BaseAPI {
     }
 
     /**
-     * Delete all finished profiling tasks with a given group ID
+     * Delete all profiling tasks and results with a given group ID. A running group must be cancelled first. Baselines registered from its results are deleted with it.
      * @summary Delete all tasks with a given group ID
      * @param {DefaultApiDeleteProfilingGroupRequest} requestParameters Request parameters.
      * @param {*} [options] Override http request option.
@@ -6993,6 +9205,18 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).deleteProfilingGroup(requestParameters.groupId, options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * Stop running a profiling schedule. Task groups already started by the schedule are kept.
+     * @summary Delete a profiling schedule
+     * @param {DefaultApiDeleteProfilingScheduleRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public deleteProfilingSchedule(requestParameters: DefaultApiDeleteProfilingScheduleRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).deleteProfilingSchedule(requestParameters.scheduleId, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * Generate sql diagnosis report
      * @summary SQL diagnosis report
@@ -7088,6 +9312,18 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).diagnoseReportsPost(requestParameters.request, options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * Download the profiling results of each target merged across all iterations of a campaign
+     * @summary Download the merged results of a campaign
+     * @param {DefaultApiDownloadProfilingCampaignRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public downloadProfilingCampaign(requestParameters: DefaultApiDownloadProfilingCampaignRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).downloadProfilingCampaign(requestParameters.token, requestParameters.profilingType, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * Download all finished profiling results of a task group
      * @summary Download all results of a task group
@@ -7097,7 +9333,19 @@ export class DefaultApi extends BaseAPI {
      * @memberof DefaultApi
      */
     public downloadProfilingGroup(requestParameters: DefaultApiDownloadProfilingGroupRequest, options?: AxiosRequestConfig) {
-        return DefaultApiFp(this.configuration).downloadProfilingGroup(requestParameters.token, options).then((request) => request(this.axios, this.basePath));
+        return DefaultApiFp(this.configuration).downloadProfilingGroup(requestParameters.token, requestParameters.autoDelete, requestParameters.kinds, requestParameters.profilingTypes, requestParameters.targets, requestParameters.mergeCpu, options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * Download the finished profiling results of a profiling type across all targets of a task group merged into a single profile. Samples are labeled with their source instance and component.
+     * @summary Download the merged result of a task group
+     * @param {DefaultApiDownloadProfilingGroupMergedRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public downloadProfilingGroupMerged(requestParameters: DefaultApiDownloadProfilingGroupMergedRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).downloadProfilingGroupMerged(requestParameters.token, requestParameters.profilingType, options).then((request) => request(this.axios, this.basePath));
     }
 
     /**
@@ -7112,6 +9360,18 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).downloadProfilingSingle(requestParameters.token, options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * Estimate the total size of the profiles of a profiling request from recently finished tasks, without starting it
+     * @summary Estimate the size of a profiling group
+     * @param {DefaultApiEstimateProfilingGroupRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public estimateProfilingGroup(requestParameters: DefaultApiEstimateProfilingGroupRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).estimateProfilingGroup(requestParameters.req, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * Get token with a given group ID or task ID and action type
      * @summary Get action token for download or view
@@ -7169,6 +9429,41 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).getPDTopology(options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * List all registered profiling baselines
+     * @summary List all baselines
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public getProfilingBaselines(options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).getProfilingBaselines(options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * 
+     * @summary Get a profiling campaign with all of its task groups
+     * @param {DefaultApiGetProfilingCampaignDetailRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public getProfilingCampaignDetail(requestParameters: DefaultApiGetProfilingCampaignDetailRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).getProfilingCampaignDetail(requestParameters.campaignId, options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * List comments of a profiling task group in the order they are added
+     * @summary List comments of a task group
+     * @param {DefaultApiGetProfilingGroupCommentsRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public getProfilingGroupComments(requestParameters: DefaultApiGetProfilingGroupCommentsRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).getProfilingGroupComments(requestParameters.groupId, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * List all profiling tasks with a given group ID
      * @summary List all tasks with a given group ID
@@ -7178,18 +9473,78 @@ export class DefaultApi extends BaseAPI {
      * @memberof DefaultApi
      */
     public getProfilingGroupDetail(requestParameters: DefaultApiGetProfilingGroupDetailRequest, options?: AxiosRequestConfig) {
-        return DefaultApiFp(this.configuration).getProfilingGroupDetail(requestParameters.groupId, options).then((request) => request(this.axios, this.basePath));
+        return DefaultApiFp(this.configuration).getProfilingGroupDetail(requestParameters.groupId, requestParameters.includeTasks, requestParameters.states, options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * Send the progress of a task group as server-sent events whenever it is changed, until the task group is stopped
+     * @summary Subscribe to the progress of a task group
+     * @param {DefaultApiGetProfilingGroupEventsRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public getProfilingGroupEvents(requestParameters: DefaultApiGetProfilingGroupEventsRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).getProfilingGroupEvents(requestParameters.groupId, options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * Aggregate all finished profiles of a profiling type in a task group and list the top functions by flat value
+     * @summary Get top functions of a task group
+     * @param {DefaultApiGetProfilingGroupTopRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public getProfilingGroupTop(requestParameters: DefaultApiGetProfilingGroupTopRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).getProfilingGroupTop(requestParameters.groupId, requestParameters.profilingType, requestParameters.limit, options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * List profiling groups ordered by ID descending, with the total number of groups
+     * @summary List profiling groups by page
+     * @param {DefaultApiGetProfilingGroupsPagedRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public getProfilingGroupsPaged(requestParameters: DefaultApiGetProfilingGroupsPagedRequest = {}, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).getProfilingGroupsPaged(requestParameters.labels, requestParameters.limit, requestParameters.offset, requestParameters.profilingTypes, requestParameters.states, requestParameters.triggeredBy, options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * 
+     * @summary List profiling schedules
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public getProfilingScheduleList(options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).getProfilingScheduleList(options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * Get a lightweight summary of a finished task, e.g. the top functions of a CPU profile
+     * @summary Preview a single profiling result
+     * @param {DefaultApiGetProfilingSinglePreviewRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public getProfilingSinglePreview(requestParameters: DefaultApiGetProfilingSinglePreviewRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).getProfilingSinglePreview(requestParameters.taskId, options).then((request) => request(this.axios, this.basePath));
     }
 
     /**
-     * List all profiling groups
-     * @summary List all profiling groups
+     * Get multiple tasks with their profiling results in the requested order. Tasks which are not finished are returned without data.
+     * @summary Get the results of tasks
+     * @param {DefaultApiGetProfilingTasksDataRequest} requestParameters Request parameters.
      * @param {*} [options] Override http request option.
      * @throws {RequiredError}
      * @memberof DefaultApi
      */
-    public getProfilingGroups(options?: AxiosRequestConfig) {
-        return DefaultApiFp(this.configuration).getProfilingGroups(options).then((request) => request(this.axios, this.basePath));
+    public getProfilingTasksData(requestParameters: DefaultApiGetProfilingTasksDataRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).getProfilingTasksData(requestParameters.req, options).then((request) => request(this.axios, this.basePath));
     }
 
     /**
@@ -7447,6 +9802,18 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).metricsSetCustomPromAddress(requestParameters.request, options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * Check whether the status API of each target is reachable before profiling
+     * @summary Check whether profiling targets are reachable
+     * @param {DefaultApiPingProfilingTargetsRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public pingProfilingTargets(requestParameters: DefaultApiPingProfilingTargetsRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).pingProfilingTargets(requestParameters.req, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * 
      * @summary Get Profiling Dynamic Config
@@ -7470,6 +9837,52 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).profilingConfigPut(requestParameters.request, options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * List the latest profiling groups, at most 1000 groups are returned. Use the paged list to get more.
+     * @summary List profiling groups
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public profilingGroupListGet(options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).profilingGroupListGet(options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * Get the profiling types supported by each kind of component
+     * @summary Get profiling capabilities
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public profilingTargetsCapabilitiesGet(options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).profilingTargetsCapabilitiesGet(options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * List all components in the cluster which can be profiled
+     * @summary List profiling targets
+     * @param {DefaultApiProfilingTargetsGetRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public profilingTargetsGet(requestParameters: DefaultApiProfilingTargetsGetRequest = {}, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).profilingTargetsGet(requestParameters.kinds, options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * Resolve all current components of the kinds to profiling targets, which can be used to start profiling
+     * @summary Resolve profiling targets
+     * @param {DefaultApiProfilingTargetsResolveGetRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public profilingTargetsResolveGet(requestParameters: DefaultApiProfilingTargetsResolveGetRequest = {}, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).profilingTargetsResolveGet(requestParameters.kinds, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * 
      * @summary Run statements
@@ -7482,6 +9895,42 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).queryEditorRun(requestParameters.request, options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * Profile the target of a stopped task again and replace its result in place. The task ID does not change. A result registered as a baseline cannot be refreshed.
+     * @summary Profile a single task again
+     * @param {DefaultApiRefreshProfilingSingleRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public refreshProfilingSingle(requestParameters: DefaultApiRefreshProfilingSingleRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).refreshProfilingSingle(requestParameters.taskId, options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * Register a finished profiling result as a named baseline. An existing baseline with the same name is replaced.
+     * @summary Register a baseline
+     * @param {DefaultApiRegisterProfilingBaselineRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public registerProfilingBaseline(requestParameters: DefaultApiRegisterProfilingBaselineRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).registerProfilingBaseline(requestParameters.req, options).then((request) => request(this.axios, this.basePath));
+    }
+
+    /**
+     * Profile the targets of failed tasks in a stopped group again. Finished tasks are kept.
+     * @summary Profile failed tasks of a group again
+     * @param {DefaultApiRetryProfilingGroupRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public retryProfilingGroup(requestParameters: DefaultApiRetryProfilingGroupRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).retryProfilingGroup(requestParameters.groupId, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * Get available field names by slowquery table columns
      * @summary Get available field names
@@ -7553,6 +10002,18 @@ export class DefaultApi extends BaseAPI {
         return DefaultApiFp(this.configuration).startProfiling(requestParameters.req, options).then((request) => request(this.axios, this.basePath));
     }
 
+    /**
+     * Run the same profiling request several times with a gap. Each iteration is a task group.
+     * @summary Start a profiling campaign
+     * @param {DefaultApiStartProfilingCampaignRequest} requestParameters Request parameters.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof DefaultApi
+     */
+    public startProfilingCampaign(requestParameters: DefaultApiStartProfilingCampaignRequest, options?: AxiosRequestConfig) {
+        return DefaultApiFp(this.configuration).startProfilingCampaign(requestParameters.req, options).then((request) => request(this.axios, this.basePath));
+    }
+
     /**
      * Get available field names by statements table columns
      * @summary Get available field names
@@ -7823,7 +10284,7 @@ export class DefaultApi extends BaseAPI {
     }
 
     /**
-     * View the finished profiling result of a task
+     * View the finished profiling result of a task, or the bytes received so far by a running task with the raw output type
      * @summary View the result of a task
      * @param {DefaultApiViewProfilingSingleRequest} requestParameters Request parameters.
      * @param {*} [options] Override http request option.
@@ -7831,6 +10292,6 @@ export class DefaultApi extends BaseAPI {
      * @memberof DefaultApi
      */
     public viewProfilingSingle(requestParameters: DefaultApiViewProfilingSingleRequest, options?: AxiosRequestConfig) {
-        return DefaultApiFp(this.configuration).viewProfilingSingle(requestParameters.token, options).then((request) => request(this.axios, this.basePath));
+        return DefaultApiFp(this.configuration).viewProfilingSingle(requestParameters.token, requestParameters.outputType, requestParameters.topN, requestParameters.offset, requestParameters.length, requestParameters.decompress, options).then((request) => request(this.axios, this.basePath));
     }
 }
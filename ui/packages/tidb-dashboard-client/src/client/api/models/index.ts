@@ -53,10 +53,35 @@ export * from './metrics-put-custom-prom-address-response';
 export * from './metrics-query-response';
 export * from './model-request-target-node';
 export * from './model-request-target-statistics';
+export * from './profiling-add-group-comment-request';
+export * from './profiling-baseline-compare-result';
+export * from './profiling-baseline-diff-entry';
+export * from './profiling-baseline-model';
+export * from './profiling-campaign-detail-response';
+export * from './profiling-campaign-model';
+export * from './profiling-clone-group-request';
+export * from './profiling-create-schedule-request';
+export * from './profiling-estimate-group-response';
 export * from './profiling-group-detail-response';
+export * from './profiling-group-progress-event';
+export * from './profiling-group-top-response';
+export * from './profiling-kind-capabilities-response';
+export * from './profiling-list-groups-response';
+export * from './profiling-ping-targets-request';
+export * from './profiling-profile-preview';
+export * from './profiling-register-baseline-request';
+export * from './profiling-schedule-model';
+export * from './profiling-schedule-request';
+export * from './profiling-start-campaign-request';
 export * from './profiling-start-request';
+export * from './profiling-target-reachability';
+export * from './profiling-task-group-comment-model';
 export * from './profiling-task-group-model';
 export * from './profiling-task-model';
+export * from './profiling-task-progress';
+export * from './profiling-task-with-data';
+export * from './profiling-tasks-data-request';
+export * from './profiling-top-function';
 export * from './queryeditor-run-request';
 export * from './queryeditor-run-response';
 export * from './rest-error-response';
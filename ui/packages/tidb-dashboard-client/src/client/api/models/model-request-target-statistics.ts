@@ -26,6 +26,24 @@ export interface ModelRequestTargetStatistics {
      * @memberof ModelRequestTargetStatistics
      */
     'num_pd_nodes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ModelRequestTargetStatistics
+     */
+    'num_pd_scheduling_nodes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ModelRequestTargetStatistics
+     */
+    'num_pd_tso_nodes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ModelRequestTargetStatistics
+     */
+    'num_ticdc_nodes'?: number;
     /**
      * 
      * @type {number}
@@ -44,5 +62,11 @@ export interface ModelRequestTargetStatistics {
      * @memberof ModelRequestTargetStatistics
      */
     'num_tikv_nodes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ModelRequestTargetStatistics
+     */
+    'num_tiproxy_nodes'?: number;
 }
 
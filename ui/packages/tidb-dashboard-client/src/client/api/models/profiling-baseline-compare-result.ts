@@ -0,0 +1,55 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ProfilingBaselineDiffEntry } from './profiling-baseline-diff-entry';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingBaselineCompareResult
+ */
+export interface ProfilingBaselineCompareResult {
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingBaselineCompareResult
+     */
+    'baseline'?: string;
+    /**
+     * 
+     * @type {Array<ProfilingBaselineDiffEntry>}
+     * @memberof ProfilingBaselineCompareResult
+     */
+    'diff'?: Array<ProfilingBaselineDiffEntry>;
+    /**
+     * 
+     * @type {Array<ProfilingBaselineDiffEntry>}
+     * @memberof ProfilingBaselineCompareResult
+     */
+    'regressions'?: Array<ProfilingBaselineDiffEntry>;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineCompareResult
+     */
+    'task_id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineCompareResult
+     */
+    'threshold'?: number;
+}
+
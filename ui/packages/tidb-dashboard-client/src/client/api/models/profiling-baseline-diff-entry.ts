@@ -0,0 +1,48 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingBaselineDiffEntry
+ */
+export interface ProfilingBaselineDiffEntry {
+    /**
+     * Share of the total samples in the baseline / current profile, in range [0, 1].
+     * @type {number}
+     * @memberof ProfilingBaselineDiffEntry
+     */
+    'baseline_share'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineDiffEntry
+     */
+    'current_share'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineDiffEntry
+     */
+    'delta'?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingBaselineDiffEntry
+     */
+    'function'?: string;
+}
+
@@ -0,0 +1,55 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ModelRequestTargetNode } from './model-request-target-node';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingBaselineModel
+ */
+export interface ProfilingBaselineModel {
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineModel
+     */
+    'created_at'?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingBaselineModel
+     */
+    'name'?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingBaselineModel
+     */
+    'profiling_type'?: string;
+    /**
+     * 
+     * @type {ModelRequestTargetNode}
+     * @memberof ProfilingBaselineModel
+     */
+    'target'?: ModelRequestTargetNode;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineModel
+     */
+    'task_id'?: number;
+}
+
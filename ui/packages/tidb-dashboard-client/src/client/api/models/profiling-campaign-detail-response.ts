@@ -0,0 +1,38 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ProfilingCampaignModel } from './profiling-campaign-model';
+import { ProfilingTaskGroupModel } from './profiling-task-group-model';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingCampaignDetailResponse
+ */
+export interface ProfilingCampaignDetailResponse {
+    /**
+     * 
+     * @type {ProfilingCampaignModel}
+     * @memberof ProfilingCampaignDetailResponse
+     */
+    'campaign'?: ProfilingCampaignModel;
+    /**
+     * 
+     * @type {Array<ProfilingTaskGroupModel>}
+     * @memberof ProfilingCampaignDetailResponse
+     */
+    'task_groups'?: Array<ProfilingTaskGroupModel>;
+}
+
@@ -0,0 +1,60 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingCampaignModel
+ */
+export interface ProfilingCampaignModel {
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingCampaignModel
+     */
+    'error'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingCampaignModel
+     */
+    'gap_secs'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingCampaignModel
+     */
+    'id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingCampaignModel
+     */
+    'iterations'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingCampaignModel
+     */
+    'started_at'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingCampaignModel
+     */
+    'state'?: number;
+}
+
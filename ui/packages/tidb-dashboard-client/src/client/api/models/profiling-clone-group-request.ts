@@ -0,0 +1,30 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingCloneGroupRequest
+ */
+export interface ProfilingCloneGroupRequest {
+    /**
+     * The duration of the cloned task group. The duration of the original task group is used when it is 0.
+     * @type {number}
+     * @memberof ProfilingCloneGroupRequest
+     */
+    'duration_secs'?: number;
+}
+
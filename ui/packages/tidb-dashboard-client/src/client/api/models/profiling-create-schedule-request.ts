@@ -0,0 +1,145 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ModelRequestTargetNode } from './model-request-target-node';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingCreateScheduleRequest
+ */
+export interface ProfilingCreateScheduleRequest {
+    /**
+     * Record the build ID of each target along with its profiling results, on a best-effort basis.
+     * @type {boolean}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'capture_build_id'?: boolean;
+    /**
+     * Reject the request if any target is no longer present in the cluster topology.
+     * @type {boolean}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'check_topology'?: boolean;
+    /**
+     * The pprof handler to fetch for ProfilingTypeCustom, e.g. /debug/pprof/custom, which must be under /debug/pprof/ of the status port of targets. It may contain a query.
+     * @type {string}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'custom_pprof_path'?: string;
+    /**
+     * Seconds passed to the custom pprof handler as the seconds query, which is not passed when it is 0.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'custom_pprof_seconds'?: number;
+    /**
+     * Duration of profiles in seconds, which must be greater than 0 and not exceed the configured maximum.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'duration_secs'?: number;
+    /**
+     * Durations of profiling types, which override DurationSecs for tasks of these types, e.g. a shorter trace along with a longer CPU profile. Durations are ignored by snapshot profiling types, e.g. heap.
+     * @type {{ [key: string]: number; }}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'duration_secs_by_type'?: { [key: string]: number; };
+    /**
+     * Durations which override DurationSecs with a task per duration for each target, e.g. both a 10 seconds and a 30 seconds CPU profile of a target to compare. They are ignored by snapshot profiling types, ProfilingTypeCustom and profiling types in DurationSecsByType, which still capture a single task.
+     * @type {Array<number>}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'duration_secs_list'?: Array<number>;
+    /**
+     * The interval between the starts of two runs, which must not be shorter than the profile duration.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'interval_secs'?: number;
+    /**
+     * Labels of the task group, by which task groups can be filtered when listing.
+     * @type {{ [key: string]: string; }}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'labels'?: { [key: string]: string; };
+    /**
+     * Only profile the leader among the PD targets.
+     * @type {boolean}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'leader_only'?: boolean;
+    /**
+     * The maximum number of tasks profiling at the same time, to avoid disturbing a large cluster. All tasks are run at once when it is 0.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'max_concurrency'?: number;
+    /**
+     * The mutex profile fraction set on TiDB targets while profiling mutex contentions, which are sampled during the profile duration. The fraction is reset to 0 afterwards. It is not changed when it is 0.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'mutex_profile_fraction'?: number;
+    /**
+     * A free-text note about why the task group is captured.
+     * @type {string}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'note'?: string;
+    /**
+     * Profiling types of targets of a component kind, which override RequstedProfilingTypes for these targets. Without RequstedProfilingTypes, every kind of targets must have profiling types here.
+     * @type {{ [key: string]: Array<string>; }}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'profiling_types_by_kind'?: { [key: string]: Array<string>; };
+    /**
+     * Seconds allowed for fetching each profile beyond the profile duration, after which the task is failed. The configured default is used when it is 0.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'request_timeout_secs'?: number;
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'requsted_profiling_types'?: Array<string>;
+    /**
+     * Run the task group again once if all of its tasks are failed, e.g. due to a transient cluster-wide failure.
+     * @type {boolean}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'retry_on_total_failure'?: boolean;
+    /**
+     * The maximum delay in milliseconds between launching two tasks, so that targets are not profiled at exactly the same time, which smooths the load of the cluster. The delay is randomized between half of it and itself. All tasks are launched at once when it is 0.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'stagger_ms'?: number;
+    /**
+     * 
+     * @type {Array<ModelRequestTargetNode>}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'targets'?: Array<ModelRequestTargetNode>;
+    /**
+     * TiKV stores to profile in addition to Targets, which are resolved to addresses by the cluster topology.
+     * @type {Array<number>}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'tikv_store_ids'?: Array<number>;
+}
+
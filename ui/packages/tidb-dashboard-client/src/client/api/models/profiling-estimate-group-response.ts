@@ -0,0 +1,42 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingEstimateGroupResponse
+ */
+export interface ProfilingEstimateGroupResponse {
+    /**
+     * The approximate total size in bytes of the profiles, which only covers the estimated tasks.
+     * @type {number}
+     * @memberof ProfilingEstimateGroupResponse
+     */
+    'approx_bytes'?: number;
+    /**
+     * The number of tasks which are not skipped.
+     * @type {number}
+     * @memberof ProfilingEstimateGroupResponse
+     */
+    'num_tasks'?: number;
+    /**
+     * The number of tasks which cannot be estimated, since no task of the same kind of component and profiling type is finished recently.
+     * @type {number}
+     * @memberof ProfilingEstimateGroupResponse
+     */
+    'num_unknown_tasks'?: number;
+}
+
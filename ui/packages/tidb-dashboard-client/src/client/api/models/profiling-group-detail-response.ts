@@ -13,6 +13,7 @@
  */
 
 
+import { ProfilingTaskGroupCommentModel } from './profiling-task-group-comment-model';
 import { ProfilingTaskGroupModel } from './profiling-task-group-model';
 import { ProfilingTaskModel } from './profiling-task-model';
 
@@ -22,6 +23,24 @@ import { ProfilingTaskModel } from './profiling-task-model';
  * @interface ProfilingGroupDetailResponse
  */
 export interface ProfilingGroupDetailResponse {
+    /**
+     * 
+     * @type {Array<ProfilingTaskGroupCommentModel>}
+     * @memberof ProfilingGroupDetailResponse
+     */
+    'comments'?: Array<ProfilingTaskGroupCommentModel>;
+    /**
+     * Number of failed tasks by their distinct error messages, so that the failures of a partially finished task group can be understood without checking every task.
+     * @type {{ [key: string]: number; }}
+     * @memberof ProfilingGroupDetailResponse
+     */
+    'error_summary'?: { [key: string]: number; };
+    /**
+     * Profiling types that produced at least one result, which may be a subset of the requested types since some components do not support all profiling types.
+     * @type {Array<string>}
+     * @memberof ProfilingGroupDetailResponse
+     */
+    'observed_profiling_types'?: Array<string>;
     /**
      * 
      * @type {number}
@@ -35,7 +54,7 @@ export interface ProfilingGroupDetailResponse {
      */
     'task_group_status'?: ProfilingTaskGroupModel;
     /**
-     * 
+     * Tasks of the task group, or nil if the tasks are not requested. Fields derived from the tasks are filled in either case.
      * @type {Array<ProfilingTaskModel>}
      * @memberof ProfilingGroupDetailResponse
      */
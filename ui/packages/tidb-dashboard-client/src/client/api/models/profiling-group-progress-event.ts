@@ -0,0 +1,43 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ProfilingTaskProgress } from './profiling-task-progress';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingGroupProgressEvent
+ */
+export interface ProfilingGroupProgressEvent {
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingGroupProgressEvent
+     */
+    'state'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingGroupProgressEvent
+     */
+    'task_group_id'?: number;
+    /**
+     * 
+     * @type {Array<ProfilingTaskProgress>}
+     * @memberof ProfilingGroupProgressEvent
+     */
+    'tasks'?: Array<ProfilingTaskProgress>;
+}
+
@@ -0,0 +1,55 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ProfilingTopFunction } from './profiling-top-function';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingGroupTopResponse
+ */
+export interface ProfilingGroupTopResponse {
+    /**
+     * 
+     * @type {Array<ProfilingTopFunction>}
+     * @memberof ProfilingGroupTopResponse
+     */
+    'functions'?: Array<ProfilingTopFunction>;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingGroupTopResponse
+     */
+    'num_profiles'?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingGroupTopResponse
+     */
+    'profiling_type'?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingGroupTopResponse
+     */
+    'sample_type'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingGroupTopResponse
+     */
+    'total'?: number;
+}
+
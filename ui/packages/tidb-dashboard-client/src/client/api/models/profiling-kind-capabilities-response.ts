@@ -0,0 +1,30 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingKindCapabilitiesResponse
+ */
+export interface ProfilingKindCapabilitiesResponse {
+    /**
+     * The profiling types served by each kind of component which can be profiled. Tasks of other profiling types are skipped.
+     * @type {{ [key: string]: Array<string>; }}
+     * @memberof ProfilingKindCapabilitiesResponse
+     */
+    'profiling_types'?: { [key: string]: Array<string>; };
+}
+
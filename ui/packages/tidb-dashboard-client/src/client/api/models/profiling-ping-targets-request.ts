@@ -0,0 +1,37 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ModelRequestTargetNode } from './model-request-target-node';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingPingTargetsRequest
+ */
+export interface ProfilingPingTargetsRequest {
+    /**
+     * Reject the request if any target is no longer present in the cluster topology, the same as StartRequest.
+     * @type {boolean}
+     * @memberof ProfilingPingTargetsRequest
+     */
+    'check_topology'?: boolean;
+    /**
+     * 
+     * @type {Array<ModelRequestTargetNode>}
+     * @memberof ProfilingPingTargetsRequest
+     */
+    'targets'?: Array<ModelRequestTargetNode>;
+}
+
@@ -0,0 +1,85 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ProfilingTopFunction } from './profiling-top-function';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingProfilePreview
+ */
+export interface ProfilingProfilePreview {
+    /**
+     * The profile duration, which is 0 for snapshot profiling types, e.g. heap.
+     * @type {number}
+     * @memberof ProfilingProfilePreview
+     */
+    'duration_secs'?: number;
+    /**
+     * 
+     * @type {Array<ProfilingTopFunction>}
+     * @memberof ProfilingProfilePreview
+     */
+    'functions'?: Array<ProfilingTopFunction>;
+    /**
+     * The number of goroutines in each wait state, e.g. \"running\" or \"chan receive\", only for goroutine dumps with full stacks.
+     * @type {{ [key: string]: number; }}
+     * @memberof ProfilingProfilePreview
+     */
+    'goroutines_by_state'?: { [key: string]: number; };
+    /**
+     * The number of goroutines, only for goroutine dumps.
+     * @type {number}
+     * @memberof ProfilingProfilePreview
+     */
+    'num_goroutines'?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingProfilePreview
+     */
+    'profiling_type'?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingProfilePreview
+     */
+    'raw_data_type'?: string;
+    /**
+     * The sample type, the total value of samples and the top functions by the flat value, only for profiles in the protobuf format, e.g. CPU and heap.
+     * @type {string}
+     * @memberof ProfilingProfilePreview
+     */
+    'sample_type'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingProfilePreview
+     */
+    'size_bytes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingProfilePreview
+     */
+    'task_id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingProfilePreview
+     */
+    'total'?: number;
+}
+
@@ -0,0 +1,36 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingRegisterBaselineRequest
+ */
+export interface ProfilingRegisterBaselineRequest {
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingRegisterBaselineRequest
+     */
+    'name'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingRegisterBaselineRequest
+     */
+    'task_id'?: number;
+}
+
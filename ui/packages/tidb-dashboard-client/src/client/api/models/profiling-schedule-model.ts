@@ -0,0 +1,61 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ProfilingScheduleRequest } from './profiling-schedule-request';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingScheduleModel
+ */
+export interface ProfilingScheduleModel {
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingScheduleModel
+     */
+    'created_at'?: number;
+    /**
+     * Why the last run failed to start, or empty if it is started.
+     * @type {string}
+     * @memberof ProfilingScheduleModel
+     */
+    'error'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingScheduleModel
+     */
+    'id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingScheduleModel
+     */
+    'interval_secs'?: number;
+    /**
+     * The time when the last run is triggered, or 0 if the schedule has not been run yet.
+     * @type {number}
+     * @memberof ProfilingScheduleModel
+     */
+    'last_run_at'?: number;
+    /**
+     * 
+     * @type {ProfilingScheduleRequest}
+     * @memberof ProfilingScheduleModel
+     */
+    'request'?: ProfilingScheduleRequest;
+}
+
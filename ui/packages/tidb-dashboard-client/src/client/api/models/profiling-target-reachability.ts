@@ -0,0 +1,43 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ModelRequestTargetNode } from './model-request-target-node';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingTargetReachability
+ */
+export interface ProfilingTargetReachability {
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingTargetReachability
+     */
+    'error'?: string;
+    /**
+     * 
+     * @type {boolean}
+     * @memberof ProfilingTargetReachability
+     */
+    'reachable'?: boolean;
+    /**
+     * 
+     * @type {ModelRequestTargetNode}
+     * @memberof ProfilingTargetReachability
+     */
+    'target'?: ModelRequestTargetNode;
+}
+
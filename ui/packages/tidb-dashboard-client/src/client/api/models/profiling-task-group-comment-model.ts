@@ -0,0 +1,54 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingTaskGroupCommentModel
+ */
+export interface ProfilingTaskGroupCommentModel {
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingTaskGroupCommentModel
+     */
+    'author'?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingTaskGroupCommentModel
+     */
+    'content'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTaskGroupCommentModel
+     */
+    'created_at'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTaskGroupCommentModel
+     */
+    'id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTaskGroupCommentModel
+     */
+    'task_group_id'?: number;
+}
+
@@ -21,12 +21,30 @@ import { ModelRequestTargetStatistics } from './model-request-target-statistics'
  * @interface ProfilingTaskGroupModel
  */
 export interface ProfilingTaskGroupModel {
+    /**
+     * The campaign which the task group is an iteration of, or 0 if it is started alone.
+     * @type {number}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'campaign_id'?: number;
     /**
      * 
      * @type {number}
      * @memberof ProfilingTaskGroupModel
      */
     'id'?: number;
+    /**
+     * Labels given when the task group is started, which are stored in TaskGroupLabelModel and only filled in responses.
+     * @type {{ [key: string]: string; }}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'labels'?: { [key: string]: string; };
+    /**
+     * Why the task group is captured, given when it is started.
+     * @type {string}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'note'?: string;
     /**
      * 
      * @type {number}
@@ -39,6 +57,18 @@ export interface ProfilingTaskGroupModel {
      * @memberof ProfilingTaskGroupModel
      */
     'requsted_profiling_types'?: Array<string>;
+    /**
+     * The task group which is retried by this task group because all of its tasks are failed, or 0 if it is not a retry.
+     * @type {number}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'retry_of'?: number;
+    /**
+     * The schedule which started the task group, or 0 if it is not started by a schedule.
+     * @type {number}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'schedule_id'?: number;
     /**
      * 
      * @type {number}
@@ -57,5 +87,11 @@ export interface ProfilingTaskGroupModel {
      * @memberof ProfilingTaskGroupModel
      */
     'target_stats'?: ModelRequestTargetStatistics;
+    /**
+     * The display name of the user who started the task group, or empty if it is started automatically, e.g. by a schedule.
+     * @type {string}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'triggered_by'?: string;
 }
 
@@ -21,12 +21,42 @@ import { ModelRequestTargetNode } from './model-request-target-node';
  * @interface ProfilingTaskModel
  */
 export interface ProfilingTaskModel {
+    /**
+     * Starts from 1 and increases on each retry, so that a reset of StartedAt can be told apart from a stalled progress.
+     * @type {number}
+     * @memberof ProfilingTaskModel
+     */
+    'attempt'?: number;
+    /**
+     * The git hash of the target binary when profiled, which can be used to match symbols. Empty if not captured.
+     * @type {string}
+     * @memberof ProfilingTaskModel
+     */
+    'build_id'?: string;
+    /**
+     * The path with the query fetched from the target, only for ProfilingTypeCustom.
+     * @type {string}
+     * @memberof ProfilingTaskModel
+     */
+    'custom_path'?: string;
+    /**
+     * The profile duration of the task, which is ignored by snapshot profiling types. The duration of the task group is used when it is 0, e.g. for tasks saved by earlier versions.
+     * @type {number}
+     * @memberof ProfilingTaskModel
+     */
+    'duration_secs'?: number;
     /**
      * 
      * @type {string}
      * @memberof ProfilingTaskModel
      */
     'error'?: string;
+    /**
+     * The time when the task is stopped in any state, or 0 if it is still running or was interrupted by restart.
+     * @type {number}
+     * @memberof ProfilingTaskModel
+     */
+    'finished_at'?: number;
     /**
      * 
      * @type {number}
@@ -39,6 +69,12 @@ export interface ProfilingTaskModel {
      * @memberof ProfilingTaskModel
      */
     'profiling_type'?: string;
+    /**
+     * Estimated progress in range [0, 1]. It is not persisted and is only filled in responses.
+     * @type {number}
+     * @memberof ProfilingTaskModel
+     */
+    'progress'?: number;
     /**
      * 
      * @type {string}
@@ -46,7 +82,25 @@ export interface ProfilingTaskModel {
      */
     'raw_data_type'?: string;
     /**
-     * The start running time, reset when retry. Used to estimate approximate profiling progress.
+     * The address and the path with the query requested from the target, e.g. 10.0.0.1:10080/debug/pprof/heap, which is recorded for debugging. The scheme is decided by the TLS config of the component and is omitted.
+     * @type {string}
+     * @memberof ProfilingTaskModel
+     */
+    'request_url'?: string;
+    /**
+     * The size of the profile downloaded from the target in bytes, before it is compressed or encrypted. It is 0 unless the task is finished.
+     * @type {number}
+     * @memberof ProfilingTaskModel
+     */
+    'size_bytes'?: number;
+    /**
+     * Why the task is skipped. Only valid when the state is TaskStateSkipped.
+     * @type {string}
+     * @memberof ProfilingTaskModel
+     */
+    'skip_reason'?: string;
+    /**
+     * The start running time, reset when retry, or 0 if the task is waiting for a concurrency slot. Used to estimate approximate profiling progress.
      * @type {number}
      * @memberof ProfilingTaskModel
      */
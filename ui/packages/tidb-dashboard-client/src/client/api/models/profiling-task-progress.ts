@@ -0,0 +1,42 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingTaskProgress
+ */
+export interface ProfilingTaskProgress {
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTaskProgress
+     */
+    'id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTaskProgress
+     */
+    'progress'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTaskProgress
+     */
+    'state'?: number;
+}
+
@@ -0,0 +1,37 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+import { ProfilingTaskModel } from './profiling-task-model';
+
+/**
+ * 
+ * @export
+ * @interface ProfilingTaskWithData
+ */
+export interface ProfilingTaskWithData {
+    /**
+     * The profiling result, which is empty unless the task is finished.
+     * @type {Array<number>}
+     * @memberof ProfilingTaskWithData
+     */
+    'data'?: Array<number>;
+    /**
+     * 
+     * @type {ProfilingTaskModel}
+     * @memberof ProfilingTaskWithData
+     */
+    'task'?: ProfilingTaskModel;
+}
+
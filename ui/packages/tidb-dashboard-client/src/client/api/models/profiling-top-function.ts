@@ -0,0 +1,42 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Dashboard API
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: 1.0
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingTopFunction
+ */
+export interface ProfilingTopFunction {
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTopFunction
+     */
+    'flat'?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingTopFunction
+     */
+    'function'?: string;
+    /**
+     * Share of the total value, in range [0, 1]
+     * @type {number}
+     * @memberof ProfilingTopFunction
+     */
+    'share'?: number;
+}
+
@@ -29,12 +29,15 @@ enum taskState {
   Error,
   Running,
   Success,
-  Skipped = 4
+  Skipped = 4,
+  Cancelled = 5
 }
 
 enum RawDataType {
   Protobuf = 'protobuf',
-  Text = 'text'
+  Text = 'text',
+  Trace = 'trace',
+  Raw = 'raw'
 }
 
 interface IRow {
@@ -47,19 +50,7 @@ function mapData(data) {
   }
 
   data.tasks_status.forEach((task) => {
-    if (task.state === 1) {
-      let task_elapsed_secs = data.server_time - task.started_at
-      let progress =
-        task_elapsed_secs / data.task_group_status.profile_duration_secs
-      if (progress > 0.99) {
-        progress = 0.99
-      }
-      if (progress < 0) {
-        progress = 0
-      }
-      task.progress = progress
-    }
-
+    // The progress of each task is estimated by the server.
     // set profiling output options for previous generated SVG files and protobuf files.
     if (task.raw_data_type === RawDataType.Protobuf) {
       task.view_options = [
@@ -69,6 +60,12 @@ function mapData(data) {
       ]
     } else if (task.raw_data_type === RawDataType.Text) {
       task.view_options = [ViewOptions.Text]
+    } else if (task.raw_data_type === RawDataType.Trace) {
+      // execution traces can only be opened by `go tool trace` after downloading
+      task.view_options = [ViewOptions.Download]
+    } else if (task.raw_data_type === RawDataType.Raw) {
+      // results of custom pprof handlers have an unknown format
+      task.view_options = [ViewOptions.Download]
     } else if (task.raw_data_type === '') {
       switch (task.target.kind) {
         case 'tidb':
@@ -91,6 +88,10 @@ function isFinished(data) {
   return groupState === 2 || groupState === 3
 }
 
+function isCancelled(data) {
+  return data?.task_group_status?.state === taskState.Cancelled
+}
+
 async function getActionToken(
   id: string,
   apiType: string,
@@ -121,7 +122,7 @@ export default function Page() {
   } = useClientRequestWithPolling(
     (reqConfig) => ctx!.ds.getProfilingGroupDetail(id, reqConfig),
     {
-      shouldPoll: (data) => !isFinished(data)
+      shouldPoll: (data) => !isFinished(data) && !isCancelled(data)
     }
   )
 
@@ -237,7 +238,7 @@ export default function Page() {
         maxWidth: 100,
         onRender: (record) => {
           if (record.profiling_type === 'cpu') {
-            return `CPU - ${profileDuration}s`
+            return `CPU - ${record.duration_secs || profileDuration}s`
           } else {
             return upperFirst(record.profiling_type)
           }
@@ -266,9 +267,15 @@ export default function Page() {
           } else if (record.state === taskState.Skipped) {
             return (
               <Tooltip
-                title={t(
-                  'instance_profiling.detail.table.status.skipped_tooltip'
-                )}
+                title={
+                  record.skip_reason === 'client_not_configured'
+                    ? t(
+                        'instance_profiling.detail.table.status.skipped_client_not_configured_tooltip'
+                      )
+                    : t(
+                        'instance_profiling.detail.table.status.skipped_tooltip'
+                      )
+                }
               >
                 <Space>
                   <Badge
@@ -279,6 +286,13 @@ export default function Page() {
                 </Space>
               </Tooltip>
             )
+          } else if (record.state === taskState.Cancelled) {
+            return (
+              <Badge
+                status="default"
+                text={t('instance_profiling.detail.table.status.cancelled')}
+              />
+            )
           } else {
             return (
               <Badge
@@ -173,6 +173,14 @@ export default function Page() {
                 text={t('instance_profiling.list.table.status.finished')}
               />
             )
+          } else if (rec.state === 5) {
+            // cancelled before any task is finished
+            return (
+              <Badge
+                status="default"
+                text={t('instance_profiling.list.table.status.cancelled')}
+              />
+            )
           } else {
             // partial success
             return (
@@ -1726,6 +1726,24 @@ export interface ModelRequestTargetStatistics {
      * @memberof ModelRequestTargetStatistics
      */
     'num_pd_nodes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ModelRequestTargetStatistics
+     */
+    'num_pd_scheduling_nodes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ModelRequestTargetStatistics
+     */
+    'num_pd_tso_nodes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ModelRequestTargetStatistics
+     */
+    'num_ticdc_nodes'?: number;
     /**
      * 
      * @type {number}
@@ -1733,17 +1751,1112 @@ export interface ModelRequestTargetStatistics {
      */
     'num_tidb_nodes'?: number;
     /**
-     * 
+     * 
+     * @type {number}
+     * @memberof ModelRequestTargetStatistics
+     */
+    'num_tiflash_nodes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ModelRequestTargetStatistics
+     */
+    'num_tikv_nodes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ModelRequestTargetStatistics
+     */
+    'num_tiproxy_nodes'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingAddGroupCommentRequest
+ */
+export interface ProfilingAddGroupCommentRequest {
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingAddGroupCommentRequest
+     */
+    'content'?: string;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingBaselineCompareResult
+ */
+export interface ProfilingBaselineCompareResult {
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingBaselineCompareResult
+     */
+    'baseline'?: string;
+    /**
+     * 
+     * @type {Array<ProfilingBaselineDiffEntry>}
+     * @memberof ProfilingBaselineCompareResult
+     */
+    'diff'?: Array<ProfilingBaselineDiffEntry>;
+    /**
+     * 
+     * @type {Array<ProfilingBaselineDiffEntry>}
+     * @memberof ProfilingBaselineCompareResult
+     */
+    'regressions'?: Array<ProfilingBaselineDiffEntry>;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineCompareResult
+     */
+    'task_id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineCompareResult
+     */
+    'threshold'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingBaselineDiffEntry
+ */
+export interface ProfilingBaselineDiffEntry {
+    /**
+     * Share of the total samples in the baseline / current profile, in range [0, 1].
+     * @type {number}
+     * @memberof ProfilingBaselineDiffEntry
+     */
+    'baseline_share'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineDiffEntry
+     */
+    'current_share'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineDiffEntry
+     */
+    'delta'?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingBaselineDiffEntry
+     */
+    'function'?: string;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingBaselineModel
+ */
+export interface ProfilingBaselineModel {
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineModel
+     */
+    'created_at'?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingBaselineModel
+     */
+    'name'?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingBaselineModel
+     */
+    'profiling_type'?: string;
+    /**
+     * 
+     * @type {ModelRequestTargetNode}
+     * @memberof ProfilingBaselineModel
+     */
+    'target'?: ModelRequestTargetNode;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingBaselineModel
+     */
+    'task_id'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingCampaignDetailResponse
+ */
+export interface ProfilingCampaignDetailResponse {
+    /**
+     * 
+     * @type {ProfilingCampaignModel}
+     * @memberof ProfilingCampaignDetailResponse
+     */
+    'campaign'?: ProfilingCampaignModel;
+    /**
+     * 
+     * @type {Array<ProfilingTaskGroupModel>}
+     * @memberof ProfilingCampaignDetailResponse
+     */
+    'task_groups'?: Array<ProfilingTaskGroupModel>;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingCampaignModel
+ */
+export interface ProfilingCampaignModel {
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingCampaignModel
+     */
+    'error'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingCampaignModel
+     */
+    'gap_secs'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingCampaignModel
+     */
+    'id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingCampaignModel
+     */
+    'iterations'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingCampaignModel
+     */
+    'started_at'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingCampaignModel
+     */
+    'state'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingCloneGroupRequest
+ */
+export interface ProfilingCloneGroupRequest {
+    /**
+     * The duration of the cloned task group. The duration of the original task group is used when it is 0.
+     * @type {number}
+     * @memberof ProfilingCloneGroupRequest
+     */
+    'duration_secs'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingCreateScheduleRequest
+ */
+export interface ProfilingCreateScheduleRequest {
+    /**
+     * Record the build ID of each target along with its profiling results, on a best-effort basis.
+     * @type {boolean}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'capture_build_id'?: boolean;
+    /**
+     * Reject the request if any target is no longer present in the cluster topology.
+     * @type {boolean}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'check_topology'?: boolean;
+    /**
+     * The pprof handler to fetch for ProfilingTypeCustom, e.g. /debug/pprof/custom, which must be under /debug/pprof/ of the status port of targets. It may contain a query.
+     * @type {string}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'custom_pprof_path'?: string;
+    /**
+     * Seconds passed to the custom pprof handler as the seconds query, which is not passed when it is 0.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'custom_pprof_seconds'?: number;
+    /**
+     * Duration of profiles in seconds, which must be greater than 0 and not exceed the configured maximum.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'duration_secs'?: number;
+    /**
+     * Durations of profiling types, which override DurationSecs for tasks of these types, e.g. a shorter trace along with a longer CPU profile. Durations are ignored by snapshot profiling types, e.g. heap.
+     * @type {{ [key: string]: number; }}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'duration_secs_by_type'?: { [key: string]: number; };
+    /**
+     * Durations which override DurationSecs with a task per duration for each target, e.g. both a 10 seconds and a 30 seconds CPU profile of a target to compare. They are ignored by snapshot profiling types, ProfilingTypeCustom and profiling types in DurationSecsByType, which still capture a single task.
+     * @type {Array<number>}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'duration_secs_list'?: Array<number>;
+    /**
+     * The interval between the starts of two runs, which must not be shorter than the profile duration.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'interval_secs'?: number;
+    /**
+     * Labels of the task group, by which task groups can be filtered when listing.
+     * @type {{ [key: string]: string; }}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'labels'?: { [key: string]: string; };
+    /**
+     * Only profile the leader among the PD targets.
+     * @type {boolean}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'leader_only'?: boolean;
+    /**
+     * The maximum number of tasks profiling at the same time, to avoid disturbing a large cluster. All tasks are run at once when it is 0.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'max_concurrency'?: number;
+    /**
+     * The mutex profile fraction set on TiDB targets while profiling mutex contentions, which are sampled during the profile duration. The fraction is reset to 0 afterwards. It is not changed when it is 0.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'mutex_profile_fraction'?: number;
+    /**
+     * A free-text note about why the task group is captured.
+     * @type {string}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'note'?: string;
+    /**
+     * Profiling types of targets of a component kind, which override RequstedProfilingTypes for these targets. Without RequstedProfilingTypes, every kind of targets must have profiling types here.
+     * @type {{ [key: string]: Array<string>; }}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'profiling_types_by_kind'?: { [key: string]: Array<string>; };
+    /**
+     * Seconds allowed for fetching each profile beyond the profile duration, after which the task is failed. The configured default is used when it is 0.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'request_timeout_secs'?: number;
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'requsted_profiling_types'?: Array<string>;
+    /**
+     * Run the task group again once if all of its tasks are failed, e.g. due to a transient cluster-wide failure.
+     * @type {boolean}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'retry_on_total_failure'?: boolean;
+    /**
+     * The maximum delay in milliseconds between launching two tasks, so that targets are not profiled at exactly the same time, which smooths the load of the cluster. The delay is randomized between half of it and itself. All tasks are launched at once when it is 0.
+     * @type {number}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'stagger_ms'?: number;
+    /**
+     * 
+     * @type {Array<ModelRequestTargetNode>}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'targets'?: Array<ModelRequestTargetNode>;
+    /**
+     * TiKV stores to profile in addition to Targets, which are resolved to addresses by the cluster topology.
+     * @type {Array<number>}
+     * @memberof ProfilingCreateScheduleRequest
+     */
+    'tikv_store_ids'?: Array<number>;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingEstimateGroupResponse
+ */
+export interface ProfilingEstimateGroupResponse {
+    /**
+     * The approximate total size in bytes of the profiles, which only covers the estimated tasks.
+     * @type {number}
+     * @memberof ProfilingEstimateGroupResponse
+     */
+    'approx_bytes'?: number;
+    /**
+     * The number of tasks which are not skipped.
+     * @type {number}
+     * @memberof ProfilingEstimateGroupResponse
+     */
+    'num_tasks'?: number;
+    /**
+     * The number of tasks which cannot be estimated, since no task of the same kind of component and profiling type is finished recently.
+     * @type {number}
+     * @memberof ProfilingEstimateGroupResponse
+     */
+    'num_unknown_tasks'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingGroupDetailResponse
+ */
+export interface ProfilingGroupDetailResponse {
+    /**
+     * 
+     * @type {Array<ProfilingTaskGroupCommentModel>}
+     * @memberof ProfilingGroupDetailResponse
+     */
+    'comments'?: Array<ProfilingTaskGroupCommentModel>;
+    /**
+     * Number of failed tasks by their distinct error messages, so that the failures of a partially finished task group can be understood without checking every task.
+     * @type {{ [key: string]: number; }}
+     * @memberof ProfilingGroupDetailResponse
+     */
+    'error_summary'?: { [key: string]: number; };
+    /**
+     * Profiling types that produced at least one result, which may be a subset of the requested types since some components do not support all profiling types.
+     * @type {Array<string>}
+     * @memberof ProfilingGroupDetailResponse
+     */
+    'observed_profiling_types'?: Array<string>;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingGroupDetailResponse
+     */
+    'server_time'?: number;
+    /**
+     * 
+     * @type {ProfilingTaskGroupModel}
+     * @memberof ProfilingGroupDetailResponse
+     */
+    'task_group_status'?: ProfilingTaskGroupModel;
+    /**
+     * Tasks of the task group, or nil if the tasks are not requested. Fields derived from the tasks are filled in either case.
+     * @type {Array<ProfilingTaskModel>}
+     * @memberof ProfilingGroupDetailResponse
+     */
+    'tasks_status'?: Array<ProfilingTaskModel>;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingGroupProgressEvent
+ */
+export interface ProfilingGroupProgressEvent {
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingGroupProgressEvent
+     */
+    'state'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingGroupProgressEvent
+     */
+    'task_group_id'?: number;
+    /**
+     * 
+     * @type {Array<ProfilingTaskProgress>}
+     * @memberof ProfilingGroupProgressEvent
+     */
+    'tasks'?: Array<ProfilingTaskProgress>;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingGroupTopResponse
+ */
+export interface ProfilingGroupTopResponse {
+    /**
+     * 
+     * @type {Array<ProfilingTopFunction>}
+     * @memberof ProfilingGroupTopResponse
+     */
+    'functions'?: Array<ProfilingTopFunction>;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingGroupTopResponse
+     */
+    'num_profiles'?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingGroupTopResponse
+     */
+    'profiling_type'?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingGroupTopResponse
+     */
+    'sample_type'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingGroupTopResponse
+     */
+    'total'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingKindCapabilitiesResponse
+ */
+export interface ProfilingKindCapabilitiesResponse {
+    /**
+     * The profiling types served by each kind of component which can be profiled. Tasks of other profiling types are skipped.
+     * @type {{ [key: string]: Array<string>; }}
+     * @memberof ProfilingKindCapabilitiesResponse
+     */
+    'profiling_types'?: { [key: string]: Array<string>; };
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingListGroupsResponse
+ */
+export interface ProfilingListGroupsResponse {
+    /**
+     * 
+     * @type {Array<ProfilingTaskGroupModel>}
+     * @memberof ProfilingListGroupsResponse
+     */
+    'groups'?: Array<ProfilingTaskGroupModel>;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingListGroupsResponse
+     */
+    'total'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingPingTargetsRequest
+ */
+export interface ProfilingPingTargetsRequest {
+    /**
+     * Reject the request if any target is no longer present in the cluster topology, the same as StartRequest.
+     * @type {boolean}
+     * @memberof ProfilingPingTargetsRequest
+     */
+    'check_topology'?: boolean;
+    /**
+     * 
+     * @type {Array<ModelRequestTargetNode>}
+     * @memberof ProfilingPingTargetsRequest
+     */
+    'targets'?: Array<ModelRequestTargetNode>;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingProfilePreview
+ */
+export interface ProfilingProfilePreview {
+    /**
+     * The profile duration, which is 0 for snapshot profiling types, e.g. heap.
+     * @type {number}
+     * @memberof ProfilingProfilePreview
+     */
+    'duration_secs'?: number;
+    /**
+     * 
+     * @type {Array<ProfilingTopFunction>}
+     * @memberof ProfilingProfilePreview
+     */
+    'functions'?: Array<ProfilingTopFunction>;
+    /**
+     * The number of goroutines in each wait state, e.g. \"running\" or \"chan receive\", only for goroutine dumps with full stacks.
+     * @type {{ [key: string]: number; }}
+     * @memberof ProfilingProfilePreview
+     */
+    'goroutines_by_state'?: { [key: string]: number; };
+    /**
+     * The number of goroutines, only for goroutine dumps.
+     * @type {number}
+     * @memberof ProfilingProfilePreview
+     */
+    'num_goroutines'?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingProfilePreview
+     */
+    'profiling_type'?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingProfilePreview
+     */
+    'raw_data_type'?: string;
+    /**
+     * The sample type, the total value of samples and the top functions by the flat value, only for profiles in the protobuf format, e.g. CPU and heap.
+     * @type {string}
+     * @memberof ProfilingProfilePreview
+     */
+    'sample_type'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingProfilePreview
+     */
+    'size_bytes'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingProfilePreview
+     */
+    'task_id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingProfilePreview
+     */
+    'total'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingRegisterBaselineRequest
+ */
+export interface ProfilingRegisterBaselineRequest {
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingRegisterBaselineRequest
+     */
+    'name'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingRegisterBaselineRequest
+     */
+    'task_id'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingScheduleModel
+ */
+export interface ProfilingScheduleModel {
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingScheduleModel
+     */
+    'created_at'?: number;
+    /**
+     * Why the last run failed to start, or empty if it is started.
+     * @type {string}
+     * @memberof ProfilingScheduleModel
+     */
+    'error'?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingScheduleModel
+     */
+    'id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingScheduleModel
+     */
+    'interval_secs'?: number;
+    /**
+     * The time when the last run is triggered, or 0 if the schedule has not been run yet.
+     * @type {number}
+     * @memberof ProfilingScheduleModel
+     */
+    'last_run_at'?: number;
+    /**
+     * 
+     * @type {ProfilingScheduleRequest}
+     * @memberof ProfilingScheduleModel
+     */
+    'request'?: ProfilingScheduleRequest;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingScheduleRequest
+ */
+export interface ProfilingScheduleRequest {
+    /**
+     * Record the build ID of each target along with its profiling results, on a best-effort basis.
+     * @type {boolean}
+     * @memberof ProfilingScheduleRequest
+     */
+    'capture_build_id'?: boolean;
+    /**
+     * Reject the request if any target is no longer present in the cluster topology.
+     * @type {boolean}
+     * @memberof ProfilingScheduleRequest
+     */
+    'check_topology'?: boolean;
+    /**
+     * The pprof handler to fetch for ProfilingTypeCustom, e.g. /debug/pprof/custom, which must be under /debug/pprof/ of the status port of targets. It may contain a query.
+     * @type {string}
+     * @memberof ProfilingScheduleRequest
+     */
+    'custom_pprof_path'?: string;
+    /**
+     * Seconds passed to the custom pprof handler as the seconds query, which is not passed when it is 0.
+     * @type {number}
+     * @memberof ProfilingScheduleRequest
+     */
+    'custom_pprof_seconds'?: number;
+    /**
+     * Duration of profiles in seconds, which must be greater than 0 and not exceed the configured maximum.
+     * @type {number}
+     * @memberof ProfilingScheduleRequest
+     */
+    'duration_secs'?: number;
+    /**
+     * Durations of profiling types, which override DurationSecs for tasks of these types, e.g. a shorter trace along with a longer CPU profile. Durations are ignored by snapshot profiling types, e.g. heap.
+     * @type {{ [key: string]: number; }}
+     * @memberof ProfilingScheduleRequest
+     */
+    'duration_secs_by_type'?: { [key: string]: number; };
+    /**
+     * Durations which override DurationSecs with a task per duration for each target, e.g. both a 10 seconds and a 30 seconds CPU profile of a target to compare. They are ignored by snapshot profiling types, ProfilingTypeCustom and profiling types in DurationSecsByType, which still capture a single task.
+     * @type {Array<number>}
+     * @memberof ProfilingScheduleRequest
+     */
+    'duration_secs_list'?: Array<number>;
+    /**
+     * Labels of the task group, by which task groups can be filtered when listing.
+     * @type {{ [key: string]: string; }}
+     * @memberof ProfilingScheduleRequest
+     */
+    'labels'?: { [key: string]: string; };
+    /**
+     * Only profile the leader among the PD targets.
+     * @type {boolean}
+     * @memberof ProfilingScheduleRequest
+     */
+    'leader_only'?: boolean;
+    /**
+     * The maximum number of tasks profiling at the same time, to avoid disturbing a large cluster. All tasks are run at once when it is 0.
+     * @type {number}
+     * @memberof ProfilingScheduleRequest
+     */
+    'max_concurrency'?: number;
+    /**
+     * The mutex profile fraction set on TiDB targets while profiling mutex contentions, which are sampled during the profile duration. The fraction is reset to 0 afterwards. It is not changed when it is 0.
+     * @type {number}
+     * @memberof ProfilingScheduleRequest
+     */
+    'mutex_profile_fraction'?: number;
+    /**
+     * A free-text note about why the task group is captured.
+     * @type {string}
+     * @memberof ProfilingScheduleRequest
+     */
+    'note'?: string;
+    /**
+     * Profiling types of targets of a component kind, which override RequstedProfilingTypes for these targets. Without RequstedProfilingTypes, every kind of targets must have profiling types here.
+     * @type {{ [key: string]: Array<string>; }}
+     * @memberof ProfilingScheduleRequest
+     */
+    'profiling_types_by_kind'?: { [key: string]: Array<string>; };
+    /**
+     * Seconds allowed for fetching each profile beyond the profile duration, after which the task is failed. The configured default is used when it is 0.
+     * @type {number}
+     * @memberof ProfilingScheduleRequest
+     */
+    'request_timeout_secs'?: number;
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof ProfilingScheduleRequest
+     */
+    'requsted_profiling_types'?: Array<string>;
+    /**
+     * Run the task group again once if all of its tasks are failed, e.g. due to a transient cluster-wide failure.
+     * @type {boolean}
+     * @memberof ProfilingScheduleRequest
+     */
+    'retry_on_total_failure'?: boolean;
+    /**
+     * The maximum delay in milliseconds between launching two tasks, so that targets are not profiled at exactly the same time, which smooths the load of the cluster. The delay is randomized between half of it and itself. All tasks are launched at once when it is 0.
+     * @type {number}
+     * @memberof ProfilingScheduleRequest
+     */
+    'stagger_ms'?: number;
+    /**
+     * 
+     * @type {Array<ModelRequestTargetNode>}
+     * @memberof ProfilingScheduleRequest
+     */
+    'targets'?: Array<ModelRequestTargetNode>;
+    /**
+     * TiKV stores to profile in addition to Targets, which are resolved to addresses by the cluster topology.
+     * @type {Array<number>}
+     * @memberof ProfilingScheduleRequest
+     */
+    'tikv_store_ids'?: Array<number>;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingStartCampaignRequest
+ */
+export interface ProfilingStartCampaignRequest {
+    /**
+     * Record the build ID of each target along with its profiling results, on a best-effort basis.
+     * @type {boolean}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'capture_build_id'?: boolean;
+    /**
+     * Reject the request if any target is no longer present in the cluster topology.
+     * @type {boolean}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'check_topology'?: boolean;
+    /**
+     * The pprof handler to fetch for ProfilingTypeCustom, e.g. /debug/pprof/custom, which must be under /debug/pprof/ of the status port of targets. It may contain a query.
+     * @type {string}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'custom_pprof_path'?: string;
+    /**
+     * Seconds passed to the custom pprof handler as the seconds query, which is not passed when it is 0.
+     * @type {number}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'custom_pprof_seconds'?: number;
+    /**
+     * Duration of profiles in seconds, which must be greater than 0 and not exceed the configured maximum.
+     * @type {number}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'duration_secs'?: number;
+    /**
+     * Durations of profiling types, which override DurationSecs for tasks of these types, e.g. a shorter trace along with a longer CPU profile. Durations are ignored by snapshot profiling types, e.g. heap.
+     * @type {{ [key: string]: number; }}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'duration_secs_by_type'?: { [key: string]: number; };
+    /**
+     * Durations which override DurationSecs with a task per duration for each target, e.g. both a 10 seconds and a 30 seconds CPU profile of a target to compare. They are ignored by snapshot profiling types, ProfilingTypeCustom and profiling types in DurationSecsByType, which still capture a single task.
+     * @type {Array<number>}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'duration_secs_list'?: Array<number>;
+    /**
+     * The gap between the end of an iteration and the start of the next one
+     * @type {number}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'gap_secs'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'iterations'?: number;
+    /**
+     * Labels of the task group, by which task groups can be filtered when listing.
+     * @type {{ [key: string]: string; }}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'labels'?: { [key: string]: string; };
+    /**
+     * Only profile the leader among the PD targets.
+     * @type {boolean}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'leader_only'?: boolean;
+    /**
+     * The maximum number of tasks profiling at the same time, to avoid disturbing a large cluster. All tasks are run at once when it is 0.
+     * @type {number}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'max_concurrency'?: number;
+    /**
+     * The mutex profile fraction set on TiDB targets while profiling mutex contentions, which are sampled during the profile duration. The fraction is reset to 0 afterwards. It is not changed when it is 0.
+     * @type {number}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'mutex_profile_fraction'?: number;
+    /**
+     * A free-text note about why the task group is captured.
+     * @type {string}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'note'?: string;
+    /**
+     * Profiling types of targets of a component kind, which override RequstedProfilingTypes for these targets. Without RequstedProfilingTypes, every kind of targets must have profiling types here.
+     * @type {{ [key: string]: Array<string>; }}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'profiling_types_by_kind'?: { [key: string]: Array<string>; };
+    /**
+     * Seconds allowed for fetching each profile beyond the profile duration, after which the task is failed. The configured default is used when it is 0.
+     * @type {number}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'request_timeout_secs'?: number;
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'requsted_profiling_types'?: Array<string>;
+    /**
+     * Run the task group again once if all of its tasks are failed, e.g. due to a transient cluster-wide failure.
+     * @type {boolean}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'retry_on_total_failure'?: boolean;
+    /**
+     * The maximum delay in milliseconds between launching two tasks, so that targets are not profiled at exactly the same time, which smooths the load of the cluster. The delay is randomized between half of it and itself. All tasks are launched at once when it is 0.
+     * @type {number}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'stagger_ms'?: number;
+    /**
+     * 
+     * @type {Array<ModelRequestTargetNode>}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'targets'?: Array<ModelRequestTargetNode>;
+    /**
+     * TiKV stores to profile in addition to Targets, which are resolved to addresses by the cluster topology.
+     * @type {Array<number>}
+     * @memberof ProfilingStartCampaignRequest
+     */
+    'tikv_store_ids'?: Array<number>;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingStartRequest
+ */
+export interface ProfilingStartRequest {
+    /**
+     * Record the build ID of each target along with its profiling results, on a best-effort basis.
+     * @type {boolean}
+     * @memberof ProfilingStartRequest
+     */
+    'capture_build_id'?: boolean;
+    /**
+     * Reject the request if any target is no longer present in the cluster topology.
+     * @type {boolean}
+     * @memberof ProfilingStartRequest
+     */
+    'check_topology'?: boolean;
+    /**
+     * The pprof handler to fetch for ProfilingTypeCustom, e.g. /debug/pprof/custom, which must be under /debug/pprof/ of the status port of targets. It may contain a query.
+     * @type {string}
+     * @memberof ProfilingStartRequest
+     */
+    'custom_pprof_path'?: string;
+    /**
+     * Seconds passed to the custom pprof handler as the seconds query, which is not passed when it is 0.
      * @type {number}
-     * @memberof ModelRequestTargetStatistics
+     * @memberof ProfilingStartRequest
      */
-    'num_tiflash_nodes'?: number;
+    'custom_pprof_seconds'?: number;
+    /**
+     * Duration of profiles in seconds, which must be greater than 0 and not exceed the configured maximum.
+     * @type {number}
+     * @memberof ProfilingStartRequest
+     */
+    'duration_secs'?: number;
+    /**
+     * Durations of profiling types, which override DurationSecs for tasks of these types, e.g. a shorter trace along with a longer CPU profile. Durations are ignored by snapshot profiling types, e.g. heap.
+     * @type {{ [key: string]: number; }}
+     * @memberof ProfilingStartRequest
+     */
+    'duration_secs_by_type'?: { [key: string]: number; };
+    /**
+     * Durations which override DurationSecs with a task per duration for each target, e.g. both a 10 seconds and a 30 seconds CPU profile of a target to compare. They are ignored by snapshot profiling types, ProfilingTypeCustom and profiling types in DurationSecsByType, which still capture a single task.
+     * @type {Array<number>}
+     * @memberof ProfilingStartRequest
+     */
+    'duration_secs_list'?: Array<number>;
+    /**
+     * Labels of the task group, by which task groups can be filtered when listing.
+     * @type {{ [key: string]: string; }}
+     * @memberof ProfilingStartRequest
+     */
+    'labels'?: { [key: string]: string; };
+    /**
+     * Only profile the leader among the PD targets.
+     * @type {boolean}
+     * @memberof ProfilingStartRequest
+     */
+    'leader_only'?: boolean;
+    /**
+     * The maximum number of tasks profiling at the same time, to avoid disturbing a large cluster. All tasks are run at once when it is 0.
+     * @type {number}
+     * @memberof ProfilingStartRequest
+     */
+    'max_concurrency'?: number;
+    /**
+     * The mutex profile fraction set on TiDB targets while profiling mutex contentions, which are sampled during the profile duration. The fraction is reset to 0 afterwards. It is not changed when it is 0.
+     * @type {number}
+     * @memberof ProfilingStartRequest
+     */
+    'mutex_profile_fraction'?: number;
+    /**
+     * A free-text note about why the task group is captured.
+     * @type {string}
+     * @memberof ProfilingStartRequest
+     */
+    'note'?: string;
+    /**
+     * Profiling types of targets of a component kind, which override RequstedProfilingTypes for these targets. Without RequstedProfilingTypes, every kind of targets must have profiling types here.
+     * @type {{ [key: string]: Array<string>; }}
+     * @memberof ProfilingStartRequest
+     */
+    'profiling_types_by_kind'?: { [key: string]: Array<string>; };
+    /**
+     * Seconds allowed for fetching each profile beyond the profile duration, after which the task is failed. The configured default is used when it is 0.
+     * @type {number}
+     * @memberof ProfilingStartRequest
+     */
+    'request_timeout_secs'?: number;
     /**
      * 
+     * @type {Array<string>}
+     * @memberof ProfilingStartRequest
+     */
+    'requsted_profiling_types'?: Array<string>;
+    /**
+     * Run the task group again once if all of its tasks are failed, e.g. due to a transient cluster-wide failure.
+     * @type {boolean}
+     * @memberof ProfilingStartRequest
+     */
+    'retry_on_total_failure'?: boolean;
+    /**
+     * The maximum delay in milliseconds between launching two tasks, so that targets are not profiled at exactly the same time, which smooths the load of the cluster. The delay is randomized between half of it and itself. All tasks are launched at once when it is 0.
      * @type {number}
-     * @memberof ModelRequestTargetStatistics
+     * @memberof ProfilingStartRequest
      */
-    'num_tikv_nodes'?: number;
+    'stagger_ms'?: number;
+    /**
+     * 
+     * @type {Array<ModelRequestTargetNode>}
+     * @memberof ProfilingStartRequest
+     */
+    'targets'?: Array<ModelRequestTargetNode>;
+    /**
+     * TiKV stores to profile in addition to Targets, which are resolved to addresses by the cluster topology.
+     * @type {Array<number>}
+     * @memberof ProfilingStartRequest
+     */
+    'tikv_store_ids'?: Array<number>;
 }
 
 
@@ -1752,27 +2865,27 @@ export interface ModelRequestTargetStatistics {
 /**
  * 
  * @export
- * @interface ProfilingGroupDetailResponse
+ * @interface ProfilingTargetReachability
  */
-export interface ProfilingGroupDetailResponse {
+export interface ProfilingTargetReachability {
     /**
      * 
-     * @type {number}
-     * @memberof ProfilingGroupDetailResponse
+     * @type {string}
+     * @memberof ProfilingTargetReachability
      */
-    'server_time'?: number;
+    'error'?: string;
     /**
      * 
-     * @type {ProfilingTaskGroupModel}
-     * @memberof ProfilingGroupDetailResponse
+     * @type {boolean}
+     * @memberof ProfilingTargetReachability
      */
-    'task_group_status'?: ProfilingTaskGroupModel;
+    'reachable'?: boolean;
     /**
      * 
-     * @type {Array<ProfilingTaskModel>}
-     * @memberof ProfilingGroupDetailResponse
+     * @type {ModelRequestTargetNode}
+     * @memberof ProfilingTargetReachability
      */
-    'tasks_status'?: Array<ProfilingTaskModel>;
+    'target'?: ModelRequestTargetNode;
 }
 
 
@@ -1781,27 +2894,39 @@ export interface ProfilingGroupDetailResponse {
 /**
  * 
  * @export
- * @interface ProfilingStartRequest
+ * @interface ProfilingTaskGroupCommentModel
  */
-export interface ProfilingStartRequest {
+export interface ProfilingTaskGroupCommentModel {
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingTaskGroupCommentModel
+     */
+    'author'?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingTaskGroupCommentModel
+     */
+    'content'?: string;
     /**
      * 
      * @type {number}
-     * @memberof ProfilingStartRequest
+     * @memberof ProfilingTaskGroupCommentModel
      */
-    'duration_secs'?: number;
+    'created_at'?: number;
     /**
      * 
-     * @type {Array<string>}
-     * @memberof ProfilingStartRequest
+     * @type {number}
+     * @memberof ProfilingTaskGroupCommentModel
      */
-    'requsted_profiling_types'?: Array<string>;
+    'id'?: number;
     /**
      * 
-     * @type {Array<ModelRequestTargetNode>}
-     * @memberof ProfilingStartRequest
+     * @type {number}
+     * @memberof ProfilingTaskGroupCommentModel
      */
-    'targets'?: Array<ModelRequestTargetNode>;
+    'task_group_id'?: number;
 }
 
 
@@ -1813,12 +2938,30 @@ export interface ProfilingStartRequest {
  * @interface ProfilingTaskGroupModel
  */
 export interface ProfilingTaskGroupModel {
+    /**
+     * The campaign which the task group is an iteration of, or 0 if it is started alone.
+     * @type {number}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'campaign_id'?: number;
     /**
      * 
      * @type {number}
      * @memberof ProfilingTaskGroupModel
      */
     'id'?: number;
+    /**
+     * Labels given when the task group is started, which are stored in TaskGroupLabelModel and only filled in responses.
+     * @type {{ [key: string]: string; }}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'labels'?: { [key: string]: string; };
+    /**
+     * Why the task group is captured, given when it is started.
+     * @type {string}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'note'?: string;
     /**
      * 
      * @type {number}
@@ -1831,6 +2974,18 @@ export interface ProfilingTaskGroupModel {
      * @memberof ProfilingTaskGroupModel
      */
     'requsted_profiling_types'?: Array<string>;
+    /**
+     * The task group which is retried by this task group because all of its tasks are failed, or 0 if it is not a retry.
+     * @type {number}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'retry_of'?: number;
+    /**
+     * The schedule which started the task group, or 0 if it is not started by a schedule.
+     * @type {number}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'schedule_id'?: number;
     /**
      * 
      * @type {number}
@@ -1849,6 +3004,12 @@ export interface ProfilingTaskGroupModel {
      * @memberof ProfilingTaskGroupModel
      */
     'target_stats'?: ModelRequestTargetStatistics;
+    /**
+     * The display name of the user who started the task group, or empty if it is started automatically, e.g. by a schedule.
+     * @type {string}
+     * @memberof ProfilingTaskGroupModel
+     */
+    'triggered_by'?: string;
 }
 
 
@@ -1860,12 +3021,42 @@ export interface ProfilingTaskGroupModel {
  * @interface ProfilingTaskModel
  */
 export interface ProfilingTaskModel {
+    /**
+     * Starts from 1 and increases on each retry, so that a reset of StartedAt can be told apart from a stalled progress.
+     * @type {number}
+     * @memberof ProfilingTaskModel
+     */
+    'attempt'?: number;
+    /**
+     * The git hash of the target binary when profiled, which can be used to match symbols. Empty if not captured.
+     * @type {string}
+     * @memberof ProfilingTaskModel
+     */
+    'build_id'?: string;
+    /**
+     * The path with the query fetched from the target, only for ProfilingTypeCustom.
+     * @type {string}
+     * @memberof ProfilingTaskModel
+     */
+    'custom_path'?: string;
+    /**
+     * The profile duration of the task, which is ignored by snapshot profiling types. The duration of the task group is used when it is 0, e.g. for tasks saved by earlier versions.
+     * @type {number}
+     * @memberof ProfilingTaskModel
+     */
+    'duration_secs'?: number;
     /**
      * 
      * @type {string}
      * @memberof ProfilingTaskModel
      */
     'error'?: string;
+    /**
+     * The time when the task is stopped in any state, or 0 if it is still running or was interrupted by restart.
+     * @type {number}
+     * @memberof ProfilingTaskModel
+     */
+    'finished_at'?: number;
     /**
      * 
      * @type {number}
@@ -1878,6 +3069,12 @@ export interface ProfilingTaskModel {
      * @memberof ProfilingTaskModel
      */
     'profiling_type'?: string;
+    /**
+     * Estimated progress in range [0, 1]. It is not persisted and is only filled in responses.
+     * @type {number}
+     * @memberof ProfilingTaskModel
+     */
+    'progress'?: number;
     /**
      * 
      * @type {string}
@@ -1885,7 +3082,25 @@ export interface ProfilingTaskModel {
      */
     'raw_data_type'?: string;
     /**
-     * The start running time, reset when retry. Used to estimate approximate profiling progress.
+     * The address and the path with the query requested from the target, e.g. 10.0.0.1:10080/debug/pprof/heap, which is recorded for debugging. The scheme is decided by the TLS config of the component and is omitted.
+     * @type {string}
+     * @memberof ProfilingTaskModel
+     */
+    'request_url'?: string;
+    /**
+     * The size of the profile downloaded from the target in bytes, before it is compressed or encrypted. It is 0 unless the task is finished.
+     * @type {number}
+     * @memberof ProfilingTaskModel
+     */
+    'size_bytes'?: number;
+    /**
+     * Why the task is skipped. Only valid when the state is TaskStateSkipped.
+     * @type {string}
+     * @memberof ProfilingTaskModel
+     */
+    'skip_reason'?: string;
+    /**
+     * The start running time, reset when retry, or 0 if the task is waiting for a concurrency slot. Used to estimate approximate profiling progress.
      * @type {number}
      * @memberof ProfilingTaskModel
      */
@@ -1913,6 +3128,104 @@ export interface ProfilingTaskModel {
 
 
 
+/**
+ * 
+ * @export
+ * @interface ProfilingTaskProgress
+ */
+export interface ProfilingTaskProgress {
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTaskProgress
+     */
+    'id'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTaskProgress
+     */
+    'progress'?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTaskProgress
+     */
+    'state'?: number;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingTaskWithData
+ */
+export interface ProfilingTaskWithData {
+    /**
+     * The profiling result, which is empty unless the task is finished.
+     * @type {Array<number>}
+     * @memberof ProfilingTaskWithData
+     */
+    'data'?: Array<number>;
+    /**
+     * 
+     * @type {ProfilingTaskModel}
+     * @memberof ProfilingTaskWithData
+     */
+    'task'?: ProfilingTaskModel;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingTasksDataRequest
+ */
+export interface ProfilingTasksDataRequest {
+    /**
+     * 
+     * @type {Array<number>}
+     * @memberof ProfilingTasksDataRequest
+     */
+    'task_ids'?: Array<number>;
+}
+
+
+
+
+/**
+ * 
+ * @export
+ * @interface ProfilingTopFunction
+ */
+export interface ProfilingTopFunction {
+    /**
+     * 
+     * @type {number}
+     * @memberof ProfilingTopFunction
+     */
+    'flat'?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof ProfilingTopFunction
+     */
+    'function'?: string;
+    /**
+     * Share of the total value, in range [0, 1]
+     * @type {number}
+     * @memberof ProfilingTopFunction
+     */
+    'share'?: number;
+}
+
+
+
+
 /**
  * 
  * @export
@@ -45,6 +45,7 @@ type GetMembersResponseMember struct {
 
 type GetMembersResponse struct {
 	Members []GetMembersResponseMember `json:"members"`
+	Leader  *GetMembersResponseMember  `json:"leader"`
 }
 
 // GetMembers returns the content from /members PD API.
@@ -58,6 +58,10 @@ func TestAPIClient_GetMembers(t *testing.T) {
 				MemberID:      0xb7da90b338a3eab3,
 			},
 		},
+		Leader: &pdclient.GetMembersResponseMember{
+			ClientUrls: []string{"http://172.16.6.171:2379"},
+			MemberID:   0xb7da90b338a3eab3,
+		},
 	}, resp)
 }
 
@@ -22,6 +22,8 @@ type DistributionResource struct {
 	TiKV     string `json:"tikv,omitempty"`
 	PD       string `json:"pd,omitempty"`
 	TiFlash  string `json:"tiflash,omitempty"`
+	TiProxy  string `json:"tiproxy,omitempty"`
+	TiCDC    string `json:"ticdc,omitempty"`
 }
 
 var defaultDistroRes = DistributionResource{
@@ -30,6 +32,8 @@ var defaultDistroRes = DistributionResource{
 	TiKV:     "TiKV",
 	PD:       "PD",
 	TiFlash:  "TiFlash",
+	TiProxy:  "TiProxy",
+	TiCDC:    "TiCDC",
 }
 
 var (
@@ -82,6 +82,52 @@ func (_m *MockTopologyProvider) GetPD(ctx context.Context) ([]PDInfo, error) {
 	return r0, r1
 }
 
+// GetPDScheduling provides a mock function with given fields: ctx
+func (_m *MockTopologyProvider) GetPDScheduling(ctx context.Context) ([]PDSchedulingInfo, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []PDSchedulingInfo
+	if rf, ok := ret.Get(0).(func(context.Context) []PDSchedulingInfo); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]PDSchedulingInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPDTSO provides a mock function with given fields: ctx
+func (_m *MockTopologyProvider) GetPDTSO(ctx context.Context) ([]PDTSOInfo, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []PDTSOInfo
+	if rf, ok := ret.Get(0).(func(context.Context) []PDTSOInfo); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]PDTSOInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPrometheus provides a mock function with given fields: ctx
 func (_m *MockTopologyProvider) GetPrometheus(ctx context.Context) (*PrometheusInfo, error) {
 	ret := _m.Called(ctx)
@@ -105,6 +151,29 @@ func (_m *MockTopologyProvider) GetPrometheus(ctx context.Context) (*PrometheusI
 	return r0, r1
 }
 
+// GetTiCDC provides a mock function with given fields: ctx
+func (_m *MockTopologyProvider) GetTiCDC(ctx context.Context) ([]TiCDCInfo, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []TiCDCInfo
+	if rf, ok := ret.Get(0).(func(context.Context) []TiCDCInfo); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]TiCDCInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTiDB provides a mock function with given fields: ctx
 func (_m *MockTopologyProvider) GetTiDB(ctx context.Context) ([]TiDBInfo, error) {
 	ret := _m.Called(ctx)
@@ -173,3 +242,26 @@ func (_m *MockTopologyProvider) GetTiKV(ctx context.Context) ([]TiKVStoreInfo, e
 
 	return r0, r1
 }
+
+// GetTiProxy provides a mock function with given fields: ctx
+func (_m *MockTopologyProvider) GetTiProxy(ctx context.Context) ([]TiProxyInfo, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []TiProxyInfo
+	if rf, ok := ret.Get(0).(func(context.Context) []TiProxyInfo); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]TiProxyInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
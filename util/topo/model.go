@@ -23,6 +23,12 @@ const (
 	KindAlertManager Kind = "alert_manager"
 	KindGrafana      Kind = "grafana"
 	KindPrometheus   Kind = "prometheus"
+	KindTiProxy      Kind = "tiproxy"
+	KindTiCDC        Kind = "ticdc"
+	// KindPDTSO is the TSO micro-service of PD, which is deployed separately from PD.
+	KindPDTSO Kind = "tso"
+	// KindPDScheduling is the scheduling micro-service of PD, which is deployed separately from PD.
+	KindPDScheduling Kind = "scheduling"
 )
 
 type PDInfo struct {
@@ -33,6 +39,7 @@ type PDInfo struct {
 	DeployPath     string
 	Status         CompStatus
 	StartTimestamp int64 // Ts = 0 means unknown
+	IsLeader       bool
 }
 
 var _ Info = &PDInfo{}
@@ -75,8 +82,110 @@ func (i *TiDBInfo) Info() CompInfo {
 	}
 }
 
+// TiProxyInfo is the information of a TiProxy instance, which registers itself in the same way as TiDB.
+type TiProxyInfo struct {
+	GitHash        string
+	Version        string
+	IP             string
+	Port           uint
+	DeployPath     string
+	Status         CompStatus
+	StatusPort     uint
+	StartTimestamp int64
+}
+
+var _ Info = &TiProxyInfo{}
+
+func (i *TiProxyInfo) Info() CompInfo {
+	return CompInfo{
+		CompDescriptor: CompDescriptor{
+			IP:         i.IP,
+			Port:       i.Port,
+			StatusPort: i.StatusPort,
+			Kind:       KindTiProxy,
+		},
+		Version: i.Version,
+		Status:  i.Status,
+	}
+}
+
+// TiCDCInfo is the information of a TiCDC capture.
+type TiCDCInfo struct {
+	ID      string
+	Version string
+	IP      string
+	Port    uint
+	Status  CompStatus
+}
+
+var _ Info = &TiCDCInfo{}
+
+func (i *TiCDCInfo) Info() CompInfo {
+	return CompInfo{
+		CompDescriptor: CompDescriptor{
+			IP:   i.IP,
+			Port: i.Port,
+			// TiCDC serves the status API and profiles on the same port.
+			StatusPort: i.Port,
+			Kind:       KindTiCDC,
+		},
+		Version: i.Version,
+		Status:  i.Status,
+	}
+}
+
+// PDServiceInfo may be either a TSO server info or a scheduling server info, which are PD micro-services
+// deployed separately from PD.
+type PDServiceInfo struct {
+	Name           string
+	GitHash        string
+	Version        string
+	IP             string
+	Port           uint
+	DeployPath     string
+	Status         CompStatus
+	StartTimestamp int64
+}
+
+type PDTSOInfo PDServiceInfo
+
+var _ Info = &PDTSOInfo{}
+
+func (i *PDTSOInfo) Info() CompInfo {
+	return CompInfo{
+		CompDescriptor: CompDescriptor{
+			IP:   i.IP,
+			Port: i.Port,
+			// PD micro-services serve the status API and profiles on the same port.
+			StatusPort: i.Port,
+			Kind:       KindPDTSO,
+		},
+		Version: i.Version,
+		Status:  i.Status,
+	}
+}
+
+type PDSchedulingInfo PDServiceInfo
+
+var _ Info = &PDSchedulingInfo{}
+
+func (i *PDSchedulingInfo) Info() CompInfo {
+	return CompInfo{
+		CompDescriptor: CompDescriptor{
+			IP:   i.IP,
+			Port: i.Port,
+			// PD micro-services serve the status API and profiles on the same port.
+			StatusPort: i.Port,
+			Kind:       KindPDScheduling,
+		},
+		Version: i.Version,
+		Status:  i.Status,
+	}
+}
+
 // StoreInfo may be either a TiKV store info or a TiFlash store info.
 type StoreInfo struct {
+	ID             uint64
 	GitHash        string
 	Version        string
 	IP             string
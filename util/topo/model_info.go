@@ -65,6 +65,46 @@ func GetInfoByKind(ctx context.Context, p TopologyProvider, kind Kind) ([]CompIn
 			result = append(result, info.Info())
 		}
 		return result, nil
+	case KindTiProxy:
+		v, err := p.GetTiProxy(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]CompInfo, 0, len(v))
+		for _, info := range v {
+			result = append(result, info.Info())
+		}
+		return result, nil
+	case KindTiCDC:
+		v, err := p.GetTiCDC(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]CompInfo, 0, len(v))
+		for _, info := range v {
+			result = append(result, info.Info())
+		}
+		return result, nil
+	case KindPDTSO:
+		v, err := p.GetPDTSO(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]CompInfo, 0, len(v))
+		for _, info := range v {
+			result = append(result, info.Info())
+		}
+		return result, nil
+	case KindPDScheduling:
+		v, err := p.GetPDScheduling(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]CompInfo, 0, len(v))
+		for _, info := range v {
+			result = append(result, info.Info())
+		}
+		return result, nil
 	case KindAlertManager:
 		v, err := p.GetAlertManager(ctx)
 		if err != nil {
@@ -32,6 +32,7 @@ func GetPDInstances(ctx context.Context, pdAPI *pdclient.APIClient) ([]topo.PDIn
 		}
 	}
 
+	leader := ds.Leader
 	nodes := make([]topo.PDInfo, 0)
 
 	for _, ds := range ds.Members {
@@ -63,6 +64,7 @@ func GetPDInstances(ctx context.Context, pdAPI *pdclient.APIClient) ([]topo.PDIn
 			DeployPath:     ds.DeployPath,
 			Status:         storeStatus,
 			StartTimestamp: tsResp.StartTimestamp,
+			IsLeader:       leader != nil && leader.MemberID == ds.MemberID,
 		})
 	}
 
@@ -0,0 +1,123 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package pdtopo
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/ozonru/etcd/v3/clientv3"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tidb-dashboard/util/distro"
+	"github.com/pingcap/tidb-dashboard/util/netutil"
+	"github.com/pingcap/tidb-dashboard/util/topo"
+)
+
+// PD micro-services are registered in `/ms/{cluster_id}/{service}/registry/{service_addr}`.
+const (
+	pdServiceTopologyKeyPrefix  = "/ms/"
+	pdServiceRegistryKeySegment = "/registry/"
+
+	pdServiceTSO        = "tso"
+	pdServiceScheduling = "scheduling"
+)
+
+func GetPDTSOInstances(ctx context.Context, etcdClient *clientv3.Client) ([]topo.PDTSOInfo, error) {
+	infos, err := getPDServiceInstances(ctx, etcdClient, pdServiceTSO)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]topo.PDTSOInfo, 0, len(infos))
+	for _, info := range infos {
+		nodes = append(nodes, topo.PDTSOInfo(info))
+	}
+	return nodes, nil
+}
+
+func GetPDSchedulingInstances(ctx context.Context, etcdClient *clientv3.Client) ([]topo.PDSchedulingInfo, error) {
+	infos, err := getPDServiceInstances(ctx, etcdClient, pdServiceScheduling)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]topo.PDSchedulingInfo, 0, len(infos))
+	for _, info := range infos {
+		nodes = append(nodes, topo.PDSchedulingInfo(info))
+	}
+	return nodes, nil
+}
+
+func getPDServiceInstances(ctx context.Context, etcdClient *clientv3.Client, service string) ([]topo.PDServiceInfo, error) {
+	resp, err := etcdClient.Get(ctx, pdServiceTopologyKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, ErrEtcdRequestFailed.Wrap(err, "Failed to read topology from etcd key `%s`", pdServiceTopologyKeyPrefix)
+	}
+
+	segment := "/" + service + pdServiceRegistryKeySegment
+	nodes := make([]topo.PDServiceInfo, 0)
+	for _, kv := range resp.Kvs {
+		key := string(kv.Key)
+		if !strings.Contains(key, segment) {
+			continue
+		}
+		node, err := parsePDServiceInfo(kv.Value)
+		if err != nil {
+			log.Warn("Ignored invalid topology info entry",
+				zap.String("component", distro.R().PD),
+				zap.String("service", service),
+				zap.String("key", key),
+				zap.String("value", string(kv.Value)),
+				zap.Error(err))
+			continue
+		}
+		nodes = append(nodes, *node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].IP < nodes[j].IP {
+			return true
+		}
+		if nodes[i].IP > nodes[j].IP {
+			return false
+		}
+		return nodes[i].Port < nodes[j].Port
+	})
+
+	return nodes, nil
+}
+
+// parsePDServiceInfo parses the registry entry of a PD micro-service. A server is alive as long as its key exists,
+// since the key is bound to the lease of the server.
+func parsePDServiceInfo(value []byte) (*topo.PDServiceInfo, error) {
+	ds := struct {
+		Name           string `json:"name"`
+		ServiceAddr    string `json:"service-addr"`
+		Version        string `json:"version"`
+		GitHash        string `json:"git-hash"`
+		DeployPath     string `json:"deploy-path"`
+		StartTimestamp int64  `json:"start-timestamp"`
+	}{}
+
+	err := json.Unmarshal(value, &ds)
+	if err != nil {
+		return nil, ErrInvalidTopologyData.Wrap(err, "Read topology value failed")
+	}
+	hostname, port, err := netutil.ParseHostAndPortFromAddressURL(ds.ServiceAddr)
+	if err != nil {
+		return nil, ErrInvalidTopologyData.Wrap(err, "Read topology address failed")
+	}
+
+	return &topo.PDServiceInfo{
+		Name:           ds.Name,
+		GitHash:        ds.GitHash,
+		Version:        ds.Version,
+		IP:             hostname,
+		Port:           port,
+		DeployPath:     ds.DeployPath,
+		Status:         topo.CompStatusUp,
+		StartTimestamp: ds.StartTimestamp,
+	}, nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package pdtopo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/util/topo"
+)
+
+func TestParsePDServiceInfo(t *testing.T) {
+	info, err := parsePDServiceInfo([]byte(`{"service-addr":"http://172.16.5.141:3379","version":"v8.0.0","git-hash":"abc123","deploy-path":"/deploy/tso","start-timestamp":1700000000,"name":"tso-1"}`))
+	require.NoError(t, err)
+	require.Equal(t, &topo.PDServiceInfo{
+		Name:           "tso-1",
+		GitHash:        "abc123",
+		Version:        "v8.0.0",
+		IP:             "172.16.5.141",
+		Port:           3379,
+		DeployPath:     "/deploy/tso",
+		Status:         topo.CompStatusUp,
+		StartTimestamp: 1700000000,
+	}, info)
+	tso := topo.PDTSOInfo(*info)
+	require.Equal(t, uint(3379), tso.Info().StatusPort)
+	require.Equal(t, topo.KindPDTSO, tso.Info().Kind)
+
+	_, err = parsePDServiceInfo([]byte(`{"service-addr":"invalid"}`))
+	require.Error(t, err)
+	_, err = parsePDServiceInfo([]byte(`not json`))
+	require.Error(t, err)
+}
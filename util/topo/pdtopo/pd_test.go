@@ -44,6 +44,7 @@ func TestGetPDInstances(t *testing.T) {
 			DeployPath:     "/home/tidb/tidb-deploy/pd-2379/bin",
 			Status:         topo.CompStatusUp,
 			StartTimestamp: 1635762685,
+			IsLeader:       true,
 		},
 	}, resp)
 }
@@ -52,6 +52,22 @@ func (p *TopologyFromPD) GetTiFlash(ctx context.Context) ([]topo.TiFlashStoreInf
 	return tiFlashStores, nil
 }
 
+func (p *TopologyFromPD) GetTiProxy(ctx context.Context) ([]topo.TiProxyInfo, error) {
+	return GetTiProxyInstances(ctx, p.etcdClient)
+}
+
+func (p *TopologyFromPD) GetTiCDC(ctx context.Context) ([]topo.TiCDCInfo, error) {
+	return GetTiCDCInstances(ctx, p.etcdClient)
+}
+
+func (p *TopologyFromPD) GetPDTSO(ctx context.Context) ([]topo.PDTSOInfo, error) {
+	return GetPDTSOInstances(ctx, p.etcdClient)
+}
+
+func (p *TopologyFromPD) GetPDScheduling(ctx context.Context) ([]topo.PDSchedulingInfo, error) {
+	return GetPDSchedulingInstances(ctx, p.etcdClient)
+}
+
 func (p *TopologyFromPD) GetPrometheus(ctx context.Context) (*topo.PrometheusInfo, error) {
 	return GetPrometheusInstance(ctx, p.etcdClient)
 }
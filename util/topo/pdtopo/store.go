@@ -72,6 +72,7 @@ func buildStoreTopology(stores []pdclient.GetStoresResponseStore) []topo.StoreIn
 			version = "v" + version
 		}
 		node := topo.StoreInfo{
+			ID:             uint64(v.ID),
 			Version:        version,
 			IP:             hostname,
 			Port:           port,
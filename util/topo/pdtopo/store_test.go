@@ -19,6 +19,7 @@ func TestGetStoreInstances(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, []topo.TiKVStoreInfo{
 		{
+			ID:             1,
 			GitHash:        "d7dc4fff51ca71c76a928a0780a069efaaeaae70",
 			Version:        "v4.0.14",
 			IP:             "172.16.5.141",
@@ -30,6 +31,7 @@ func TestGetStoreInstances(t *testing.T) {
 			StartTimestamp: 1636421301,
 		},
 		{
+			ID:             5,
 			GitHash:        "d7dc4fff51ca71c76a928a0780a069efaaeaae70",
 			Version:        "v4.0.14",
 			IP:             "172.16.5.218",
@@ -41,6 +43,7 @@ func TestGetStoreInstances(t *testing.T) {
 			StartTimestamp: 1636421304,
 		},
 		{
+			ID:             4,
 			GitHash:        "d7dc4fff51ca71c76a928a0780a069efaaeaae70",
 			Version:        "v4.0.14",
 			IP:             "172.16.6.168",
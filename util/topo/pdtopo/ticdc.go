@@ -0,0 +1,89 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package pdtopo
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/ozonru/etcd/v3/clientv3"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tidb-dashboard/util/distro"
+	"github.com/pingcap/tidb-dashboard/util/netutil"
+	"github.com/pingcap/tidb-dashboard/util/topo"
+)
+
+// TiCDC captures are registered in `/tidb/cdc/capture/{id}` before v6.2,
+// and in `/tidb/cdc/{cluster}/__cdc_meta__/capture/{id}` since v6.2.
+const (
+	ticdcTopologyKeyPrefix = "/tidb/cdc/"
+	ticdcCaptureKeySegment = "/capture/"
+)
+
+func GetTiCDCInstances(ctx context.Context, etcdClient *clientv3.Client) ([]topo.TiCDCInfo, error) {
+	resp, err := etcdClient.Get(ctx, ticdcTopologyKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, ErrEtcdRequestFailed.Wrap(err, "Failed to read topology from etcd key `%s`", ticdcTopologyKeyPrefix)
+	}
+
+	nodes := make([]topo.TiCDCInfo, 0)
+	for _, kv := range resp.Kvs {
+		key := string(kv.Key)
+		if !strings.Contains(key, ticdcCaptureKeySegment) {
+			continue
+		}
+		node, err := parseTiCDCInfo(kv.Value)
+		if err != nil {
+			log.Warn("Ignored invalid topology info entry",
+				zap.String("component", distro.R().TiCDC),
+				zap.String("key", key),
+				zap.String("value", string(kv.Value)),
+				zap.Error(err))
+			continue
+		}
+		nodes = append(nodes, *node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].IP < nodes[j].IP {
+			return true
+		}
+		if nodes[i].IP > nodes[j].IP {
+			return false
+		}
+		return nodes[i].Port < nodes[j].Port
+	})
+
+	return nodes, nil
+}
+
+// parseTiCDCInfo parses the capture info. A capture is alive as long as its key exists, since the key is
+// bound to the lease of the capture.
+func parseTiCDCInfo(value []byte) (*topo.TiCDCInfo, error) {
+	ds := struct {
+		ID      string `json:"id"`
+		Address string `json:"address"`
+		Version string `json:"version"`
+	}{}
+
+	err := json.Unmarshal(value, &ds)
+	if err != nil {
+		return nil, ErrInvalidTopologyData.Wrap(err, "Read topology value failed")
+	}
+	hostname, port, err := netutil.ParseHostAndPortFromAddress(ds.Address)
+	if err != nil {
+		return nil, ErrInvalidTopologyData.Wrap(err, "Read topology address failed")
+	}
+
+	return &topo.TiCDCInfo{
+		ID:      ds.ID,
+		Version: ds.Version,
+		IP:      hostname,
+		Port:    port,
+		Status:  topo.CompStatusUp,
+	}, nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package pdtopo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tidb-dashboard/util/topo"
+)
+
+func TestParseTiCDCInfo(t *testing.T) {
+	info, err := parseTiCDCInfo([]byte(`{"id":"5f5e4b3a-1c2d-4e5f-8a9b-0c1d2e3f4a5b","address":"172.16.5.141:8300","version":"v6.1.0"}`))
+	require.NoError(t, err)
+	require.Equal(t, &topo.TiCDCInfo{
+		ID:      "5f5e4b3a-1c2d-4e5f-8a9b-0c1d2e3f4a5b",
+		Version: "v6.1.0",
+		IP:      "172.16.5.141",
+		Port:    8300,
+		Status:  topo.CompStatusUp,
+	}, info)
+	require.Equal(t, uint(8300), info.Info().StatusPort)
+
+	_, err = parseTiCDCInfo([]byte(`{"id":"1","address":"invalid"}`))
+	require.Error(t, err)
+	_, err = parseTiCDCInfo([]byte(`not json`))
+	require.Error(t, err)
+}
@@ -22,9 +22,15 @@ import (
 const tidbTopologyKeyPrefix = "/topology/tidb/"
 
 func GetTiDBInstances(ctx context.Context, etcdClient *clientv3.Client) ([]topo.TiDBInfo, error) {
-	resp, err := etcdClient.Get(ctx, tidbTopologyKeyPrefix, clientv3.WithPrefix())
+	return getTiDBStyleInstances(ctx, etcdClient, tidbTopologyKeyPrefix, distro.R().TiDB)
+}
+
+// getTiDBStyleInstances reads instances of components registering themselves like TiDB, i.e. the info and the TTL
+// of each instance are written in `{keyPrefix}{ip:port}/info` and `{keyPrefix}{ip:port}/ttl`.
+func getTiDBStyleInstances(ctx context.Context, etcdClient *clientv3.Client, keyPrefix string, componentName string) ([]topo.TiDBInfo, error) {
+	resp, err := etcdClient.Get(ctx, keyPrefix, clientv3.WithPrefix())
 	if err != nil {
-		return nil, ErrEtcdRequestFailed.Wrap(err, "Failed to read topology from etcd key `%s`", tidbTopologyKeyPrefix)
+		return nil, ErrEtcdRequestFailed.Wrap(err, "Failed to read topology from etcd key `%s`", keyPrefix)
 	}
 
 	nodesAlive := make(map[string]struct{}, len(resp.Kvs))
@@ -32,15 +38,15 @@ func GetTiDBInstances(ctx context.Context, etcdClient *clientv3.Client) ([]topo.
 
 	for _, kv := range resp.Kvs {
 		key := string(kv.Key)
-		if !strings.HasPrefix(key, tidbTopologyKeyPrefix) {
+		if !strings.HasPrefix(key, keyPrefix) {
 			continue
 		}
 		// remainingKey looks like `ip:port/info` or `ip:port/ttl`.
-		remainingKey := key[len(tidbTopologyKeyPrefix):]
+		remainingKey := key[len(keyPrefix):]
 		keyParts := strings.Split(remainingKey, "/")
 		if len(keyParts) != 2 {
 			log.Warn("Ignored invalid topology key",
-				zap.String("component", distro.R().TiDB),
+				zap.String("component", componentName),
 				zap.String("key", key))
 			continue
 		}
@@ -52,7 +58,7 @@ func GetTiDBInstances(ctx context.Context, etcdClient *clientv3.Client) ([]topo.
 				nodesInfo[keyParts[0]] = node
 			} else {
 				log.Warn("Ignored invalid topology info entry",
-					zap.String("component", distro.R().TiDB),
+					zap.String("component", componentName),
 					zap.String("key", key),
 					zap.String("value", string(kv.Value)),
 					zap.Error(err))
@@ -63,13 +69,13 @@ func GetTiDBInstances(ctx context.Context, etcdClient *clientv3.Client) ([]topo.
 				nodesAlive[keyParts[0]] = struct{}{}
 				if !alive {
 					log.Warn("Component alive TTL has expired (maybe local time are not synchronized)",
-						zap.String("component", distro.R().TiDB),
+						zap.String("component", componentName),
 						zap.String("key", key),
 						zap.String("value", string(kv.Value)))
 				}
 			} else {
 				log.Warn("Ignored invalid topology TTL entry",
-					zap.String("component", distro.R().TiDB),
+					zap.String("component", componentName),
 					zap.String("key", key),
 					zap.String("value", string(kv.Value)),
 					zap.Error(err))
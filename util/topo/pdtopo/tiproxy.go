@@ -0,0 +1,26 @@
+// Copyright 2022 PingCAP, Inc. Licensed under Apache-2.0.
+
+package pdtopo
+
+import (
+	"context"
+
+	"github.com/ozonru/etcd/v3/clientv3"
+
+	"github.com/pingcap/tidb-dashboard/util/distro"
+	"github.com/pingcap/tidb-dashboard/util/topo"
+)
+
+const tiproxyTopologyKeyPrefix = "/topology/tiproxy/"
+
+func GetTiProxyInstances(ctx context.Context, etcdClient *clientv3.Client) ([]topo.TiProxyInfo, error) {
+	infos, err := getTiDBStyleInstances(ctx, etcdClient, tiproxyTopologyKeyPrefix, distro.R().TiProxy)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]topo.TiProxyInfo, 0, len(infos))
+	for _, info := range infos {
+		nodes = append(nodes, topo.TiProxyInfo(info))
+	}
+	return nodes, nil
+}
@@ -12,6 +12,10 @@ type TopologyProvider interface {
 	GetTiDB(ctx context.Context) ([]TiDBInfo, error)
 	GetTiKV(ctx context.Context) ([]TiKVStoreInfo, error)
 	GetTiFlash(ctx context.Context) ([]TiFlashStoreInfo, error)
+	GetTiProxy(ctx context.Context) ([]TiProxyInfo, error)
+	GetTiCDC(ctx context.Context) ([]TiCDCInfo, error)
+	GetPDTSO(ctx context.Context) ([]PDTSOInfo, error)
+	GetPDScheduling(ctx context.Context) ([]PDSchedulingInfo, error)
 	GetPrometheus(ctx context.Context) (*PrometheusInfo, error)
 	GetGrafana(ctx context.Context) (*GrafanaInfo, error)
 	GetAlertManager(ctx context.Context) (*AlertManagerInfo, error)
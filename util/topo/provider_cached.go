@@ -88,6 +88,46 @@ func (c *CachedTopology) GetTiFlash(ctx context.Context) ([]TiFlashStoreInfo, er
 	return v.([]TiFlashStoreInfo), nil
 }
 
+func (c *CachedTopology) GetTiProxy(ctx context.Context) ([]TiProxyInfo, error) {
+	v, err := c.getOrFillCache("tiproxy", func() (interface{}, error) {
+		return c.p.GetTiProxy(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]TiProxyInfo), nil
+}
+
+func (c *CachedTopology) GetTiCDC(ctx context.Context) ([]TiCDCInfo, error) {
+	v, err := c.getOrFillCache("ticdc", func() (interface{}, error) {
+		return c.p.GetTiCDC(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]TiCDCInfo), nil
+}
+
+func (c *CachedTopology) GetPDTSO(ctx context.Context) ([]PDTSOInfo, error) {
+	v, err := c.getOrFillCache("pd_tso", func() (interface{}, error) {
+		return c.p.GetPDTSO(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]PDTSOInfo), nil
+}
+
+func (c *CachedTopology) GetPDScheduling(ctx context.Context) ([]PDSchedulingInfo, error) {
+	v, err := c.getOrFillCache("pd_scheduling", func() (interface{}, error) {
+		return c.p.GetPDScheduling(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]PDSchedulingInfo), nil
+}
+
 func (c *CachedTopology) GetPrometheus(ctx context.Context) (*PrometheusInfo, error) {
 	v, err := c.getOrFillCache("prometheus", func() (interface{}, error) {
 		return c.p.GetPrometheus(ctx)